@@ -23,6 +23,14 @@ const (
 	AdminAuthScopes  = "AdminAuth.Scopes"
 )
 
+// Defines values for ErrorSchemaWeakPasswordReasons.
+const (
+	Characters     ErrorSchemaWeakPasswordReasons = "characters"
+	Entropy        ErrorSchemaWeakPasswordReasons = "entropy"
+	Leaked         ErrorSchemaWeakPasswordReasons = "leaked"
+	UserIdentifier ErrorSchemaWeakPasswordReasons = "user_identifier"
+)
+
 // Defines values for PostAdminSsoProvidersJSONBodyType.
 const (
 	Saml PostAdminSsoProvidersJSONBodyType = "saml"
@@ -37,6 +45,28 @@ const (
 	Signup             PostGenerateLinkJSONBodyType = "signup"
 )
 
+// AdminUserParamsSchema defines model for AdminUserParamsSchema.
+type AdminUserParamsSchema struct {
+	AppMetadata *map[string]interface{} `json:"app_metadata,omitempty"`
+	Aud         *string                 `json:"aud,omitempty"`
+
+	// BanDuration A duration like `24h` to ban the user for, or `none` to lift a ban.
+	BanDuration  *string              `json:"ban_duration,omitempty"`
+	Email        *openapi_types.Email `json:"email,omitempty"`
+	EmailConfirm *bool                `json:"email_confirm,omitempty"`
+
+	// MfaRequired Requires the user to use MFA, in addition to the users required to by `MFA_ENFORCEMENT_ROLES` and `MFA_ENFORCEMENT_AUDIENCES`.
+	MfaRequired *bool   `json:"mfa_required,omitempty"`
+	Password    *string `json:"password,omitempty"`
+
+	// PasswordHash A password hash generated elsewhere, instead of a `password`. Supported are bcrypt, argon2id and scrypt hashes in the PHC string format, argon2i, Firebase scrypt (`$fbscrypt$v=1,n=,r=,p=,ss=,sk=$<salt>$<hash>`) and Django `pbkdf2_sha256` hashes. The password is hashed again with the configured algorithm when the user signs in with it.
+	PasswordHash *string                 `json:"password_hash,omitempty"`
+	Phone        *string                 `json:"phone,omitempty"`
+	PhoneConfirm *bool                   `json:"phone_confirm,omitempty"`
+	Role         *string                 `json:"role,omitempty"`
+	UserMetadata *map[string]interface{} `json:"user_metadata,omitempty"`
+}
+
 // ErrorSchema defines model for ErrorSchema.
 type ErrorSchema struct {
 	// Code The HTTP status code. Usually missing if `error` is present.
@@ -58,12 +88,22 @@ type ErrorSchema struct {
 
 	// Msg A basic message describing the problem with the request. Usually missing if `error` is present.
 	Msg *string `json:"msg,omitempty"`
+
+	// WeakPassword Present when a password was rejected by the password policy.
+	WeakPassword *struct {
+		Reasons *[]ErrorSchemaWeakPasswordReasons `json:"reasons,omitempty"`
+	} `json:"weak_password,omitempty"`
 }
 
+// ErrorSchemaWeakPasswordReasons defines model for ErrorSchema.WeakPassword.Reasons.
+type ErrorSchemaWeakPasswordReasons string
+
 // MFAFactorSchema Represents a MFA factor.
 type MFAFactorSchema struct {
 	// FactorType Usually one of:
 	// - totp
+	// - webauthn
+	// - phone
 	FactorType   *string             `json:"factor_type,omitempty"`
 	FriendlyName *string             `json:"friendly_name,omitempty"`
 	Id           *openapi_types.UUID `json:"id,omitempty"`
@@ -81,8 +121,9 @@ type SAMLAttributeMappingSchema struct {
 
 // SSOProviderSchema defines model for SSOProviderSchema.
 type SSOProviderSchema struct {
-	Id   *openapi_types.UUID `json:"id,omitempty"`
-	Saml *struct {
+	Id          *openapi_types.UUID `json:"id,omitempty"`
+	MfaRequired *bool               `json:"mfa_required,omitempty"`
+	Saml        *struct {
 		AttributeMapping *SAMLAttributeMappingSchema `json:"attribute_mapping,omitempty"`
 		EntityId         *string                     `json:"entity_id,omitempty"`
 		MetadataUrl      *string                     `json:"metadata_url,omitempty"`
@@ -93,6 +134,33 @@ type SSOProviderSchema struct {
 	} `json:"sso_domains,omitempty"`
 }
 
+// ServiceAccountSchema defines model for ServiceAccountSchema.
+type ServiceAccountSchema struct {
+	// ClientSecret Only returned when the service account is created or its secret is regenerated.
+	ClientSecret *string             `json:"client_secret,omitempty"`
+	CreatedAt    *time.Time          `json:"created_at,omitempty"`
+	ExpiresAt    *time.Time          `json:"expires_at,omitempty"`
+	Id           *openapi_types.UUID `json:"id,omitempty"`
+	Name         *string             `json:"name,omitempty"`
+	Role         *string             `json:"role,omitempty"`
+	Scopes       *[]string           `json:"scopes,omitempty"`
+	UpdatedAt    *time.Time          `json:"updated_at,omitempty"`
+}
+
+// SessionSchema Represents a session of a user.
+type SessionSchema struct {
+	Aal           *string             `json:"aal,omitempty"`
+	CreatedAt     *time.Time          `json:"created_at,omitempty"`
+	Current       *bool               `json:"current,omitempty"`
+	Id            *openapi_types.UUID `json:"id,omitempty"`
+	Ip            *string             `json:"ip,omitempty"`
+	NotAfter      *time.Time          `json:"not_after,omitempty"`
+	OauthClientId *openapi_types.UUID `json:"oauth_client_id,omitempty"`
+	RefreshedAt   *time.Time          `json:"refreshed_at,omitempty"`
+	UpdatedAt     *time.Time          `json:"updated_at,omitempty"`
+	UserAgent     *string             `json:"user_agent,omitempty"`
+}
+
 // UserSchema Object describing the user related to the issued access and refresh tokens.
 type UserSchema struct {
 	AppMetadata        *map[string]interface{} `json:"app_metadata,omitempty"`
@@ -111,8 +179,11 @@ type UserSchema struct {
 	Id                *openapi_types.UUID       `json:"id,omitempty"`
 	Identities        *[]map[string]interface{} `json:"identities,omitempty"`
 	LastSignInAt      *time.Time                `json:"last_sign_in_at,omitempty"`
-	NewEmail          *openapi_types.Email      `json:"new_email,omitempty"`
-	NewPhone          *string                   `json:"new_phone,omitempty"`
+
+	// MfaRequired Set by an admin to require the user to use MFA, in addition to the users required to by `MFA_ENFORCEMENT_ROLES` and `MFA_ENFORCEMENT_AUDIENCES`.
+	MfaRequired *bool                `json:"mfa_required,omitempty"`
+	NewEmail    *openapi_types.Email `json:"new_email,omitempty"`
+	NewPhone    *string              `json:"new_phone,omitempty"`
 
 	// Phone User's primary contact phone number. In most cases you can uniquely identify a user by their phone number, but not in all cases.
 	Phone                  *string                 `json:"phone,omitempty"`
@@ -140,13 +211,36 @@ type GetAdminAuditParams struct {
 	PerPage *int `form:"per_page,omitempty" json:"per_page,omitempty"`
 }
 
+// PostAdminServiceAccountsJSONBody defines parameters for PostAdminServiceAccounts.
+type PostAdminServiceAccountsJSONBody struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+
+	// Role Role of the access tokens issued to the service account. Cannot be one of the admin roles.
+	Role   string    `json:"role"`
+	Scopes *[]string `json:"scopes,omitempty"`
+}
+
+// PutAdminServiceAccountsServiceAccountIdJSONBody defines parameters for PutAdminServiceAccountsServiceAccountId.
+type PutAdminServiceAccountsServiceAccountIdJSONBody struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+
+	// Role Role of the access tokens issued to the service account. Cannot be one of the admin roles.
+	Role   *string   `json:"role,omitempty"`
+	Scopes *[]string `json:"scopes,omitempty"`
+}
+
 // PostAdminSsoProvidersJSONBody defines parameters for PostAdminSsoProviders.
 type PostAdminSsoProvidersJSONBody struct {
-	AttributeMapping *SAMLAttributeMappingSchema       `json:"attribute_mapping,omitempty"`
-	Domains          *[]string                         `json:"domains,omitempty"`
-	MetadataUrl      *string                           `json:"metadata_url,omitempty"`
-	MetadataXml      *string                           `json:"metadata_xml,omitempty"`
-	Type             PostAdminSsoProvidersJSONBodyType `json:"type"`
+	AttributeMapping *SAMLAttributeMappingSchema `json:"attribute_mapping,omitempty"`
+	Domains          *[]string                   `json:"domains,omitempty"`
+	MetadataUrl      *string                     `json:"metadata_url,omitempty"`
+	MetadataXml      *string                     `json:"metadata_xml,omitempty"`
+
+	// MfaRequired Requires the users of the provider to use MFA, for identity providers that don't enforce it themselves.
+	MfaRequired *bool                             `json:"mfa_required,omitempty"`
+	Type        PostAdminSsoProvidersJSONBodyType `json:"type"`
 }
 
 // PostAdminSsoProvidersJSONBodyType defines parameters for PostAdminSsoProviders.
@@ -158,6 +252,9 @@ type PutAdminSsoProvidersSsoProviderIdJSONBody struct {
 	Domains          *[]string                   `json:"domains,omitempty"`
 	MetadataUrl      *string                     `json:"metadata_url,omitempty"`
 	MetadataXml      *string                     `json:"metadata_xml,omitempty"`
+
+	// MfaRequired Requires the users of the provider to use MFA, for identity providers that don't enforce it themselves.
+	MfaRequired *bool `json:"mfa_required,omitempty"`
 }
 
 // GetAdminUsersParams defines parameters for GetAdminUsers.
@@ -188,14 +285,23 @@ type PostInviteJSONBody struct {
 	Email string                  `json:"email"`
 }
 
+// PostAdminServiceAccountsJSONRequestBody defines body for PostAdminServiceAccounts for application/json ContentType.
+type PostAdminServiceAccountsJSONRequestBody PostAdminServiceAccountsJSONBody
+
+// PutAdminServiceAccountsServiceAccountIdJSONRequestBody defines body for PutAdminServiceAccountsServiceAccountId for application/json ContentType.
+type PutAdminServiceAccountsServiceAccountIdJSONRequestBody PutAdminServiceAccountsServiceAccountIdJSONBody
+
 // PostAdminSsoProvidersJSONRequestBody defines body for PostAdminSsoProviders for application/json ContentType.
 type PostAdminSsoProvidersJSONRequestBody PostAdminSsoProvidersJSONBody
 
 // PutAdminSsoProvidersSsoProviderIdJSONRequestBody defines body for PutAdminSsoProvidersSsoProviderId for application/json ContentType.
 type PutAdminSsoProvidersSsoProviderIdJSONRequestBody PutAdminSsoProvidersSsoProviderIdJSONBody
 
+// PostAdminUsersJSONRequestBody defines body for PostAdminUsers for application/json ContentType.
+type PostAdminUsersJSONRequestBody = AdminUserParamsSchema
+
 // PutAdminUsersUserIdJSONRequestBody defines body for PutAdminUsersUserId for application/json ContentType.
-type PutAdminUsersUserIdJSONRequestBody = UserSchema
+type PutAdminUsersUserIdJSONRequestBody = AdminUserParamsSchema
 
 // PutAdminUsersUserIdFactorsFactorIdJSONRequestBody defines body for PutAdminUsersUserIdFactorsFactorId for application/json ContentType.
 type PutAdminUsersUserIdFactorsFactorIdJSONRequestBody = PutAdminUsersUserIdFactorsFactorIdJSONBody
@@ -282,6 +388,28 @@ type ClientInterface interface {
 	// GetAdminAudit request
 	GetAdminAudit(ctx context.Context, params *GetAdminAuditParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetAdminServiceAccounts request
+	GetAdminServiceAccounts(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostAdminServiceAccounts request with any body
+	PostAdminServiceAccountsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostAdminServiceAccounts(ctx context.Context, body PostAdminServiceAccountsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteAdminServiceAccountsServiceAccountId request
+	DeleteAdminServiceAccountsServiceAccountId(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAdminServiceAccountsServiceAccountId request
+	GetAdminServiceAccountsServiceAccountId(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutAdminServiceAccountsServiceAccountId request with any body
+	PutAdminServiceAccountsServiceAccountIdWithBody(ctx context.Context, serviceAccountId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PutAdminServiceAccountsServiceAccountId(ctx context.Context, serviceAccountId openapi_types.UUID, body PutAdminServiceAccountsServiceAccountIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostAdminServiceAccountsServiceAccountIdRegenerateSecret request
+	PostAdminServiceAccountsServiceAccountIdRegenerateSecret(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// GetAdminSsoProviders request
 	GetAdminSsoProviders(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -304,6 +432,11 @@ type ClientInterface interface {
 	// GetAdminUsers request
 	GetAdminUsers(ctx context.Context, params *GetAdminUsersParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// PostAdminUsers request with any body
+	PostAdminUsersWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostAdminUsers(ctx context.Context, body PostAdminUsersJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// DeleteAdminUsersUserId request
 	DeleteAdminUsersUserId(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -326,6 +459,15 @@ type ClientInterface interface {
 
 	PutAdminUsersUserIdFactorsFactorId(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// DeleteAdminUsersUserIdSessions request
+	DeleteAdminUsersUserIdSessions(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAdminUsersUserIdSessions request
+	GetAdminUsersUserIdSessions(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteAdminUsersUserIdSessionsSessionId request
+	DeleteAdminUsersUserIdSessionsSessionId(ctx context.Context, userId openapi_types.UUID, sessionId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// PostGenerateLink request with any body
 	PostGenerateLinkWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -349,6 +491,102 @@ func (c *Client) GetAdminAudit(ctx context.Context, params *GetAdminAuditParams,
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetAdminServiceAccounts(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAdminServiceAccountsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostAdminServiceAccountsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAdminServiceAccountsRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostAdminServiceAccounts(ctx context.Context, body PostAdminServiceAccountsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAdminServiceAccountsRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteAdminServiceAccountsServiceAccountId(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteAdminServiceAccountsServiceAccountIdRequest(c.Server, serviceAccountId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAdminServiceAccountsServiceAccountId(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAdminServiceAccountsServiceAccountIdRequest(c.Server, serviceAccountId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutAdminServiceAccountsServiceAccountIdWithBody(ctx context.Context, serviceAccountId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutAdminServiceAccountsServiceAccountIdRequestWithBody(c.Server, serviceAccountId, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutAdminServiceAccountsServiceAccountId(ctx context.Context, serviceAccountId openapi_types.UUID, body PutAdminServiceAccountsServiceAccountIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutAdminServiceAccountsServiceAccountIdRequest(c.Server, serviceAccountId, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostAdminServiceAccountsServiceAccountIdRegenerateSecret(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAdminServiceAccountsServiceAccountIdRegenerateSecretRequest(c.Server, serviceAccountId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) GetAdminSsoProviders(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewGetAdminSsoProvidersRequest(c.Server)
 	if err != nil {
@@ -445,6 +683,30 @@ func (c *Client) GetAdminUsers(ctx context.Context, params *GetAdminUsersParams,
 	return c.Client.Do(req)
 }
 
+func (c *Client) PostAdminUsersWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAdminUsersRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostAdminUsers(ctx context.Context, body PostAdminUsersJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAdminUsersRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) DeleteAdminUsersUserId(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewDeleteAdminUsersUserIdRequest(c.Server, userId)
 	if err != nil {
@@ -541,6 +803,42 @@ func (c *Client) PutAdminUsersUserIdFactorsFactorId(ctx context.Context, userId
 	return c.Client.Do(req)
 }
 
+func (c *Client) DeleteAdminUsersUserIdSessions(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteAdminUsersUserIdSessionsRequest(c.Server, userId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAdminUsersUserIdSessions(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAdminUsersUserIdSessionsRequest(c.Server, userId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteAdminUsersUserIdSessionsSessionId(ctx context.Context, userId openapi_types.UUID, sessionId openapi_types.UUID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteAdminUsersUserIdSessionsSessionIdRequest(c.Server, userId, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) PostGenerateLinkWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewPostGenerateLinkRequestWithBody(c.Server, contentType, body)
 	if err != nil {
@@ -652,8 +950,8 @@ func NewGetAdminAuditRequest(server string, params *GetAdminAuditParams) (*http.
 	return req, nil
 }
 
-// NewGetAdminSsoProvidersRequest generates requests for GetAdminSsoProviders
-func NewGetAdminSsoProvidersRequest(server string) (*http.Request, error) {
+// NewGetAdminServiceAccountsRequest generates requests for GetAdminServiceAccounts
+func NewGetAdminServiceAccountsRequest(server string) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -661,7 +959,7 @@ func NewGetAdminSsoProvidersRequest(server string) (*http.Request, error) {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/sso/providers")
+	operationPath := fmt.Sprintf("/admin/service_accounts")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -679,19 +977,19 @@ func NewGetAdminSsoProvidersRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
-// NewPostAdminSsoProvidersRequest calls the generic PostAdminSsoProviders builder with application/json body
-func NewPostAdminSsoProvidersRequest(server string, body PostAdminSsoProvidersJSONRequestBody) (*http.Request, error) {
+// NewPostAdminServiceAccountsRequest calls the generic PostAdminServiceAccounts builder with application/json body
+func NewPostAdminServiceAccountsRequest(server string, body PostAdminServiceAccountsJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewPostAdminSsoProvidersRequestWithBody(server, "application/json", bodyReader)
+	return NewPostAdminServiceAccountsRequestWithBody(server, "application/json", bodyReader)
 }
 
-// NewPostAdminSsoProvidersRequestWithBody generates requests for PostAdminSsoProviders with any type of body
-func NewPostAdminSsoProvidersRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+// NewPostAdminServiceAccountsRequestWithBody generates requests for PostAdminServiceAccounts with any type of body
+func NewPostAdminServiceAccountsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -699,7 +997,7 @@ func NewPostAdminSsoProvidersRequestWithBody(server string, contentType string,
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/sso/providers")
+	operationPath := fmt.Sprintf("/admin/service_accounts")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -719,13 +1017,13 @@ func NewPostAdminSsoProvidersRequestWithBody(server string, contentType string,
 	return req, nil
 }
 
-// NewDeleteAdminSsoProvidersSsoProviderIdRequest generates requests for DeleteAdminSsoProvidersSsoProviderId
-func NewDeleteAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId openapi_types.UUID) (*http.Request, error) {
+// NewDeleteAdminServiceAccountsServiceAccountIdRequest generates requests for DeleteAdminServiceAccountsServiceAccountId
+func NewDeleteAdminServiceAccountsServiceAccountIdRequest(server string, serviceAccountId openapi_types.UUID) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "ssoProviderId", runtime.ParamLocationPath, ssoProviderId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "serviceAccountId", runtime.ParamLocationPath, serviceAccountId)
 	if err != nil {
 		return nil, err
 	}
@@ -735,7 +1033,7 @@ func NewDeleteAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/sso/providers/%s", pathParam0)
+	operationPath := fmt.Sprintf("/admin/service_accounts/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -753,13 +1051,13 @@ func NewDeleteAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId
 	return req, nil
 }
 
-// NewGetAdminSsoProvidersSsoProviderIdRequest generates requests for GetAdminSsoProvidersSsoProviderId
-func NewGetAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId openapi_types.UUID) (*http.Request, error) {
+// NewGetAdminServiceAccountsServiceAccountIdRequest generates requests for GetAdminServiceAccountsServiceAccountId
+func NewGetAdminServiceAccountsServiceAccountIdRequest(server string, serviceAccountId openapi_types.UUID) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "ssoProviderId", runtime.ParamLocationPath, ssoProviderId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "serviceAccountId", runtime.ParamLocationPath, serviceAccountId)
 	if err != nil {
 		return nil, err
 	}
@@ -769,7 +1067,7 @@ func NewGetAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId op
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/sso/providers/%s", pathParam0)
+	operationPath := fmt.Sprintf("/admin/service_accounts/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -787,24 +1085,24 @@ func NewGetAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId op
 	return req, nil
 }
 
-// NewPutAdminSsoProvidersSsoProviderIdRequest calls the generic PutAdminSsoProvidersSsoProviderId builder with application/json body
-func NewPutAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId openapi_types.UUID, body PutAdminSsoProvidersSsoProviderIdJSONRequestBody) (*http.Request, error) {
+// NewPutAdminServiceAccountsServiceAccountIdRequest calls the generic PutAdminServiceAccountsServiceAccountId builder with application/json body
+func NewPutAdminServiceAccountsServiceAccountIdRequest(server string, serviceAccountId openapi_types.UUID, body PutAdminServiceAccountsServiceAccountIdJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewPutAdminSsoProvidersSsoProviderIdRequestWithBody(server, ssoProviderId, "application/json", bodyReader)
+	return NewPutAdminServiceAccountsServiceAccountIdRequestWithBody(server, serviceAccountId, "application/json", bodyReader)
 }
 
-// NewPutAdminSsoProvidersSsoProviderIdRequestWithBody generates requests for PutAdminSsoProvidersSsoProviderId with any type of body
-func NewPutAdminSsoProvidersSsoProviderIdRequestWithBody(server string, ssoProviderId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
+// NewPutAdminServiceAccountsServiceAccountIdRequestWithBody generates requests for PutAdminServiceAccountsServiceAccountId with any type of body
+func NewPutAdminServiceAccountsServiceAccountIdRequestWithBody(server string, serviceAccountId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "ssoProviderId", runtime.ParamLocationPath, ssoProviderId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "serviceAccountId", runtime.ParamLocationPath, serviceAccountId)
 	if err != nil {
 		return nil, err
 	}
@@ -814,7 +1112,7 @@ func NewPutAdminSsoProvidersSsoProviderIdRequestWithBody(server string, ssoProvi
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/sso/providers/%s", pathParam0)
+	operationPath := fmt.Sprintf("/admin/service_accounts/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -834,16 +1132,23 @@ func NewPutAdminSsoProvidersSsoProviderIdRequestWithBody(server string, ssoProvi
 	return req, nil
 }
 
-// NewGetAdminUsersRequest generates requests for GetAdminUsers
-func NewGetAdminUsersRequest(server string, params *GetAdminUsersParams) (*http.Request, error) {
+// NewPostAdminServiceAccountsServiceAccountIdRegenerateSecretRequest generates requests for PostAdminServiceAccountsServiceAccountIdRegenerateSecret
+func NewPostAdminServiceAccountsServiceAccountIdRegenerateSecretRequest(server string, serviceAccountId openapi_types.UUID) (*http.Request, error) {
 	var err error
 
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "serviceAccountId", runtime.ParamLocationPath, serviceAccountId)
+	if err != nil {
+		return nil, err
+	}
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users")
+	operationPath := fmt.Sprintf("/admin/service_accounts/%s/regenerate_secret", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -853,43 +1158,7 @@ func NewGetAdminUsersRequest(server string, params *GetAdminUsersParams) (*http.
 		return nil, err
 	}
 
-	queryValues := queryURL.Query()
-
-	if params.Page != nil {
-
-		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, *params.Page); err != nil {
-			return nil, err
-		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
-			return nil, err
-		} else {
-			for k, v := range parsed {
-				for _, v2 := range v {
-					queryValues.Add(k, v2)
-				}
-			}
-		}
-
-	}
-
-	if params.PerPage != nil {
-
-		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "per_page", runtime.ParamLocationQuery, *params.PerPage); err != nil {
-			return nil, err
-		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
-			return nil, err
-		} else {
-			for k, v := range parsed {
-				for _, v2 := range v {
-					queryValues.Add(k, v2)
-				}
-			}
-		}
-
-	}
-
-	queryURL.RawQuery = queryValues.Encode()
-
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -897,23 +1166,16 @@ func NewGetAdminUsersRequest(server string, params *GetAdminUsersParams) (*http.
 	return req, nil
 }
 
-// NewDeleteAdminUsersUserIdRequest generates requests for DeleteAdminUsersUserId
-func NewDeleteAdminUsersUserIdRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+// NewGetAdminSsoProvidersRequest generates requests for GetAdminSsoProviders
+func NewGetAdminSsoProvidersRequest(server string) (*http.Request, error) {
 	var err error
 
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
-	if err != nil {
-		return nil, err
-	}
-
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users/%s", pathParam0)
+	operationPath := fmt.Sprintf("/admin/sso/providers")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -923,7 +1185,7 @@ func NewDeleteAdminUsersUserIdRequest(server string, userId openapi_types.UUID)
 		return nil, err
 	}
 
-	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -931,23 +1193,27 @@ func NewDeleteAdminUsersUserIdRequest(server string, userId openapi_types.UUID)
 	return req, nil
 }
 
-// NewGetAdminUsersUserIdRequest generates requests for GetAdminUsersUserId
-func NewGetAdminUsersUserIdRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
-	var err error
-
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+// NewPostAdminSsoProvidersRequest calls the generic PostAdminSsoProviders builder with application/json body
+func NewPostAdminSsoProvidersRequest(server string, body PostAdminSsoProvidersJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostAdminSsoProvidersRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostAdminSsoProvidersRequestWithBody generates requests for PostAdminSsoProviders with any type of body
+func NewPostAdminSsoProvidersRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
 
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users/%s", pathParam0)
+	operationPath := fmt.Sprintf("/admin/sso/providers")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -957,32 +1223,23 @@ func NewGetAdminUsersUserIdRequest(server string, userId openapi_types.UUID) (*h
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	req, err := http.NewRequest("POST", queryURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
-	return req, nil
-}
+	req.Header.Add("Content-Type", contentType)
 
-// NewPutAdminUsersUserIdRequest calls the generic PutAdminUsersUserId builder with application/json body
-func NewPutAdminUsersUserIdRequest(server string, userId openapi_types.UUID, body PutAdminUsersUserIdJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	bodyReader = bytes.NewReader(buf)
-	return NewPutAdminUsersUserIdRequestWithBody(server, userId, "application/json", bodyReader)
+	return req, nil
 }
 
-// NewPutAdminUsersUserIdRequestWithBody generates requests for PutAdminUsersUserId with any type of body
-func NewPutAdminUsersUserIdRequestWithBody(server string, userId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
+// NewDeleteAdminSsoProvidersSsoProviderIdRequest generates requests for DeleteAdminSsoProvidersSsoProviderId
+func NewDeleteAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId openapi_types.UUID) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "ssoProviderId", runtime.ParamLocationPath, ssoProviderId)
 	if err != nil {
 		return nil, err
 	}
@@ -992,7 +1249,7 @@ func NewPutAdminUsersUserIdRequestWithBody(server string, userId openapi_types.U
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users/%s", pathParam0)
+	operationPath := fmt.Sprintf("/admin/sso/providers/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1002,23 +1259,21 @@ func NewPutAdminUsersUserIdRequestWithBody(server string, userId openapi_types.U
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", contentType)
-
 	return req, nil
 }
 
-// NewGetAdminUsersUserIdFactorsRequest generates requests for GetAdminUsersUserIdFactors
-func NewGetAdminUsersUserIdFactorsRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+// NewGetAdminSsoProvidersSsoProviderIdRequest generates requests for GetAdminSsoProvidersSsoProviderId
+func NewGetAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId openapi_types.UUID) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "ssoProviderId", runtime.ParamLocationPath, ssoProviderId)
 	if err != nil {
 		return nil, err
 	}
@@ -1028,7 +1283,7 @@ func NewGetAdminUsersUserIdFactorsRequest(server string, userId openapi_types.UU
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users/%s/factors", pathParam0)
+	operationPath := fmt.Sprintf("/admin/sso/providers/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1046,30 +1301,63 @@ func NewGetAdminUsersUserIdFactorsRequest(server string, userId openapi_types.UU
 	return req, nil
 }
 
-// NewDeleteAdminUsersUserIdFactorsFactorIdRequest generates requests for DeleteAdminUsersUserIdFactorsFactorId
-func NewDeleteAdminUsersUserIdFactorsFactorIdRequest(server string, userId openapi_types.UUID, factorId openapi_types.UUID) (*http.Request, error) {
+// NewPutAdminSsoProvidersSsoProviderIdRequest calls the generic PutAdminSsoProvidersSsoProviderId builder with application/json body
+func NewPutAdminSsoProvidersSsoProviderIdRequest(server string, ssoProviderId openapi_types.UUID, body PutAdminSsoProvidersSsoProviderIdJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutAdminSsoProvidersSsoProviderIdRequestWithBody(server, ssoProviderId, "application/json", bodyReader)
+}
+
+// NewPutAdminSsoProvidersSsoProviderIdRequestWithBody generates requests for PutAdminSsoProvidersSsoProviderId with any type of body
+func NewPutAdminSsoProvidersSsoProviderIdRequestWithBody(server string, ssoProviderId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "ssoProviderId", runtime.ParamLocationPath, ssoProviderId)
 	if err != nil {
 		return nil, err
 	}
 
-	var pathParam1 string
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "factorId", runtime.ParamLocationPath, factorId)
+	operationPath := fmt.Sprintf("/admin/sso/providers/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetAdminUsersRequest generates requests for GetAdminUsers
+func NewGetAdminUsersRequest(server string, params *GetAdminUsersParams) (*http.Request, error) {
+	var err error
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users/%s/factors/%s", pathParam0, pathParam1)
+	operationPath := fmt.Sprintf("/admin/users")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1079,7 +1367,43 @@ func NewDeleteAdminUsersUserIdFactorsFactorIdRequest(server string, userId opena
 		return nil, err
 	}
 
-	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	queryValues := queryURL.Query()
+
+	if params.Page != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, *params.Page); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.PerPage != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "per_page", runtime.ParamLocationQuery, *params.PerPage); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1087,31 +1411,53 @@ func NewDeleteAdminUsersUserIdFactorsFactorIdRequest(server string, userId opena
 	return req, nil
 }
 
-// NewPutAdminUsersUserIdFactorsFactorIdRequest calls the generic PutAdminUsersUserIdFactorsFactorId builder with application/json body
-func NewPutAdminUsersUserIdFactorsFactorIdRequest(server string, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody) (*http.Request, error) {
+// NewPostAdminUsersRequest calls the generic PostAdminUsers builder with application/json body
+func NewPostAdminUsersRequest(server string, body PostAdminUsersJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody(server, userId, factorId, "application/json", bodyReader)
+	return NewPostAdminUsersRequestWithBody(server, "application/json", bodyReader)
 }
 
-// NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody generates requests for PutAdminUsersUserIdFactorsFactorId with any type of body
-func NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody(server string, userId openapi_types.UUID, factorId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
+// NewPostAdminUsersRequestWithBody generates requests for PostAdminUsers with any type of body
+func NewPostAdminUsersRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
-	var pathParam0 string
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	operationPath := fmt.Sprintf("/admin/users")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var pathParam1 string
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
 
-	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "factorId", runtime.ParamLocationPath, factorId)
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteAdminUsersUserIdRequest generates requests for DeleteAdminUsersUserId
+func NewDeleteAdminUsersUserIdRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -1121,7 +1467,7 @@ func NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody(server string, userId
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/admin/users/%s/factors/%s", pathParam0, pathParam1)
+	operationPath := fmt.Sprintf("/admin/users/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1131,37 +1477,31 @@ func NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody(server string, userId
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", contentType)
-
 	return req, nil
 }
 
-// NewPostGenerateLinkRequest calls the generic PostGenerateLink builder with application/json body
-func NewPostGenerateLinkRequest(server string, body PostGenerateLinkJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+// NewGetAdminUsersUserIdRequest generates requests for GetAdminUsersUserId
+func NewGetAdminUsersUserIdRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewPostGenerateLinkRequestWithBody(server, "application/json", bodyReader)
-}
-
-// NewPostGenerateLinkRequestWithBody generates requests for PostGenerateLink with any type of body
-func NewPostGenerateLinkRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
 
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/generate_link")
+	operationPath := fmt.Sprintf("/admin/users/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1171,37 +1511,42 @@ func NewPostGenerateLinkRequestWithBody(server string, contentType string, body
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", contentType)
-
 	return req, nil
 }
 
-// NewPostInviteRequest calls the generic PostInvite builder with application/json body
-func NewPostInviteRequest(server string, body PostInviteJSONRequestBody) (*http.Request, error) {
+// NewPutAdminUsersUserIdRequest calls the generic PutAdminUsersUserId builder with application/json body
+func NewPutAdminUsersUserIdRequest(server string, userId openapi_types.UUID, body PutAdminUsersUserIdJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 	bodyReader = bytes.NewReader(buf)
-	return NewPostInviteRequestWithBody(server, "application/json", bodyReader)
+	return NewPutAdminUsersUserIdRequestWithBody(server, userId, "application/json", bodyReader)
 }
 
-// NewPostInviteRequestWithBody generates requests for PostInvite with any type of body
-func NewPostInviteRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+// NewPutAdminUsersUserIdRequestWithBody generates requests for PutAdminUsersUserId with any type of body
+func NewPutAdminUsersUserIdRequestWithBody(server string, userId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	if err != nil {
+		return nil, err
+	}
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/invite")
+	operationPath := fmt.Sprintf("/admin/users/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1211,7 +1556,7 @@ func NewPostInviteRequestWithBody(server string, contentType string, body io.Rea
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -1221,38 +1566,356 @@ func NewPostInviteRequestWithBody(server string, contentType string, body io.Rea
 	return req, nil
 }
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
-	}
-	return nil
-}
+// NewGetAdminUsersUserIdFactorsRequest generates requests for GetAdminUsersUserIdFactors
+func NewGetAdminUsersUserIdFactorsRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+	var err error
 
-// ClientWithResponses builds on ClientInterface to offer response payloads
-type ClientWithResponses struct {
-	ClientInterface
-}
+	var pathParam0 string
 
-// NewClientWithResponses creates a new ClientWithResponses, which wraps
-// Client with return type handling
-func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
-	client, err := NewClient(server, opts...)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
 	if err != nil {
 		return nil, err
 	}
-	return &ClientWithResponses{client}, nil
-}
 
-// WithBaseURL overrides the baseURL.
-func WithBaseURL(baseURL string) ClientOption {
-	return func(c *Client) error {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/users/%s/factors", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDeleteAdminUsersUserIdFactorsFactorIdRequest generates requests for DeleteAdminUsersUserIdFactorsFactorId
+func NewDeleteAdminUsersUserIdFactorsFactorIdRequest(server string, userId openapi_types.UUID, factorId openapi_types.UUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "factorId", runtime.ParamLocationPath, factorId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/users/%s/factors/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPutAdminUsersUserIdFactorsFactorIdRequest calls the generic PutAdminUsersUserIdFactorsFactorId builder with application/json body
+func NewPutAdminUsersUserIdFactorsFactorIdRequest(server string, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody(server, userId, factorId, "application/json", bodyReader)
+}
+
+// NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody generates requests for PutAdminUsersUserIdFactorsFactorId with any type of body
+func NewPutAdminUsersUserIdFactorsFactorIdRequestWithBody(server string, userId openapi_types.UUID, factorId openapi_types.UUID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "factorId", runtime.ParamLocationPath, factorId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/users/%s/factors/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteAdminUsersUserIdSessionsRequest generates requests for DeleteAdminUsersUserIdSessions
+func NewDeleteAdminUsersUserIdSessionsRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/users/%s/sessions", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetAdminUsersUserIdSessionsRequest generates requests for GetAdminUsersUserIdSessions
+func NewGetAdminUsersUserIdSessionsRequest(server string, userId openapi_types.UUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/users/%s/sessions", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDeleteAdminUsersUserIdSessionsSessionIdRequest generates requests for DeleteAdminUsersUserIdSessionsSessionId
+func NewDeleteAdminUsersUserIdSessionsSessionIdRequest(server string, userId openapi_types.UUID, sessionId openapi_types.UUID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userId", runtime.ParamLocationPath, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "sessionId", runtime.ParamLocationPath, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/users/%s/sessions/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostGenerateLinkRequest calls the generic PostGenerateLink builder with application/json body
+func NewPostGenerateLinkRequest(server string, body PostGenerateLinkJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostGenerateLinkRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostGenerateLinkRequestWithBody generates requests for PostGenerateLink with any type of body
+func NewPostGenerateLinkRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/generate_link")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostInviteRequest calls the generic PostInvite builder with application/json body
+func NewPostInviteRequest(server string, body PostInviteJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostInviteRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostInviteRequestWithBody generates requests for PostInvite with any type of body
+func NewPostInviteRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/invite")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
 		newBaseURL, err := url.Parse(baseURL)
 		if err != nil {
 			return err
@@ -1262,120 +1925,515 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// ClientWithResponsesInterface is the interface specification for the client with responses above.
-type ClientWithResponsesInterface interface {
-	// GetAdminAudit request
-	GetAdminAuditWithResponse(ctx context.Context, params *GetAdminAuditParams, reqEditors ...RequestEditorFn) (*GetAdminAuditResponse, error)
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetAdminAudit request
+	GetAdminAuditWithResponse(ctx context.Context, params *GetAdminAuditParams, reqEditors ...RequestEditorFn) (*GetAdminAuditResponse, error)
+
+	// GetAdminServiceAccounts request
+	GetAdminServiceAccountsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminServiceAccountsResponse, error)
+
+	// PostAdminServiceAccounts request with any body
+	PostAdminServiceAccountsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminServiceAccountsResponse, error)
+
+	PostAdminServiceAccountsWithResponse(ctx context.Context, body PostAdminServiceAccountsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminServiceAccountsResponse, error)
+
+	// DeleteAdminServiceAccountsServiceAccountId request
+	DeleteAdminServiceAccountsServiceAccountIdWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminServiceAccountsServiceAccountIdResponse, error)
+
+	// GetAdminServiceAccountsServiceAccountId request
+	GetAdminServiceAccountsServiceAccountIdWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminServiceAccountsServiceAccountIdResponse, error)
+
+	// PutAdminServiceAccountsServiceAccountId request with any body
+	PutAdminServiceAccountsServiceAccountIdWithBodyWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminServiceAccountsServiceAccountIdResponse, error)
+
+	PutAdminServiceAccountsServiceAccountIdWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, body PutAdminServiceAccountsServiceAccountIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminServiceAccountsServiceAccountIdResponse, error)
+
+	// PostAdminServiceAccountsServiceAccountIdRegenerateSecret request
+	PostAdminServiceAccountsServiceAccountIdRegenerateSecretWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse, error)
+
+	// GetAdminSsoProviders request
+	GetAdminSsoProvidersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersResponse, error)
+
+	// PostAdminSsoProviders request with any body
+	PostAdminSsoProvidersWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error)
+
+	PostAdminSsoProvidersWithResponse(ctx context.Context, body PostAdminSsoProvidersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error)
+
+	// DeleteAdminSsoProvidersSsoProviderId request
+	DeleteAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminSsoProvidersSsoProviderIdResponse, error)
+
+	// GetAdminSsoProvidersSsoProviderId request
+	GetAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersSsoProviderIdResponse, error)
+
+	// PutAdminSsoProvidersSsoProviderId request with any body
+	PutAdminSsoProvidersSsoProviderIdWithBodyWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error)
+
+	PutAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, body PutAdminSsoProvidersSsoProviderIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error)
+
+	// GetAdminUsers request
+	GetAdminUsersWithResponse(ctx context.Context, params *GetAdminUsersParams, reqEditors ...RequestEditorFn) (*GetAdminUsersResponse, error)
+
+	// PostAdminUsers request with any body
+	PostAdminUsersWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminUsersResponse, error)
+
+	PostAdminUsersWithResponse(ctx context.Context, body PostAdminUsersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminUsersResponse, error)
+
+	// DeleteAdminUsersUserId request
+	DeleteAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdResponse, error)
+
+	// GetAdminUsersUserId request
+	GetAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdResponse, error)
+
+	// PutAdminUsersUserId request with any body
+	PutAdminUsersUserIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error)
+
+	PutAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, body PutAdminUsersUserIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error)
+
+	// GetAdminUsersUserIdFactors request
+	GetAdminUsersUserIdFactorsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdFactorsResponse, error)
+
+	// DeleteAdminUsersUserIdFactorsFactorId request
+	DeleteAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdFactorsFactorIdResponse, error)
+
+	// PutAdminUsersUserIdFactorsFactorId request with any body
+	PutAdminUsersUserIdFactorsFactorIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error)
+
+	PutAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error)
+
+	// DeleteAdminUsersUserIdSessions request
+	DeleteAdminUsersUserIdSessionsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdSessionsResponse, error)
+
+	// GetAdminUsersUserIdSessions request
+	GetAdminUsersUserIdSessionsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdSessionsResponse, error)
+
+	// DeleteAdminUsersUserIdSessionsSessionId request
+	DeleteAdminUsersUserIdSessionsSessionIdWithResponse(ctx context.Context, userId openapi_types.UUID, sessionId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdSessionsSessionIdResponse, error)
+
+	// PostGenerateLink request with any body
+	PostGenerateLinkWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error)
+
+	PostGenerateLinkWithResponse(ctx context.Context, body PostGenerateLinkJSONRequestBody, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error)
+
+	// PostInvite request with any body
+	PostInviteWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostInviteResponse, error)
+
+	PostInviteWithResponse(ctx context.Context, body PostInviteJSONRequestBody, reqEditors ...RequestEditorFn) (*PostInviteResponse, error)
+}
+
+type GetAdminAuditResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]struct {
+		CreatedAt *time.Time          `json:"created_at,omitempty"`
+		Id        *openapi_types.UUID `json:"id,omitempty"`
+		IpAddress *string             `json:"ip_address,omitempty"`
+		Payload   *struct {
+			// Action Usually one of these values:
+			// - login
+			// - logout
+			// - invite_accepted
+			// - user_signedup
+			// - user_invited
+			// - user_deleted
+			// - user_modified
+			// - user_recovery_requested
+			// - user_reauthenticate_requested
+			// - user_reauthenticated
+			// - user_confirmation_requested
+			// - user_repeated_signup
+			// - user_updated_password
+			// - token_revoked
+			// - token_refreshed
+			// - generate_recovery_codes
+			// - factor_in_progress
+			// - factor_unenrolled
+			// - challenge_created
+			// - verification_attempted
+			// - factor_deleted
+			// - recovery_codes_deleted
+			// - factor_updated
+			// - mfa_code_login
+			Action        *string `json:"action,omitempty"`
+			ActorId       *string `json:"actor_id,omitempty"`
+			ActorName     *string `json:"actor_name,omitempty"`
+			ActorUsername *string `json:"actor_username,omitempty"`
+
+			// ActorViaSso Whether the actor used a SSO protocol (like SAML 2.0 or OIDC) to authenticate.
+			ActorViaSso *bool `json:"actor_via_sso,omitempty"`
+
+			// LogType Usually one of these values:
+			// - account
+			// - team
+			// - token
+			// - user
+			// - factor
+			// - recovery_codes
+			LogType *string                 `json:"log_type,omitempty"`
+			Traits  *map[string]interface{} `json:"traits,omitempty"`
+		} `json:"payload,omitempty"`
+	}
+	JSON401 *ErrorSchema
+	JSON403 *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminAuditResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminAuditResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAdminServiceAccountsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Items *[]ServiceAccountSchema `json:"items,omitempty"`
+	}
+	JSON401 *ErrorSchema
+	JSON403 *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminServiceAccountsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminServiceAccountsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostAdminServiceAccountsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *ServiceAccountSchema
+	JSON400      *ErrorSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r PostAdminServiceAccountsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostAdminServiceAccountsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteAdminServiceAccountsServiceAccountIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ServiceAccountSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteAdminServiceAccountsServiceAccountIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteAdminServiceAccountsServiceAccountIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAdminServiceAccountsServiceAccountIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ServiceAccountSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminServiceAccountsServiceAccountIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminServiceAccountsServiceAccountIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutAdminServiceAccountsServiceAccountIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ServiceAccountSchema
+	JSON400      *ErrorSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r PutAdminServiceAccountsServiceAccountIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutAdminServiceAccountsServiceAccountIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	// GetAdminSsoProviders request
-	GetAdminSsoProvidersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersResponse, error)
+type PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ServiceAccountSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
 
-	// PostAdminSsoProviders request with any body
-	PostAdminSsoProvidersWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error)
+// Status returns HTTPResponse.Status
+func (r PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	PostAdminSsoProvidersWithResponse(ctx context.Context, body PostAdminSsoProvidersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	// DeleteAdminSsoProvidersSsoProviderId request
-	DeleteAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminSsoProvidersSsoProviderIdResponse, error)
+type GetAdminSsoProvidersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Items *[]SSOProviderSchema `json:"items,omitempty"`
+	}
+}
 
-	// GetAdminSsoProvidersSsoProviderId request
-	GetAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersSsoProviderIdResponse, error)
+// Status returns HTTPResponse.Status
+func (r GetAdminSsoProvidersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	// PutAdminSsoProvidersSsoProviderId request with any body
-	PutAdminSsoProvidersSsoProviderIdWithBodyWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminSsoProvidersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	PutAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, body PutAdminSsoProvidersSsoProviderIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error)
+type PostAdminSsoProvidersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SSOProviderSchema
+	JSON400      *ErrorSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+}
 
-	// GetAdminUsers request
-	GetAdminUsersWithResponse(ctx context.Context, params *GetAdminUsersParams, reqEditors ...RequestEditorFn) (*GetAdminUsersResponse, error)
+// Status returns HTTPResponse.Status
+func (r PostAdminSsoProvidersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	// DeleteAdminUsersUserId request
-	DeleteAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostAdminSsoProvidersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	// GetAdminUsersUserId request
-	GetAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdResponse, error)
+type DeleteAdminSsoProvidersSsoProviderIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SSOProviderSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
 
-	// PutAdminUsersUserId request with any body
-	PutAdminUsersUserIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error)
+// Status returns HTTPResponse.Status
+func (r DeleteAdminSsoProvidersSsoProviderIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	PutAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, body PutAdminUsersUserIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteAdminSsoProvidersSsoProviderIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	// GetAdminUsersUserIdFactors request
-	GetAdminUsersUserIdFactorsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdFactorsResponse, error)
+type GetAdminSsoProvidersSsoProviderIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SSOProviderSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
 
-	// DeleteAdminUsersUserIdFactorsFactorId request
-	DeleteAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdFactorsFactorIdResponse, error)
+// Status returns HTTPResponse.Status
+func (r GetAdminSsoProvidersSsoProviderIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	// PutAdminUsersUserIdFactorsFactorId request with any body
-	PutAdminUsersUserIdFactorsFactorIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminSsoProvidersSsoProviderIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	PutAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error)
+type PutAdminSsoProvidersSsoProviderIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SSOProviderSchema
+	JSON400      *ErrorSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
 
-	// PostGenerateLink request with any body
-	PostGenerateLinkWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error)
+// Status returns HTTPResponse.Status
+func (r PutAdminSsoProvidersSsoProviderIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
 
-	PostGenerateLinkWithResponse(ctx context.Context, body PostGenerateLinkJSONRequestBody, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error)
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutAdminSsoProvidersSsoProviderIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
 
-	// PostInvite request with any body
-	PostInviteWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostInviteResponse, error)
+type GetAdminUsersResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Aud   *string       `json:"aud,omitempty"`
+		Users *[]UserSchema `json:"users,omitempty"`
+	}
+	JSON401 *ErrorSchema
+	JSON403 *ErrorSchema
+}
 
-	PostInviteWithResponse(ctx context.Context, body PostInviteJSONRequestBody, reqEditors ...RequestEditorFn) (*PostInviteResponse, error)
+// Status returns HTTPResponse.Status
+func (r GetAdminUsersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
 }
 
-type GetAdminAuditResponse struct {
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminUsersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostAdminUsersResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *[]struct {
-		CreatedAt *time.Time          `json:"created_at,omitempty"`
-		Id        *openapi_types.UUID `json:"id,omitempty"`
-		IpAddress *string             `json:"ip_address,omitempty"`
-		Payload   *struct {
-			// Action Usually one of these values:
-			// - login
-			// - logout
-			// - invite_accepted
-			// - user_signedup
-			// - user_invited
-			// - user_deleted
-			// - user_modified
-			// - user_recovery_requested
-			// - user_reauthenticate_requested
-			// - user_confirmation_requested
-			// - user_repeated_signup
-			// - user_updated_password
-			// - token_revoked
-			// - token_refreshed
-			// - generate_recovery_codes
-			// - factor_in_progress
-			// - factor_unenrolled
-			// - challenge_created
-			// - verification_attempted
-			// - factor_deleted
-			// - recovery_codes_deleted
-			// - factor_updated
-			// - mfa_code_login
-			Action        *string `json:"action,omitempty"`
-			ActorId       *string `json:"actor_id,omitempty"`
-			ActorName     *string `json:"actor_name,omitempty"`
-			ActorUsername *string `json:"actor_username,omitempty"`
+	JSON200      *UserSchema
+	JSON400      *ErrorSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON422      *ErrorSchema
+}
 
-			// LogType Usually one of these values:
-			// - account
-			// - team
-			// - token
-			// - user
-			// - factor
-			// - recovery_codes
-			LogType *string                 `json:"log_type,omitempty"`
-			Traits  *map[string]interface{} `json:"traits,omitempty"`
-		} `json:"payload,omitempty"`
+// Status returns HTTPResponse.Status
+func (r PostAdminUsersResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
 	}
-	JSON401 *ErrorSchema
-	JSON403 *ErrorSchema
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostAdminUsersResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteAdminUsersUserIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UserSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r GetAdminAuditResponse) Status() string {
+func (r DeleteAdminUsersUserIdResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1383,23 +2441,49 @@ func (r GetAdminAuditResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r GetAdminAuditResponse) StatusCode() int {
+func (r DeleteAdminUsersUserIdResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type GetAdminSsoProvidersResponse struct {
+type GetAdminUsersUserIdResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *struct {
-		Items *[]SSOProviderSchema `json:"items,omitempty"`
+	JSON200      *UserSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminUsersUserIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminUsersUserIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
 	}
+	return 0
+}
+
+type PutAdminUsersUserIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *UserSchema
+	JSON401      *ErrorSchema
+	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r GetAdminSsoProvidersResponse) Status() string {
+func (r PutAdminUsersUserIdResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1407,24 +2491,24 @@ func (r GetAdminSsoProvidersResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r GetAdminSsoProvidersResponse) StatusCode() int {
+func (r PutAdminUsersUserIdResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type PostAdminSsoProvidersResponse struct {
+type GetAdminUsersUserIdFactorsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *SSOProviderSchema
-	JSON400      *ErrorSchema
+	JSON200      *[]MFAFactorSchema
 	JSON401      *ErrorSchema
 	JSON403      *ErrorSchema
+	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r PostAdminSsoProvidersResponse) Status() string {
+func (r GetAdminUsersUserIdFactorsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1432,24 +2516,24 @@ func (r PostAdminSsoProvidersResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r PostAdminSsoProvidersResponse) StatusCode() int {
+func (r GetAdminUsersUserIdFactorsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type DeleteAdminSsoProvidersSsoProviderIdResponse struct {
+type DeleteAdminUsersUserIdFactorsFactorIdResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *SSOProviderSchema
+	JSON200      *MFAFactorSchema
 	JSON401      *ErrorSchema
 	JSON403      *ErrorSchema
 	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r DeleteAdminSsoProvidersSsoProviderIdResponse) Status() string {
+func (r DeleteAdminUsersUserIdFactorsFactorIdResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1457,24 +2541,24 @@ func (r DeleteAdminSsoProvidersSsoProviderIdResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r DeleteAdminSsoProvidersSsoProviderIdResponse) StatusCode() int {
+func (r DeleteAdminUsersUserIdFactorsFactorIdResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type GetAdminSsoProvidersSsoProviderIdResponse struct {
+type PutAdminUsersUserIdFactorsFactorIdResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *SSOProviderSchema
+	JSON200      *MFAFactorSchema
 	JSON401      *ErrorSchema
 	JSON403      *ErrorSchema
 	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r GetAdminSsoProvidersSsoProviderIdResponse) Status() string {
+func (r PutAdminUsersUserIdFactorsFactorIdResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1482,25 +2566,23 @@ func (r GetAdminSsoProvidersSsoProviderIdResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r GetAdminSsoProvidersSsoProviderIdResponse) StatusCode() int {
+func (r PutAdminUsersUserIdFactorsFactorIdResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type PutAdminSsoProvidersSsoProviderIdResponse struct {
+type DeleteAdminUsersUserIdSessionsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *SSOProviderSchema
-	JSON400      *ErrorSchema
 	JSON401      *ErrorSchema
 	JSON403      *ErrorSchema
 	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r PutAdminSsoProvidersSsoProviderIdResponse) Status() string {
+func (r DeleteAdminUsersUserIdSessionsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1508,26 +2590,26 @@ func (r PutAdminSsoProvidersSsoProviderIdResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r PutAdminSsoProvidersSsoProviderIdResponse) StatusCode() int {
+func (r DeleteAdminUsersUserIdSessionsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type GetAdminUsersResponse struct {
+type GetAdminUsersUserIdSessionsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
 	JSON200      *struct {
-		Aud   *string       `json:"aud,omitempty"`
-		Users *[]UserSchema `json:"users,omitempty"`
+		Sessions *[]SessionSchema `json:"sessions,omitempty"`
 	}
 	JSON401 *ErrorSchema
 	JSON403 *ErrorSchema
+	JSON404 *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r GetAdminUsersResponse) Status() string {
+func (r GetAdminUsersUserIdSessionsResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1535,24 +2617,23 @@ func (r GetAdminUsersResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r GetAdminUsersResponse) StatusCode() int {
+func (r GetAdminUsersUserIdSessionsResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type DeleteAdminUsersUserIdResponse struct {
+type DeleteAdminUsersUserIdSessionsSessionIdResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *UserSchema
 	JSON401      *ErrorSchema
 	JSON403      *ErrorSchema
 	JSON404      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r DeleteAdminUsersUserIdResponse) Status() string {
+func (r DeleteAdminUsersUserIdSessionsSessionIdResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1560,24 +2641,33 @@ func (r DeleteAdminUsersUserIdResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r DeleteAdminUsersUserIdResponse) StatusCode() int {
+func (r DeleteAdminUsersUserIdSessionsSessionIdResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type GetAdminUsersUserIdResponse struct {
+type PostGenerateLinkResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
-	JSON200      *UserSchema
-	JSON401      *ErrorSchema
-	JSON403      *ErrorSchema
-	JSON404      *ErrorSchema
+	JSON200      *struct {
+		ActionLink           *string                `json:"action_link,omitempty"`
+		EmailOtp             *string                `json:"email_otp,omitempty"`
+		HashedToken          *string                `json:"hashed_token,omitempty"`
+		RedirectTo           *string                `json:"redirect_to,omitempty"`
+		VerificationType     *string                `json:"verification_type,omitempty"`
+		AdditionalProperties map[string]interface{} `json:"-"`
+	}
+	JSON400 *ErrorSchema
+	JSON401 *ErrorSchema
+	JSON403 *ErrorSchema
+	JSON404 *ErrorSchema
+	JSON422 *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r GetAdminUsersUserIdResponse) Status() string {
+func (r PostGenerateLinkResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1585,24 +2675,23 @@ func (r GetAdminUsersUserIdResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r GetAdminUsersUserIdResponse) StatusCode() int {
+func (r PostGenerateLinkResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
 	return 0
 }
 
-type PutAdminUsersUserIdResponse struct {
+type PostInviteResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
 	JSON200      *UserSchema
-	JSON401      *ErrorSchema
-	JSON403      *ErrorSchema
-	JSON404      *ErrorSchema
+	JSON400      *ErrorSchema
+	JSON422      *ErrorSchema
 }
 
 // Status returns HTTPResponse.Status
-func (r PutAdminUsersUserIdResponse) Status() string {
+func (r PostInviteResponse) Status() string {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.Status
 	}
@@ -1610,389 +2699,651 @@ func (r PutAdminUsersUserIdResponse) Status() string {
 }
 
 // StatusCode returns HTTPResponse.StatusCode
-func (r PutAdminUsersUserIdResponse) StatusCode() int {
+func (r PostInviteResponse) StatusCode() int {
 	if r.HTTPResponse != nil {
 		return r.HTTPResponse.StatusCode
 	}
-	return 0
+	return 0
+}
+
+// GetAdminAuditWithResponse request returning *GetAdminAuditResponse
+func (c *ClientWithResponses) GetAdminAuditWithResponse(ctx context.Context, params *GetAdminAuditParams, reqEditors ...RequestEditorFn) (*GetAdminAuditResponse, error) {
+	rsp, err := c.GetAdminAudit(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminAuditResponse(rsp)
+}
+
+// GetAdminServiceAccountsWithResponse request returning *GetAdminServiceAccountsResponse
+func (c *ClientWithResponses) GetAdminServiceAccountsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminServiceAccountsResponse, error) {
+	rsp, err := c.GetAdminServiceAccounts(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminServiceAccountsResponse(rsp)
+}
+
+// PostAdminServiceAccountsWithBodyWithResponse request with arbitrary body returning *PostAdminServiceAccountsResponse
+func (c *ClientWithResponses) PostAdminServiceAccountsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminServiceAccountsResponse, error) {
+	rsp, err := c.PostAdminServiceAccountsWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminServiceAccountsResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostAdminServiceAccountsWithResponse(ctx context.Context, body PostAdminServiceAccountsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminServiceAccountsResponse, error) {
+	rsp, err := c.PostAdminServiceAccounts(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminServiceAccountsResponse(rsp)
+}
+
+// DeleteAdminServiceAccountsServiceAccountIdWithResponse request returning *DeleteAdminServiceAccountsServiceAccountIdResponse
+func (c *ClientWithResponses) DeleteAdminServiceAccountsServiceAccountIdWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminServiceAccountsServiceAccountIdResponse, error) {
+	rsp, err := c.DeleteAdminServiceAccountsServiceAccountId(ctx, serviceAccountId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteAdminServiceAccountsServiceAccountIdResponse(rsp)
+}
+
+// GetAdminServiceAccountsServiceAccountIdWithResponse request returning *GetAdminServiceAccountsServiceAccountIdResponse
+func (c *ClientWithResponses) GetAdminServiceAccountsServiceAccountIdWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminServiceAccountsServiceAccountIdResponse, error) {
+	rsp, err := c.GetAdminServiceAccountsServiceAccountId(ctx, serviceAccountId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminServiceAccountsServiceAccountIdResponse(rsp)
+}
+
+// PutAdminServiceAccountsServiceAccountIdWithBodyWithResponse request with arbitrary body returning *PutAdminServiceAccountsServiceAccountIdResponse
+func (c *ClientWithResponses) PutAdminServiceAccountsServiceAccountIdWithBodyWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminServiceAccountsServiceAccountIdResponse, error) {
+	rsp, err := c.PutAdminServiceAccountsServiceAccountIdWithBody(ctx, serviceAccountId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutAdminServiceAccountsServiceAccountIdResponse(rsp)
+}
+
+func (c *ClientWithResponses) PutAdminServiceAccountsServiceAccountIdWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, body PutAdminServiceAccountsServiceAccountIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminServiceAccountsServiceAccountIdResponse, error) {
+	rsp, err := c.PutAdminServiceAccountsServiceAccountId(ctx, serviceAccountId, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutAdminServiceAccountsServiceAccountIdResponse(rsp)
+}
+
+// PostAdminServiceAccountsServiceAccountIdRegenerateSecretWithResponse request returning *PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse
+func (c *ClientWithResponses) PostAdminServiceAccountsServiceAccountIdRegenerateSecretWithResponse(ctx context.Context, serviceAccountId openapi_types.UUID, reqEditors ...RequestEditorFn) (*PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse, error) {
+	rsp, err := c.PostAdminServiceAccountsServiceAccountIdRegenerateSecret(ctx, serviceAccountId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse(rsp)
+}
+
+// GetAdminSsoProvidersWithResponse request returning *GetAdminSsoProvidersResponse
+func (c *ClientWithResponses) GetAdminSsoProvidersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersResponse, error) {
+	rsp, err := c.GetAdminSsoProviders(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminSsoProvidersResponse(rsp)
+}
+
+// PostAdminSsoProvidersWithBodyWithResponse request with arbitrary body returning *PostAdminSsoProvidersResponse
+func (c *ClientWithResponses) PostAdminSsoProvidersWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error) {
+	rsp, err := c.PostAdminSsoProvidersWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminSsoProvidersResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostAdminSsoProvidersWithResponse(ctx context.Context, body PostAdminSsoProvidersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error) {
+	rsp, err := c.PostAdminSsoProviders(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminSsoProvidersResponse(rsp)
+}
+
+// DeleteAdminSsoProvidersSsoProviderIdWithResponse request returning *DeleteAdminSsoProvidersSsoProviderIdResponse
+func (c *ClientWithResponses) DeleteAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminSsoProvidersSsoProviderIdResponse, error) {
+	rsp, err := c.DeleteAdminSsoProvidersSsoProviderId(ctx, ssoProviderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteAdminSsoProvidersSsoProviderIdResponse(rsp)
+}
+
+// GetAdminSsoProvidersSsoProviderIdWithResponse request returning *GetAdminSsoProvidersSsoProviderIdResponse
+func (c *ClientWithResponses) GetAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersSsoProviderIdResponse, error) {
+	rsp, err := c.GetAdminSsoProvidersSsoProviderId(ctx, ssoProviderId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminSsoProvidersSsoProviderIdResponse(rsp)
+}
+
+// PutAdminSsoProvidersSsoProviderIdWithBodyWithResponse request with arbitrary body returning *PutAdminSsoProvidersSsoProviderIdResponse
+func (c *ClientWithResponses) PutAdminSsoProvidersSsoProviderIdWithBodyWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error) {
+	rsp, err := c.PutAdminSsoProvidersSsoProviderIdWithBody(ctx, ssoProviderId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutAdminSsoProvidersSsoProviderIdResponse(rsp)
+}
+
+func (c *ClientWithResponses) PutAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, body PutAdminSsoProvidersSsoProviderIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error) {
+	rsp, err := c.PutAdminSsoProvidersSsoProviderId(ctx, ssoProviderId, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutAdminSsoProvidersSsoProviderIdResponse(rsp)
+}
+
+// GetAdminUsersWithResponse request returning *GetAdminUsersResponse
+func (c *ClientWithResponses) GetAdminUsersWithResponse(ctx context.Context, params *GetAdminUsersParams, reqEditors ...RequestEditorFn) (*GetAdminUsersResponse, error) {
+	rsp, err := c.GetAdminUsers(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminUsersResponse(rsp)
 }
 
-type GetAdminUsersUserIdFactorsResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *[]MFAFactorSchema
-	JSON401      *ErrorSchema
-	JSON403      *ErrorSchema
-	JSON404      *ErrorSchema
+// PostAdminUsersWithBodyWithResponse request with arbitrary body returning *PostAdminUsersResponse
+func (c *ClientWithResponses) PostAdminUsersWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminUsersResponse, error) {
+	rsp, err := c.PostAdminUsersWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminUsersResponse(rsp)
 }
 
-// Status returns HTTPResponse.Status
-func (r GetAdminUsersUserIdFactorsResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+func (c *ClientWithResponses) PostAdminUsersWithResponse(ctx context.Context, body PostAdminUsersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminUsersResponse, error) {
+	rsp, err := c.PostAdminUsers(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	return ParsePostAdminUsersResponse(rsp)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r GetAdminUsersUserIdFactorsResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// DeleteAdminUsersUserIdWithResponse request returning *DeleteAdminUsersUserIdResponse
+func (c *ClientWithResponses) DeleteAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdResponse, error) {
+	rsp, err := c.DeleteAdminUsersUserId(ctx, userId, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	return ParseDeleteAdminUsersUserIdResponse(rsp)
 }
 
-type DeleteAdminUsersUserIdFactorsFactorIdResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *MFAFactorSchema
-	JSON401      *ErrorSchema
-	JSON403      *ErrorSchema
-	JSON404      *ErrorSchema
+// GetAdminUsersUserIdWithResponse request returning *GetAdminUsersUserIdResponse
+func (c *ClientWithResponses) GetAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdResponse, error) {
+	rsp, err := c.GetAdminUsersUserId(ctx, userId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminUsersUserIdResponse(rsp)
 }
 
-// Status returns HTTPResponse.Status
-func (r DeleteAdminUsersUserIdFactorsFactorIdResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// PutAdminUsersUserIdWithBodyWithResponse request with arbitrary body returning *PutAdminUsersUserIdResponse
+func (c *ClientWithResponses) PutAdminUsersUserIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error) {
+	rsp, err := c.PutAdminUsersUserIdWithBody(ctx, userId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	return ParsePutAdminUsersUserIdResponse(rsp)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r DeleteAdminUsersUserIdFactorsFactorIdResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+func (c *ClientWithResponses) PutAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, body PutAdminUsersUserIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error) {
+	rsp, err := c.PutAdminUsersUserId(ctx, userId, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	return ParsePutAdminUsersUserIdResponse(rsp)
 }
 
-type PutAdminUsersUserIdFactorsFactorIdResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *MFAFactorSchema
-	JSON401      *ErrorSchema
-	JSON403      *ErrorSchema
-	JSON404      *ErrorSchema
+// GetAdminUsersUserIdFactorsWithResponse request returning *GetAdminUsersUserIdFactorsResponse
+func (c *ClientWithResponses) GetAdminUsersUserIdFactorsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdFactorsResponse, error) {
+	rsp, err := c.GetAdminUsersUserIdFactors(ctx, userId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminUsersUserIdFactorsResponse(rsp)
 }
 
-// Status returns HTTPResponse.Status
-func (r PutAdminUsersUserIdFactorsFactorIdResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// DeleteAdminUsersUserIdFactorsFactorIdWithResponse request returning *DeleteAdminUsersUserIdFactorsFactorIdResponse
+func (c *ClientWithResponses) DeleteAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdFactorsFactorIdResponse, error) {
+	rsp, err := c.DeleteAdminUsersUserIdFactorsFactorId(ctx, userId, factorId, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	return ParseDeleteAdminUsersUserIdFactorsFactorIdResponse(rsp)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r PutAdminUsersUserIdFactorsFactorIdResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// PutAdminUsersUserIdFactorsFactorIdWithBodyWithResponse request with arbitrary body returning *PutAdminUsersUserIdFactorsFactorIdResponse
+func (c *ClientWithResponses) PutAdminUsersUserIdFactorsFactorIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error) {
+	rsp, err := c.PutAdminUsersUserIdFactorsFactorIdWithBody(ctx, userId, factorId, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	return ParsePutAdminUsersUserIdFactorsFactorIdResponse(rsp)
 }
 
-type PostGenerateLinkResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *struct {
-		ActionLink           *string                `json:"action_link,omitempty"`
-		EmailOtp             *string                `json:"email_otp,omitempty"`
-		HashedToken          *string                `json:"hashed_token,omitempty"`
-		RedirectTo           *string                `json:"redirect_to,omitempty"`
-		VerificationType     *string                `json:"verification_type,omitempty"`
-		AdditionalProperties map[string]interface{} `json:"-"`
+func (c *ClientWithResponses) PutAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error) {
+	rsp, err := c.PutAdminUsersUserIdFactorsFactorId(ctx, userId, factorId, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	JSON400 *ErrorSchema
-	JSON401 *ErrorSchema
-	JSON403 *ErrorSchema
-	JSON404 *ErrorSchema
-	JSON422 *ErrorSchema
+	return ParsePutAdminUsersUserIdFactorsFactorIdResponse(rsp)
 }
 
-// Status returns HTTPResponse.Status
-func (r PostGenerateLinkResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// DeleteAdminUsersUserIdSessionsWithResponse request returning *DeleteAdminUsersUserIdSessionsResponse
+func (c *ClientWithResponses) DeleteAdminUsersUserIdSessionsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdSessionsResponse, error) {
+	rsp, err := c.DeleteAdminUsersUserIdSessions(ctx, userId, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	return ParseDeleteAdminUsersUserIdSessionsResponse(rsp)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r PostGenerateLinkResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+// GetAdminUsersUserIdSessionsWithResponse request returning *GetAdminUsersUserIdSessionsResponse
+func (c *ClientWithResponses) GetAdminUsersUserIdSessionsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdSessionsResponse, error) {
+	rsp, err := c.GetAdminUsersUserIdSessions(ctx, userId, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	return ParseGetAdminUsersUserIdSessionsResponse(rsp)
 }
 
-type PostInviteResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *UserSchema
-	JSON400      *ErrorSchema
-	JSON422      *ErrorSchema
+// DeleteAdminUsersUserIdSessionsSessionIdWithResponse request returning *DeleteAdminUsersUserIdSessionsSessionIdResponse
+func (c *ClientWithResponses) DeleteAdminUsersUserIdSessionsSessionIdWithResponse(ctx context.Context, userId openapi_types.UUID, sessionId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdSessionsSessionIdResponse, error) {
+	rsp, err := c.DeleteAdminUsersUserIdSessionsSessionId(ctx, userId, sessionId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteAdminUsersUserIdSessionsSessionIdResponse(rsp)
 }
 
-// Status returns HTTPResponse.Status
-func (r PostInviteResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// PostGenerateLinkWithBodyWithResponse request with arbitrary body returning *PostGenerateLinkResponse
+func (c *ClientWithResponses) PostGenerateLinkWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error) {
+	rsp, err := c.PostGenerateLinkWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
+	return ParsePostGenerateLinkResponse(rsp)
 }
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r PostInviteResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+func (c *ClientWithResponses) PostGenerateLinkWithResponse(ctx context.Context, body PostGenerateLinkJSONRequestBody, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error) {
+	rsp, err := c.PostGenerateLink(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	return ParsePostGenerateLinkResponse(rsp)
 }
 
-// GetAdminAuditWithResponse request returning *GetAdminAuditResponse
-func (c *ClientWithResponses) GetAdminAuditWithResponse(ctx context.Context, params *GetAdminAuditParams, reqEditors ...RequestEditorFn) (*GetAdminAuditResponse, error) {
-	rsp, err := c.GetAdminAudit(ctx, params, reqEditors...)
+// PostInviteWithBodyWithResponse request with arbitrary body returning *PostInviteResponse
+func (c *ClientWithResponses) PostInviteWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostInviteResponse, error) {
+	rsp, err := c.PostInviteWithBody(ctx, contentType, body, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetAdminAuditResponse(rsp)
+	return ParsePostInviteResponse(rsp)
 }
 
-// GetAdminSsoProvidersWithResponse request returning *GetAdminSsoProvidersResponse
-func (c *ClientWithResponses) GetAdminSsoProvidersWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersResponse, error) {
-	rsp, err := c.GetAdminSsoProviders(ctx, reqEditors...)
+func (c *ClientWithResponses) PostInviteWithResponse(ctx context.Context, body PostInviteJSONRequestBody, reqEditors ...RequestEditorFn) (*PostInviteResponse, error) {
+	rsp, err := c.PostInvite(ctx, body, reqEditors...)
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetAdminSsoProvidersResponse(rsp)
+	return ParsePostInviteResponse(rsp)
 }
 
-// PostAdminSsoProvidersWithBodyWithResponse request with arbitrary body returning *PostAdminSsoProvidersResponse
-func (c *ClientWithResponses) PostAdminSsoProvidersWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error) {
-	rsp, err := c.PostAdminSsoProvidersWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+// ParseGetAdminAuditResponse parses an HTTP response from a GetAdminAuditWithResponse call
+func ParseGetAdminAuditResponse(rsp *http.Response) (*GetAdminAuditResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAdminAuditResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []struct {
+			CreatedAt *time.Time          `json:"created_at,omitempty"`
+			Id        *openapi_types.UUID `json:"id,omitempty"`
+			IpAddress *string             `json:"ip_address,omitempty"`
+			Payload   *struct {
+				// Action Usually one of these values:
+				// - login
+				// - logout
+				// - invite_accepted
+				// - user_signedup
+				// - user_invited
+				// - user_deleted
+				// - user_modified
+				// - user_recovery_requested
+				// - user_reauthenticate_requested
+				// - user_reauthenticated
+				// - user_confirmation_requested
+				// - user_repeated_signup
+				// - user_updated_password
+				// - token_revoked
+				// - token_refreshed
+				// - generate_recovery_codes
+				// - factor_in_progress
+				// - factor_unenrolled
+				// - challenge_created
+				// - verification_attempted
+				// - factor_deleted
+				// - recovery_codes_deleted
+				// - factor_updated
+				// - mfa_code_login
+				Action        *string `json:"action,omitempty"`
+				ActorId       *string `json:"actor_id,omitempty"`
+				ActorName     *string `json:"actor_name,omitempty"`
+				ActorUsername *string `json:"actor_username,omitempty"`
+
+				// ActorViaSso Whether the actor used a SSO protocol (like SAML 2.0 or OIDC) to authenticate.
+				ActorViaSso *bool `json:"actor_via_sso,omitempty"`
+
+				// LogType Usually one of these values:
+				// - account
+				// - team
+				// - token
+				// - user
+				// - factor
+				// - recovery_codes
+				LogType *string                 `json:"log_type,omitempty"`
+				Traits  *map[string]interface{} `json:"traits,omitempty"`
+			} `json:"payload,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
 	}
-	return ParsePostAdminSsoProvidersResponse(rsp)
-}
 
-func (c *ClientWithResponses) PostAdminSsoProvidersWithResponse(ctx context.Context, body PostAdminSsoProvidersJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAdminSsoProvidersResponse, error) {
-	rsp, err := c.PostAdminSsoProviders(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
-	}
-	return ParsePostAdminSsoProvidersResponse(rsp)
+	return response, nil
 }
 
-// DeleteAdminSsoProvidersSsoProviderIdWithResponse request returning *DeleteAdminSsoProvidersSsoProviderIdResponse
-func (c *ClientWithResponses) DeleteAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminSsoProvidersSsoProviderIdResponse, error) {
-	rsp, err := c.DeleteAdminSsoProvidersSsoProviderId(ctx, ssoProviderId, reqEditors...)
+// ParseGetAdminServiceAccountsResponse parses an HTTP response from a GetAdminServiceAccountsWithResponse call
+func ParseGetAdminServiceAccountsResponse(rsp *http.Response) (*GetAdminServiceAccountsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseDeleteAdminSsoProvidersSsoProviderIdResponse(rsp)
-}
 
-// GetAdminSsoProvidersSsoProviderIdWithResponse request returning *GetAdminSsoProvidersSsoProviderIdResponse
-func (c *ClientWithResponses) GetAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminSsoProvidersSsoProviderIdResponse, error) {
-	rsp, err := c.GetAdminSsoProvidersSsoProviderId(ctx, ssoProviderId, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &GetAdminServiceAccountsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseGetAdminSsoProvidersSsoProviderIdResponse(rsp)
-}
 
-// PutAdminSsoProvidersSsoProviderIdWithBodyWithResponse request with arbitrary body returning *PutAdminSsoProvidersSsoProviderIdResponse
-func (c *ClientWithResponses) PutAdminSsoProvidersSsoProviderIdWithBodyWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error) {
-	rsp, err := c.PutAdminSsoProvidersSsoProviderIdWithBody(ctx, ssoProviderId, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Items *[]ServiceAccountSchema `json:"items,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
 	}
-	return ParsePutAdminSsoProvidersSsoProviderIdResponse(rsp)
+
+	return response, nil
 }
 
-func (c *ClientWithResponses) PutAdminSsoProvidersSsoProviderIdWithResponse(ctx context.Context, ssoProviderId openapi_types.UUID, body PutAdminSsoProvidersSsoProviderIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminSsoProvidersSsoProviderIdResponse, error) {
-	rsp, err := c.PutAdminSsoProvidersSsoProviderId(ctx, ssoProviderId, body, reqEditors...)
+// ParsePostAdminServiceAccountsResponse parses an HTTP response from a PostAdminServiceAccountsWithResponse call
+func ParsePostAdminServiceAccountsResponse(rsp *http.Response) (*PostAdminServiceAccountsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParsePutAdminSsoProvidersSsoProviderIdResponse(rsp)
-}
 
-// GetAdminUsersWithResponse request returning *GetAdminUsersResponse
-func (c *ClientWithResponses) GetAdminUsersWithResponse(ctx context.Context, params *GetAdminUsersParams, reqEditors ...RequestEditorFn) (*GetAdminUsersResponse, error) {
-	rsp, err := c.GetAdminUsers(ctx, params, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &PostAdminServiceAccountsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseGetAdminUsersResponse(rsp)
-}
 
-// DeleteAdminUsersUserIdWithResponse request returning *DeleteAdminUsersUserIdResponse
-func (c *ClientWithResponses) DeleteAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdResponse, error) {
-	rsp, err := c.DeleteAdminUsersUserId(ctx, userId, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest ServiceAccountSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
 	}
-	return ParseDeleteAdminUsersUserIdResponse(rsp)
+
+	return response, nil
 }
 
-// GetAdminUsersUserIdWithResponse request returning *GetAdminUsersUserIdResponse
-func (c *ClientWithResponses) GetAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdResponse, error) {
-	rsp, err := c.GetAdminUsersUserId(ctx, userId, reqEditors...)
+// ParseDeleteAdminServiceAccountsServiceAccountIdResponse parses an HTTP response from a DeleteAdminServiceAccountsServiceAccountIdWithResponse call
+func ParseDeleteAdminServiceAccountsServiceAccountIdResponse(rsp *http.Response) (*DeleteAdminServiceAccountsServiceAccountIdResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetAdminUsersUserIdResponse(rsp)
-}
 
-// PutAdminUsersUserIdWithBodyWithResponse request with arbitrary body returning *PutAdminUsersUserIdResponse
-func (c *ClientWithResponses) PutAdminUsersUserIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error) {
-	rsp, err := c.PutAdminUsersUserIdWithBody(ctx, userId, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &DeleteAdminServiceAccountsServiceAccountIdResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParsePutAdminUsersUserIdResponse(rsp)
-}
 
-func (c *ClientWithResponses) PutAdminUsersUserIdWithResponse(ctx context.Context, userId openapi_types.UUID, body PutAdminUsersUserIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdResponse, error) {
-	rsp, err := c.PutAdminUsersUserId(ctx, userId, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ServiceAccountSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
 	}
-	return ParsePutAdminUsersUserIdResponse(rsp)
+
+	return response, nil
 }
 
-// GetAdminUsersUserIdFactorsWithResponse request returning *GetAdminUsersUserIdFactorsResponse
-func (c *ClientWithResponses) GetAdminUsersUserIdFactorsWithResponse(ctx context.Context, userId openapi_types.UUID, reqEditors ...RequestEditorFn) (*GetAdminUsersUserIdFactorsResponse, error) {
-	rsp, err := c.GetAdminUsersUserIdFactors(ctx, userId, reqEditors...)
+// ParseGetAdminServiceAccountsServiceAccountIdResponse parses an HTTP response from a GetAdminServiceAccountsServiceAccountIdWithResponse call
+func ParseGetAdminServiceAccountsServiceAccountIdResponse(rsp *http.Response) (*GetAdminServiceAccountsServiceAccountIdResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParseGetAdminUsersUserIdFactorsResponse(rsp)
-}
 
-// DeleteAdminUsersUserIdFactorsFactorIdWithResponse request returning *DeleteAdminUsersUserIdFactorsFactorIdResponse
-func (c *ClientWithResponses) DeleteAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, reqEditors ...RequestEditorFn) (*DeleteAdminUsersUserIdFactorsFactorIdResponse, error) {
-	rsp, err := c.DeleteAdminUsersUserIdFactorsFactorId(ctx, userId, factorId, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &GetAdminServiceAccountsServiceAccountIdResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParseDeleteAdminUsersUserIdFactorsFactorIdResponse(rsp)
-}
 
-// PutAdminUsersUserIdFactorsFactorIdWithBodyWithResponse request with arbitrary body returning *PutAdminUsersUserIdFactorsFactorIdResponse
-func (c *ClientWithResponses) PutAdminUsersUserIdFactorsFactorIdWithBodyWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error) {
-	rsp, err := c.PutAdminUsersUserIdFactorsFactorIdWithBody(ctx, userId, factorId, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ServiceAccountSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
 	}
-	return ParsePutAdminUsersUserIdFactorsFactorIdResponse(rsp)
+
+	return response, nil
 }
 
-func (c *ClientWithResponses) PutAdminUsersUserIdFactorsFactorIdWithResponse(ctx context.Context, userId openapi_types.UUID, factorId openapi_types.UUID, body PutAdminUsersUserIdFactorsFactorIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PutAdminUsersUserIdFactorsFactorIdResponse, error) {
-	rsp, err := c.PutAdminUsersUserIdFactorsFactorId(ctx, userId, factorId, body, reqEditors...)
+// ParsePutAdminServiceAccountsServiceAccountIdResponse parses an HTTP response from a PutAdminServiceAccountsServiceAccountIdWithResponse call
+func ParsePutAdminServiceAccountsServiceAccountIdResponse(rsp *http.Response) (*PutAdminServiceAccountsServiceAccountIdResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParsePutAdminUsersUserIdFactorsFactorIdResponse(rsp)
-}
 
-// PostGenerateLinkWithBodyWithResponse request with arbitrary body returning *PostGenerateLinkResponse
-func (c *ClientWithResponses) PostGenerateLinkWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error) {
-	rsp, err := c.PostGenerateLinkWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &PutAdminServiceAccountsServiceAccountIdResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParsePostGenerateLinkResponse(rsp)
-}
 
-func (c *ClientWithResponses) PostGenerateLinkWithResponse(ctx context.Context, body PostGenerateLinkJSONRequestBody, reqEditors ...RequestEditorFn) (*PostGenerateLinkResponse, error) {
-	rsp, err := c.PostGenerateLink(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
-	}
-	return ParsePostGenerateLinkResponse(rsp)
-}
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ServiceAccountSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
 
-// PostInviteWithBodyWithResponse request with arbitrary body returning *PostInviteResponse
-func (c *ClientWithResponses) PostInviteWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostInviteResponse, error) {
-	rsp, err := c.PostInviteWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
 	}
-	return ParsePostInviteResponse(rsp)
-}
 
-func (c *ClientWithResponses) PostInviteWithResponse(ctx context.Context, body PostInviteJSONRequestBody, reqEditors ...RequestEditorFn) (*PostInviteResponse, error) {
-	rsp, err := c.PostInvite(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
-	}
-	return ParsePostInviteResponse(rsp)
+	return response, nil
 }
 
-// ParseGetAdminAuditResponse parses an HTTP response from a GetAdminAuditWithResponse call
-func ParseGetAdminAuditResponse(rsp *http.Response) (*GetAdminAuditResponse, error) {
+// ParsePostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse parses an HTTP response from a PostAdminServiceAccountsServiceAccountIdRegenerateSecretWithResponse call
+func ParsePostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse(rsp *http.Response) (*PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &GetAdminAuditResponse{
+	response := &PostAdminServiceAccountsServiceAccountIdRegenerateSecretResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest []struct {
-			CreatedAt *time.Time          `json:"created_at,omitempty"`
-			Id        *openapi_types.UUID `json:"id,omitempty"`
-			IpAddress *string             `json:"ip_address,omitempty"`
-			Payload   *struct {
-				// Action Usually one of these values:
-				// - login
-				// - logout
-				// - invite_accepted
-				// - user_signedup
-				// - user_invited
-				// - user_deleted
-				// - user_modified
-				// - user_recovery_requested
-				// - user_reauthenticate_requested
-				// - user_confirmation_requested
-				// - user_repeated_signup
-				// - user_updated_password
-				// - token_revoked
-				// - token_refreshed
-				// - generate_recovery_codes
-				// - factor_in_progress
-				// - factor_unenrolled
-				// - challenge_created
-				// - verification_attempted
-				// - factor_deleted
-				// - recovery_codes_deleted
-				// - factor_updated
-				// - mfa_code_login
-				Action        *string `json:"action,omitempty"`
-				ActorId       *string `json:"actor_id,omitempty"`
-				ActorName     *string `json:"actor_name,omitempty"`
-				ActorUsername *string `json:"actor_username,omitempty"`
-
-				// LogType Usually one of these values:
-				// - account
-				// - team
-				// - token
-				// - user
-				// - factor
-				// - recovery_codes
-				LogType *string                 `json:"log_type,omitempty"`
-				Traits  *map[string]interface{} `json:"traits,omitempty"`
-			} `json:"payload,omitempty"`
-		}
+		var dest ServiceAccountSchema
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -2012,6 +3363,13 @@ func ParseGetAdminAuditResponse(rsp *http.Response) (*GetAdminAuditResponse, err
 		}
 		response.JSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
 	}
 
 	return response, nil
@@ -2283,6 +3641,60 @@ func ParseGetAdminUsersResponse(rsp *http.Response) (*GetAdminUsersResponse, err
 	return response, nil
 }
 
+// ParsePostAdminUsersResponse parses an HTTP response from a PostAdminUsersWithResponse call
+func ParsePostAdminUsersResponse(rsp *http.Response) (*PostAdminUsersResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostAdminUsersResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest UserSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseDeleteAdminUsersUserIdResponse parses an HTTP response from a DeleteAdminUsersUserIdWithResponse call
 func ParseDeleteAdminUsersUserIdResponse(rsp *http.Response) (*DeleteAdminUsersUserIdResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -2565,6 +3977,135 @@ func ParsePutAdminUsersUserIdFactorsFactorIdResponse(rsp *http.Response) (*PutAd
 	return response, nil
 }
 
+// ParseDeleteAdminUsersUserIdSessionsResponse parses an HTTP response from a DeleteAdminUsersUserIdSessionsWithResponse call
+func ParseDeleteAdminUsersUserIdSessionsResponse(rsp *http.Response) (*DeleteAdminUsersUserIdSessionsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteAdminUsersUserIdSessionsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAdminUsersUserIdSessionsResponse parses an HTTP response from a GetAdminUsersUserIdSessionsWithResponse call
+func ParseGetAdminUsersUserIdSessionsResponse(rsp *http.Response) (*GetAdminUsersUserIdSessionsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAdminUsersUserIdSessionsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Sessions *[]SessionSchema `json:"sessions,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteAdminUsersUserIdSessionsSessionIdResponse parses an HTTP response from a DeleteAdminUsersUserIdSessionsSessionIdWithResponse call
+func ParseDeleteAdminUsersUserIdSessionsSessionIdResponse(rsp *http.Response) (*DeleteAdminUsersUserIdSessionsSessionIdResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteAdminUsersUserIdSessionsSessionIdResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorSchema
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParsePostGenerateLinkResponse parses an HTTP response from a PostGenerateLinkWithResponse call
 func ParsePostGenerateLinkResponse(rsp *http.Response) (*PostGenerateLinkResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -5,12 +5,14 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/storage"
 )
 
 var autoconfirm, isAdmin bool
 var audience string
+var batchSize int
 
 func getAudience(c *conf.GlobalConfiguration) string {
 	if audience == "" {
@@ -25,12 +27,14 @@ func adminCmd() *cobra.Command {
 		Use: "admin",
 	}
 
-	adminCmd.AddCommand(&adminCreateUserCmd, &adminDeleteUserCmd)
+	adminCmd.AddCommand(&adminCreateUserCmd, &adminDeleteUserCmd, &adminHashRefreshTokensCmd)
 	adminCmd.PersistentFlags().StringVarP(&audience, "aud", "a", "", "Set the new user's audience")
 
 	adminCreateUserCmd.Flags().BoolVar(&autoconfirm, "confirm", false, "Automatically confirm user without sending an email")
 	adminCreateUserCmd.Flags().BoolVar(&isAdmin, "admin", false, "Create user with admin privileges")
 
+	adminHashRefreshTokensCmd.Flags().IntVar(&batchSize, "batch-size", 1000, "Number of refresh tokens hashed per transaction")
+
 	return adminCmd
 }
 
@@ -58,6 +62,13 @@ var adminDeleteUserCmd = cobra.Command{
 	},
 }
 
+var adminHashRefreshTokensCmd = cobra.Command{
+	Use: "hashrefreshtokens",
+	Run: func(cmd *cobra.Command, args []string) {
+		execWithConfigAndArgs(cmd, adminHashRefreshTokens, args)
+	},
+}
+
 func adminCreateUser(config *conf.GlobalConfiguration, args []string) {
 	db, err := storage.Dial(config)
 	if err != nil {
@@ -72,7 +83,7 @@ func adminCreateUser(config *conf.GlobalConfiguration, args []string) {
 		logrus.Fatalf("Error checking user email: %+v", err)
 	}
 
-	user, err := models.NewUser("", args[0], args[1], aud, nil)
+	user, err := models.NewUser(crypto.NewPasswordHashParams(&config.PasswordHash), "", args[0], args[1], aud, nil)
 	if err != nil {
 		logrus.Fatalf("Error creating new user: %+v", err)
 	}
@@ -129,3 +140,42 @@ func adminDeleteUser(config *conf.GlobalConfiguration, args []string) {
 
 	logrus.Infof("Removed user: %s", args[0])
 }
+
+// adminHashRefreshTokens hashes the refresh tokens stored in plaintext before
+// a refresh token hash key was configured, which would otherwise only be
+// hashed when they are next used. Each batch is hashed in its own transaction
+// so the command can run against a live database.
+func adminHashRefreshTokens(config *conf.GlobalConfiguration, args []string) {
+	if config.Security.RefreshTokenHashKey == "" {
+		logrus.Fatal("GOTRUE_SECURITY_REFRESH_TOKEN_HASH_KEY must be set to hash refresh tokens")
+	}
+	if batchSize <= 0 {
+		logrus.Fatal("The batch size must be greater than 0")
+	}
+
+	db, err := storage.Dial(config)
+	if err != nil {
+		logrus.Fatalf("Error opening database: %+v", err)
+	}
+	defer db.Close()
+
+	total := 0
+	for {
+		var count int
+		err = db.Transaction(func(tx *storage.Connection) error {
+			var terr error
+			count, terr = models.HashPlaintextRefreshTokens(tx, config.Security.RefreshTokenHashKey, batchSize)
+			return terr
+		})
+		if err != nil {
+			logrus.Fatalf("Error hashing refresh tokens: %+v", err)
+		}
+
+		total += count
+		if count < batchSize {
+			break
+		}
+	}
+
+	logrus.Infof("Hashed %d refresh tokens stored in plaintext", total)
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
 )
 
 var migrateCmd = cobra.Command{
@@ -108,4 +112,39 @@ func migrate(cmd *cobra.Command, args []string) {
 			log.Fatalf("%+v", errors.Wrap(err, "migration status"))
 		}
 	}
+
+	if globalConfig.MFA.SecretEncryptionKeyID != "" {
+		encryptFactorSecrets(globalConfig, log)
+	}
+}
+
+// encryptFactorSecrets encrypts the factor secrets stored before encryption
+// was configured, and moves secrets encrypted with a retired key to the
+// active key.
+func encryptFactorSecrets(globalConfig *conf.GlobalConfiguration, log *logrus.Logger) {
+	keys, err := globalConfig.MFA.GetSecretEncryptionKeys()
+	if err != nil {
+		log.Fatalf("%+v", errors.Wrap(err, "loading MFA secret encryption keys"))
+	}
+	secretKeys := crypto.NewEnvelopeKeys(globalConfig.MFA.SecretEncryptionKeyID, keys)
+
+	db, err := storage.Dial(globalConfig)
+	if err != nil {
+		log.Fatalf("%+v", errors.Wrap(err, "opening db connection"))
+	}
+	defer db.Close()
+
+	var count int
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+		count, terr = models.EncryptFactorSecrets(tx, secretKeys)
+		return terr
+	})
+	if err != nil {
+		log.Fatalf("%+v", errors.Wrap(err, "encrypting MFA factor secrets"))
+	}
+
+	if count > 0 {
+		log.Infof("Encrypted %d MFA factor secrets with key %q", count, globalConfig.MFA.SecretEncryptionKeyID)
+	}
 }
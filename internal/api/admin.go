@@ -13,6 +13,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/sethvargo/go-password/password"
 	"github.com/supabase/gotrue/internal/api/provider"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/observability"
 	"github.com/supabase/gotrue/internal/storage"
@@ -24,11 +25,13 @@ type AdminUserParams struct {
 	Email        string                 `json:"email"`
 	Phone        string                 `json:"phone"`
 	Password     *string                `json:"password"`
+	PasswordHash string                 `json:"password_hash"`
 	EmailConfirm bool                   `json:"email_confirm"`
 	PhoneConfirm bool                   `json:"phone_confirm"`
 	UserMetaData map[string]interface{} `json:"user_metadata"`
 	AppMetaData  map[string]interface{} `json:"app_metadata"`
 	BanDuration  string                 `json:"ban_duration"`
+	MFARequired  *bool                  `json:"mfa_required"`
 }
 
 type adminUserDeleteParams struct {
@@ -97,6 +100,15 @@ func (a *API) getAdminParams(r *http.Request) (*AdminUserParams, error) {
 		return nil, badRequestError("Could not decode admin user params: %v", err)
 	}
 
+	if params.PasswordHash != "" {
+		if params.Password != nil {
+			return nil, badRequestError("Only a password or a password hash should be provided")
+		}
+		if err := crypto.ValidatePasswordHash(params.PasswordHash); err != nil {
+			return nil, badRequestError("Password hash is invalid or in an unsupported format").WithInternalError(err)
+		}
+	}
+
 	return &params, nil
 }
 
@@ -195,12 +207,24 @@ func (a *API) adminUserUpdate(w http.ResponseWriter, r *http.Request) error {
 			}
 		}
 
+		if params.MFARequired != nil {
+			if terr := user.SetMFARequired(tx, *params.MFARequired); terr != nil {
+				return terr
+			}
+		}
+
 		if params.Password != nil {
 			if len(*params.Password) < config.PasswordMinLength {
 				return invalidPasswordLengthError(config.PasswordMinLength)
 			}
 
-			if terr := user.UpdatePassword(tx, *params.Password, nil); terr != nil {
+			if terr := user.UpdatePassword(tx, crypto.NewPasswordHashParams(&config.PasswordHash), *params.Password, nil); terr != nil {
+				return terr
+			}
+		}
+
+		if params.PasswordHash != "" {
+			if terr := user.UpdatePasswordHash(tx, params.PasswordHash, nil); terr != nil {
 				return terr
 			}
 		}
@@ -341,15 +365,20 @@ func (a *API) adminUserCreate(w http.ResponseWriter, r *http.Request) error {
 		providers = append(providers, "phone")
 	}
 
-	if params.Password == nil || *params.Password == "" {
-		password, err := password.Generate(64, 10, 0, false, true)
-		if err != nil {
-			return internalServerError("Error generating password").WithInternalError(err)
+	var user *models.User
+	if params.PasswordHash != "" {
+		user, err = models.NewUserWithPasswordHash(params.Phone, params.Email, params.PasswordHash, aud, params.UserMetaData)
+	} else {
+		if params.Password == nil || *params.Password == "" {
+			password, err := password.Generate(64, 10, 0, false, true)
+			if err != nil {
+				return internalServerError("Error generating password").WithInternalError(err)
+			}
+			params.Password = &password
 		}
-		params.Password = &password
-	}
 
-	user, err := models.NewUser(params.Phone, params.Email, *params.Password, aud, params.UserMetaData)
+		user, err = models.NewUser(crypto.NewPasswordHashParams(&config.PasswordHash), params.Phone, params.Email, *params.Password, aud, params.UserMetaData)
+	}
 	if err != nil {
 		return internalServerError("Error creating user").WithInternalError(err)
 	}
@@ -427,6 +456,12 @@ func (a *API) adminUserCreate(w http.ResponseWriter, r *http.Request) error {
 			}
 		}
 
+		if params.MFARequired != nil {
+			if terr := user.SetMFARequired(tx, *params.MFARequired); terr != nil {
+				return terr
+			}
+		}
+
 		if params.BanDuration != "" {
 			duration := time.Duration(0)
 			if params.BanDuration != "none" {
@@ -555,6 +590,93 @@ func (a *API) adminUserGetFactors(w http.ResponseWriter, r *http.Request) error
 	return sendJSON(w, http.StatusOK, factors)
 }
 
+// adminUserGetSessions returns the sessions of a user
+func (a *API) adminUserGetSessions(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+	sessions, err := models.FindAllSessionsByUserID(a.db, user.ID)
+	if err != nil {
+		return internalServerError("Database error finding sessions").WithInternalError(err)
+	}
+	return sendJSON(w, http.StatusOK, newSessionsResponse(sessions, nil))
+}
+
+// adminUserDeleteSessions logs a user out of all of their sessions
+func (a *API) adminUserDeleteSessions(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+	adminUser := getAdminUser(ctx)
+
+	err := a.db.Transaction(func(tx *storage.Connection) error {
+		if terr := models.NewAuditLogEntry(r, tx, adminUser, models.LogoutAction, "", map[string]interface{}{
+			"user_id": user.ID,
+		}); terr != nil {
+			return internalServerError("Error recording audit log entry").WithInternalError(terr)
+		}
+
+		if terr := models.Logout(tx, user.ID); terr != nil {
+			return internalServerError("Error deleting user's sessions").WithInternalError(terr)
+		}
+
+		// refresh tokens issued before sessions were introduced are not
+		// deleted with the sessions
+		if terr := models.LogoutAllRefreshTokens(tx, user.ID); terr != nil {
+			return internalServerError("Error revoking user's refresh tokens").WithInternalError(terr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// adminUserDeleteSession logs a user out of a single session
+func (a *API) adminUserDeleteSession(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+	adminUser := getAdminUser(ctx)
+
+	sessionID, err := uuid.FromString(chi.URLParam(r, "session_id"))
+	if err != nil {
+		return badRequestError("session_id must be an UUID")
+	}
+
+	session, err := models.FindSessionByID(a.db, sessionID, false)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return notFoundError("Session not found")
+		}
+		return internalServerError("Database error finding session").WithInternalError(err)
+	}
+
+	if session.UserID != user.ID {
+		return notFoundError("Session not found")
+	}
+
+	err = a.db.Transaction(func(tx *storage.Connection) error {
+		if terr := models.NewAuditLogEntry(r, tx, adminUser, models.LogoutAction, "", map[string]interface{}{
+			"user_id":    user.ID,
+			"session_id": session.ID,
+		}); terr != nil {
+			return internalServerError("Error recording audit log entry").WithInternalError(terr)
+		}
+
+		if terr := models.LogoutSession(tx, session.ID); terr != nil {
+			return internalServerError("Error deleting session").WithInternalError(terr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 // adminUserUpdate updates a single factor object
 func (a *API) adminUserUpdateFactor(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
@@ -10,11 +10,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	jwt "github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 )
 
@@ -60,6 +62,38 @@ func (ts *AdminTestSuite) TestAdminUsersUnauthorized() {
 	assert.Equal(ts.T(), http.StatusUnauthorized, w.Code)
 }
 
+// TestAdminUsersMFAPolicy tests that admins required to use MFA can't use
+// the admin API below aal2 once their grace period has ended
+func (ts *AdminTestSuite) TestAdminUsersMFAPolicy() {
+	for _, example := range []struct {
+		aal      string
+		enrollBy time.Time
+		code     int
+	}{
+		{aal: "aal1", enrollBy: time.Now().Add(-time.Minute), code: http.StatusForbidden},
+		{aal: "aal1", enrollBy: time.Now().Add(time.Hour), code: http.StatusOK},
+		{aal: "aal2", enrollBy: time.Now().Add(-time.Minute), code: http.StatusOK},
+	} {
+		claims := &GoTrueClaims{
+			Role:                        "supabase_admin",
+			AuthenticatorAssuranceLevel: example.aal,
+			MFAPolicy: &MFAPolicyClaims{
+				Required: true,
+				EnrollBy: example.enrollBy.Unix(),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.Config.JWT.Secret))
+		require.NoError(ts.T(), err)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+		ts.API.handler.ServeHTTP(w, req)
+		require.Equal(ts.T(), example.code, w.Code, example.aal)
+	}
+}
+
 // TestAdminUsers tests API /admin/users route
 func (ts *AdminTestSuite) TestAdminUsers() {
 	// Setup request
@@ -77,11 +111,11 @@ func (ts *AdminTestSuite) TestAdminUsers() {
 
 // TestAdminUsers tests API /admin/users route
 func (ts *AdminTestSuite) TestAdminUsers_Pagination() {
-	u, err := models.NewUser("12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	u, err = models.NewUser("987654321", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err = models.NewUser(crypto.DefaultPasswordHashParams, "987654321", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -106,12 +140,12 @@ func (ts *AdminTestSuite) TestAdminUsers_Pagination() {
 
 // TestAdminUsers tests API /admin/users route
 func (ts *AdminTestSuite) TestAdminUsers_SortAsc() {
-	u, err := models.NewUser("", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	u.CreatedAt = time.Now().Add(-time.Minute)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	u, err = models.NewUser("", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err = models.NewUser(crypto.DefaultPasswordHashParams, "", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
 	u.CreatedAt = time.Now()
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
@@ -141,12 +175,12 @@ func (ts *AdminTestSuite) TestAdminUsers_SortAsc() {
 
 // TestAdminUsers tests API /admin/users route
 func (ts *AdminTestSuite) TestAdminUsers_SortDesc() {
-	u, err := models.NewUser("12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	u.CreatedAt = time.Now().Add(-time.Minute)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	u, err = models.NewUser("987654321", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err = models.NewUser(crypto.DefaultPasswordHashParams, "987654321", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
 	u.CreatedAt = time.Now()
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
@@ -173,7 +207,7 @@ func (ts *AdminTestSuite) TestAdminUsers_SortDesc() {
 
 // TestAdminUsers tests API /admin/users route
 func (ts *AdminTestSuite) TestAdminUsers_FilterEmail() {
-	u, err := models.NewUser("", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -198,11 +232,11 @@ func (ts *AdminTestSuite) TestAdminUsers_FilterEmail() {
 
 // TestAdminUsers tests API /admin/users route
 func (ts *AdminTestSuite) TestAdminUsers_FilterName() {
-	u, err := models.NewUser("", "test1@example.com", "test", ts.Config.JWT.Aud, map[string]interface{}{"full_name": "Test User"})
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test1@example.com", "test", ts.Config.JWT.Aud, map[string]interface{}{"full_name": "Test User"})
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	u, err = models.NewUser("", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err = models.NewUser(crypto.DefaultPasswordHashParams, "", "test2@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -358,9 +392,55 @@ func (ts *AdminTestSuite) TestAdminUserCreate() {
 	}
 }
 
+func (ts *AdminTestSuite) TestAdminUserCreateWithPasswordHash() {
+	// the example of the Firebase scrypt documentation
+	passwordHash := "$fbscrypt$v=1,n=14,r=8,p=1,ss=Bw==,sk=jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==$42xEC+ixf3L2lw==$lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ=="
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/admin/users", ts.token, map[string]interface{}{
+		"email":         "test1@example.com",
+		"password_hash": "$2a$10$invalid",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/admin/users", ts.token, map[string]interface{}{
+		"email":         "test1@example.com",
+		"password":      "user1password",
+		"password_hash": passwordHash,
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/admin/users", ts.token, map[string]interface{}{
+		"email":         "test1@example.com",
+		"password_hash": passwordHash,
+		"email_confirm": true,
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := models.User{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+
+	u, err := models.FindUserByID(ts.API.db, data.ID)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), passwordHash, u.EncryptedPassword)
+	require.True(ts.T(), u.Authenticate("user1password"))
+
+	// the imported hash is replaced with a native one on sign in
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=password", "", map[string]interface{}{
+		"email":    "test1@example.com",
+		"password": "user1password",
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	u, err = models.FindUserByID(ts.API.db, data.ID)
+	require.NoError(ts.T(), err)
+	require.NotEqual(ts.T(), passwordHash, u.EncryptedPassword)
+	require.False(ts.T(), crypto.NeedsRehash(crypto.NewPasswordHashParams(&ts.Config.PasswordHash), u.EncryptedPassword))
+	require.True(ts.T(), u.Authenticate("user1password"))
+}
+
 // TestAdminUserGet tests API /admin/user route (GET)
 func (ts *AdminTestSuite) TestAdminUserGet() {
-	u, err := models.NewUser("12345678", "test1@example.com", "test", ts.Config.JWT.Aud, map[string]interface{}{"full_name": "Test Get User"})
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "12345678", "test1@example.com", "test", ts.Config.JWT.Aud, map[string]interface{}{"full_name": "Test Get User"})
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -386,7 +466,7 @@ func (ts *AdminTestSuite) TestAdminUserGet() {
 
 // TestAdminUserUpdate tests API /admin/user route (UPDATE)
 func (ts *AdminTestSuite) TestAdminUserUpdate() {
-	u, err := models.NewUser("12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -404,6 +484,7 @@ func (ts *AdminTestSuite) TestAdminUserUpdate() {
 		"ban_duration": "24h",
 		"email":        newEmail,
 		"phone":        newPhone,
+		"mfa_required": true,
 	}))
 
 	// Setup request
@@ -429,9 +510,11 @@ func (ts *AdminTestSuite) TestAdminUserUpdate() {
 	assert.Contains(ts.T(), data.AppMetaData["roles"], "writer")
 	assert.Contains(ts.T(), data.AppMetaData["roles"], "editor")
 	assert.NotNil(ts.T(), data.BannedUntil)
+	assert.True(ts.T(), data.MFARequired)
 
 	u, err = models.FindUserByID(ts.API.db, data.ID)
 	require.NoError(ts.T(), err)
+	assert.NotNil(ts.T(), u.MFARequiredSince)
 
 	// check if the corresponding identities were successfully created
 	require.NotEmpty(ts.T(), u.Identities)
@@ -451,7 +534,7 @@ func (ts *AdminTestSuite) TestAdminUserUpdate() {
 }
 
 func (ts *AdminTestSuite) TestAdminUserUpdatePasswordFailed() {
-	u, err := models.NewUser("12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "12345678", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -474,7 +557,7 @@ func (ts *AdminTestSuite) TestAdminUserUpdatePasswordFailed() {
 }
 
 func (ts *AdminTestSuite) TestAdminUserUpdateBannedUntilFailed() {
-	u, err := models.NewUser("", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test1@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
@@ -581,7 +664,7 @@ func (ts *AdminTestSuite) TestAdminUserDelete() {
 
 func (ts *AdminTestSuite) TestAdminUserSoftDeletion() {
 	// create user
-	u, err := models.NewUser("123456789", "test@example.com", "secret", ts.Config.JWT.Aud, map[string]interface{}{"name": "test"})
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test@example.com", "secret", ts.Config.JWT.Aud, map[string]interface{}{"name": "test"})
 	require.NoError(ts.T(), err)
 	u.ConfirmationToken = "some_token"
 	u.RecoveryToken = "some_token"
@@ -715,11 +798,11 @@ func (ts *AdminTestSuite) TestAdminUserCreateWithDisabledLogin() {
 
 // TestAdminUserDeleteFactor tests API /admin/users/<user_id>/factors/<factor_id>/
 func (ts *AdminTestSuite) TestAdminUserDeleteFactor() {
-	u, err := models.NewUser("123456789", "test-delete@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test-delete@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	f, err := models.NewFactor(u, "testSimpleName", models.TOTP, models.FactorStateVerified, "secretkey")
+	f, err := models.NewFactor(nil, u, "testSimpleName", models.TOTP, models.FactorStateVerified, "secretkey")
 
 	require.NoError(ts.T(), err, "Error creating test factor model")
 	require.NoError(ts.T(), ts.API.db.Create(f), "Error saving new test factor")
@@ -740,11 +823,11 @@ func (ts *AdminTestSuite) TestAdminUserDeleteFactor() {
 
 // TestAdminUserGetFactor tests API /admin/user/<user_id>/factors/
 func (ts *AdminTestSuite) TestAdminUserGetFactors() {
-	u, err := models.NewUser("123456789", "test-delete@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test-delete@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	f, err := models.NewFactor(u, "testSimpleName", models.TOTP, models.FactorStateUnverified, "secretkey")
+	f, err := models.NewFactor(nil, u, "testSimpleName", models.TOTP, models.FactorStateUnverified, "secretkey")
 	require.NoError(ts.T(), err, "Error creating test factor model")
 	require.NoError(ts.T(), ts.API.db.Create(f), "Error saving new test factor")
 
@@ -762,11 +845,11 @@ func (ts *AdminTestSuite) TestAdminUserGetFactors() {
 }
 
 func (ts *AdminTestSuite) TestAdminUserUpdateFactor() {
-	u, err := models.NewUser("123456789", "test-delete@example.com", "test", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test-delete@example.com", "test", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error making new user")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
 
-	f, err := models.NewFactor(u, "testSimpleName", models.TOTP, models.FactorStateUnverified, "secretkey")
+	f, err := models.NewFactor(nil, u, "testSimpleName", models.TOTP, models.FactorStateUnverified, "secretkey")
 	require.NoError(ts.T(), err, "Error creating test factor model")
 	require.NoError(ts.T(), ts.API.db.Create(f), "Error saving new test factor")
 
@@ -813,3 +896,60 @@ func (ts *AdminTestSuite) TestAdminUserUpdateFactor() {
 	}
 
 }
+
+func (ts *AdminTestSuite) TestAdminUserSessions() {
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test-sessions@example.com", "test", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error making new user")
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error creating user")
+
+	other, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "other-sessions@example.com", "test", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error making new user")
+	require.NoError(ts.T(), ts.API.db.Create(other), "Error creating user")
+
+	var sessionIDs []uuid.UUID
+	for i := 0; i < 2; i++ {
+		token, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
+		require.NoError(ts.T(), err)
+		sessionIDs = append(sessionIDs, *token.SessionId)
+	}
+
+	otherToken, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, other, models.GrantParams{})
+	require.NoError(ts.T(), err)
+
+	listSessions := func() *SessionsResponse {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/admin/users/%s/sessions", u.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ts.token))
+		ts.API.handler.ServeHTTP(w, req)
+		require.Equal(ts.T(), http.StatusOK, w.Code)
+
+		data := &SessionsResponse{}
+		require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+		return data
+	}
+
+	deleteSessions := func(path string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/admin/users/%s/sessions%s", u.ID, path), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ts.token))
+		ts.API.handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Len(ts.T(), listSessions().Sessions, 2)
+
+	// sessions of other users cannot be deleted through this user
+	require.Equal(ts.T(), http.StatusNotFound, deleteSessions("/"+otherToken.SessionId.String()))
+
+	require.Equal(ts.T(), http.StatusNoContent, deleteSessions("/"+sessionIDs[0].String()))
+
+	sessions := listSessions().Sessions
+	require.Len(ts.T(), sessions, 1)
+	require.Equal(ts.T(), sessionIDs[1], sessions[0].ID)
+
+	require.Equal(ts.T(), http.StatusNoContent, deleteSessions(""))
+	require.Len(ts.T(), listSessions().Sessions, 0)
+
+	_, err = models.FindSessionByID(ts.API.db, *otherToken.SessionId, false)
+	require.NoError(ts.T(), err, "Sessions of other users should not be deleted")
+}
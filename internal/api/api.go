@@ -101,6 +101,8 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 		r.Use(api.isValidExternalHost)
 
 		r.Get("/settings", api.Settings)
+		r.Get("/.well-known/jwks.json", api.JWKS)
+		r.With(api.requireOIDCEnabled).Get("/.well-known/openid-configuration", api.OpenIDConfiguration)
 
 		r.Get("/authorize", api.ExternalProviderRedirect)
 
@@ -113,12 +115,18 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 
 		r.With(sharedLimiter).With(api.verifyCaptcha).Post("/otp", api.Otp)
 
-		r.With(api.limitHandler(
+		// introspection and revocation authenticate clients with their
+		// secret, so they share the limiter of /token
+		tokenLimiter := api.limitHandler(
 			// Allow requests at the specified rate per 5 minutes.
 			tollbooth.NewLimiter(api.config.RateLimitTokenRefresh/(60*5), &limiter.ExpirableOptions{
 				DefaultExpirationTTL: time.Hour,
 			}).SetBurst(30),
-		)).With(api.verifyCaptcha).Post("/token", api.Token)
+		)
+		r.With(tokenLimiter).With(api.verifyCaptcha).Post("/token", api.Token)
+
+		r.With(tokenLimiter).Post("/token/introspect", api.TokenIntrospect)
+		r.With(tokenLimiter).Post("/token/revoke", api.TokenRevoke)
 
 		r.With(api.limitHandler(
 			// Allow requests at the specified rate per 5 minutes.
@@ -132,17 +140,47 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 
 		r.With(api.requireAuthentication).Post("/logout", api.Logout)
 
-		r.With(api.requireAuthentication).Route("/reauthenticate", func(r *router) {
+		r.With(api.requireAuthentication).With(api.requireFirstPartySession).With(api.requireMFAPolicy).Route("/reauthenticate", func(r *router) {
 			r.Get("/", api.Reauthenticate)
+			r.With(api.limitHandler(
+				// Allow requests at the specified rate per 5 minutes.
+				tollbooth.NewLimiter(api.config.RateLimitVerify/(60*5), &limiter.ExpirableOptions{
+					DefaultExpirationTTL: time.Hour,
+				}).SetBurst(30),
+			)).Post("/", api.ReauthenticateVerify)
 		})
 
-		r.With(api.requireAuthentication).Route("/user", func(r *router) {
+		r.With(api.requireOIDCEnabled).With(api.requireAuthentication).With(api.requireMFAPolicy).Route("/userinfo", func(r *router) {
+			r.Get("/", api.UserInfo)
+			r.Post("/", api.UserInfo)
+		})
+
+		r.With(api.requireAuthentication).With(api.requireFirstPartySession).Route("/user", func(r *router) {
 			r.Get("/", api.UserGet)
-			r.With(sharedLimiter).Put("/", api.UserUpdate)
+			r.With(sharedLimiter).With(api.requireMFAPolicy).Put("/", api.UserUpdate)
+
+			r.With(api.requireMFAPolicy).Route("/sessions", func(r *router) {
+				r.Get("/", api.UserSessionsList)
+				r.Delete("/{session_id}", api.UserSessionDelete)
+			})
 		})
 
-		r.With(api.requireAuthentication).Route("/factors", func(r *router) {
+		r.With(api.requireAuthentication).With(api.requireFirstPartySession).Route("/factors", func(r *router) {
 			r.Post("/", api.EnrollFactor)
+			r.Route("/recovery_codes", func(r *router) {
+				r.Get("/", api.GetRecoveryCodes)
+				r.Post("/", api.GenerateRecoveryCodes)
+				r.Delete("/", api.DeleteRecoveryCodes)
+				r.With(api.limitHandler(
+					tollbooth.NewLimiter(api.config.MFA.RateLimitChallengeAndVerify/60, &limiter.ExpirableOptions{
+						DefaultExpirationTTL: time.Minute,
+					}).SetBurst(30))).Post("/redeem", api.RedeemRecoveryCode)
+			})
+			r.Route("/trusted_devices", func(r *router) {
+				r.Get("/", api.ListTrustedDevices)
+				r.Delete("/", api.DeleteTrustedDevices)
+				r.Delete("/{device_id}", api.DeleteTrustedDevice)
+			})
 			r.Route("/{factor_id}", func(r *router) {
 				r.Use(api.loadFactor)
 
@@ -159,6 +197,44 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 			})
 		})
 
+		r.With(api.requireDeviceAuthorizationEnabled).Route("/device", func(r *router) {
+			r.With(api.limitHandler(
+				// Allow requests at the specified rate per 5 minutes.
+				tollbooth.NewLimiter(api.config.RateLimitTokenRefresh/(60*5), &limiter.ExpirableOptions{
+					DefaultExpirationTTL: time.Hour,
+				}).SetBurst(30),
+			)).Post("/code", api.DeviceCode)
+
+			r.With(api.limitHandler(
+				// Allow requests at the specified rate per 5 minutes, as
+				// user codes are short enough to be guessed otherwise.
+				tollbooth.NewLimiter(api.config.RateLimitVerify/(60*5), &limiter.ExpirableOptions{
+					DefaultExpirationTTL: time.Hour,
+				}).SetBurst(30),
+			)).With(api.requireAuthentication).With(api.requireFirstPartySession).With(api.requireMFAPolicy).Route("/authorizations/{user_code}", func(r *router) {
+				r.Get("/", api.DeviceAuthorizationGet)
+				r.Post("/", api.DeviceAuthorizationApproval)
+			})
+		})
+
+		r.With(api.requirePasskeyLoginEnabled).Route("/passkey", func(r *router) {
+			r.With(api.limitHandler(
+				// Allow requests at the specified rate per 5 minutes.
+				tollbooth.NewLimiter(api.config.RateLimitTokenRefresh/(60*5), &limiter.ExpirableOptions{
+					DefaultExpirationTTL: time.Hour,
+				}).SetBurst(30),
+			)).Post("/challenge", api.PasskeyChallenge)
+		})
+
+		r.With(api.requireOAuthServerEnabled).Route("/oauth", func(r *router) {
+			r.Get("/authorize", api.OAuthAuthorize)
+
+			r.With(api.requireAuthentication).With(api.requireFirstPartySession).With(api.requireMFAPolicy).Route("/authorizations/{authorization_id}", func(r *router) {
+				r.Get("/", api.OAuthAuthorizationGet)
+				r.Post("/", api.OAuthAuthorizationConsent)
+			})
+		})
+
 		r.Route("/sso", func(r *router) {
 			r.Use(api.requireSAMLEnabled)
 			r.With(api.limitHandler(
@@ -182,6 +258,7 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 
 		r.Route("/admin", func(r *router) {
 			r.Use(api.requireAdminCredentials)
+			r.Use(api.requireMFAPolicy)
 
 			r.Route("/audit", func(r *router) {
 				r.Get("/", api.adminAuditLog)
@@ -202,6 +279,12 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 						})
 					})
 
+					r.Route("/sessions", func(r *router) {
+						r.Get("/", api.adminUserGetSessions)
+						r.Delete("/", api.adminUserDeleteSessions)
+						r.Delete("/{session_id}", api.adminUserDeleteSession)
+					})
+
 					r.Get("/", api.adminUserGet)
 					r.Put("/", api.adminUserUpdate)
 					r.Delete("/", api.adminUserDelete)
@@ -210,6 +293,36 @@ func NewAPIWithVersion(ctx context.Context, globalConfig *conf.GlobalConfigurati
 
 			r.Post("/generate_link", api.GenerateLink)
 
+			r.Route("/oauth", func(r *router) {
+				r.Route("/clients", func(r *router) {
+					r.Get("/", api.adminOAuthClientsList)
+					r.Post("/", api.adminOAuthClientsCreate)
+
+					r.Route("/{client_id}", func(r *router) {
+						r.Use(api.loadOAuthClient)
+
+						r.Get("/", api.adminOAuthClientsGet)
+						r.Put("/", api.adminOAuthClientsUpdate)
+						r.Delete("/", api.adminOAuthClientsDelete)
+						r.Post("/regenerate_secret", api.adminOAuthClientsRegenerateSecret)
+					})
+				})
+			})
+
+			r.Route("/service_accounts", func(r *router) {
+				r.Get("/", api.adminServiceAccountsList)
+				r.Post("/", api.adminServiceAccountsCreate)
+
+				r.Route("/{service_account_id}", func(r *router) {
+					r.Use(api.loadServiceAccount)
+
+					r.Get("/", api.adminServiceAccountsGet)
+					r.Put("/", api.adminServiceAccountsUpdate)
+					r.Delete("/", api.adminServiceAccountsDelete)
+					r.Post("/regenerate_secret", api.adminServiceAccountsRegenerateSecret)
+				})
+			})
+
 			r.Route("/sso", func(r *router) {
 				r.Route("/providers", func(r *router) {
 					r.Get("/", api.adminSSOProvidersList)
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -49,6 +52,24 @@ func setupAPIForTestWithCallback(cb func(*conf.GlobalConfiguration, *storage.Con
 	return NewAPIWithVersion(context.Background(), config, conn, apiTestVersion), config, nil
 }
 
+// performJSONRequest sends a request with the JSON encoded body to the API
+// and records the response. The token is sent as a bearer token when set.
+func performJSONRequest(t *testing.T, api *API, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var buffer bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buffer).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, "http://localhost"+path, &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	api.handler.ServeHTTP(w, req)
+	return w
+}
+
 func TestEmailEnabledByDefault(t *testing.T) {
 	api, _, err := setupAPIForTest()
 	require.NoError(t, err)
@@ -4,12 +4,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	jwt "github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 )
 
@@ -35,7 +37,7 @@ func (ts *AuthTestSuite) SetupTest() {
 	models.TruncateAll(ts.API.db)
 
 	// Create user
-	u, err := models.NewUser("", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
 }
@@ -71,6 +73,81 @@ func (ts *AuthTestSuite) TestParseJWTClaims() {
 	require.Equal(ts.T(), userJwt, token.Raw)
 }
 
+func (ts *AuthTestSuite) TestParseJWTClaimsWithRetiredKey() {
+	defer func(keyID string, retired conf.JWTRetiredKeys) {
+		ts.Config.JWT.KeyID = keyID
+		ts.Config.JWT.RetiredKeys = retired
+	}(ts.Config.JWT.KeyID, ts.Config.JWT.RetiredKeys)
+
+	ts.Config.JWT.KeyID = "current"
+	ts.Config.JWT.RetiredKeys = conf.JWTRetiredKeys{
+		{
+			KeyID:     "retired",
+			Algorithm: conf.JWTAlgorithmHS256,
+			Secret:    "retired-secret",
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+		{
+			KeyID:     "expired",
+			Algorithm: conf.JWTAlgorithmHS256,
+			Secret:    "expired-secret",
+			ExpiresAt: time.Now().Add(-time.Hour),
+		},
+	}
+
+	cases := []struct {
+		desc    string
+		kid     string
+		secret  string
+		isValid bool
+	}{
+		{
+			desc:    "Current key",
+			kid:     "current",
+			secret:  ts.Config.JWT.Secret,
+			isValid: true,
+		},
+		{
+			desc:    "Retired key within grace period",
+			kid:     "retired",
+			secret:  "retired-secret",
+			isValid: true,
+		},
+		{
+			desc:    "Retired key after grace period",
+			kid:     "expired",
+			secret:  "expired-secret",
+			isValid: false,
+		},
+		{
+			desc:    "Retired secret with current kid",
+			kid:     "current",
+			secret:  "retired-secret",
+			isValid: false,
+		},
+	}
+
+	for _, c := range cases {
+		ts.Run(c.desc, func() {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, &GoTrueClaims{
+				Role: "authenticated",
+			})
+			token.Header["kid"] = c.kid
+
+			userJwt, err := token.SignedString([]byte(c.secret))
+			require.NoError(ts.T(), err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			_, err = ts.API.parseJWTClaims(userJwt, req)
+			if c.isValid {
+				require.NoError(ts.T(), err)
+			} else {
+				require.Error(ts.T(), err)
+			}
+		})
+	}
+}
+
 func (ts *AuthTestSuite) TestMaybeLoadUserOrSession() {
 	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
 	require.NoError(ts.T(), err)
@@ -31,6 +31,8 @@ const (
 	ssoProviderKey          = contextKey("sso_provider")
 	externalHostKey         = contextKey("external_host")
 	flowStateKey            = contextKey("flow_state_id")
+	oauthClientKey          = contextKey("oauth_client")
+	serviceAccountKey       = contextKey("service_account")
 )
 
 // withToken adds the JWT token to the context.
@@ -238,6 +240,30 @@ func getSSOProvider(ctx context.Context) *models.SSOProvider {
 	return obj.(*models.SSOProvider)
 }
 
+func withOAuthClient(ctx context.Context, client *models.OAuthClient) context.Context {
+	return context.WithValue(ctx, oauthClientKey, client)
+}
+
+func getOAuthClient(ctx context.Context) *models.OAuthClient {
+	obj := ctx.Value(oauthClientKey)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*models.OAuthClient)
+}
+
+func withServiceAccount(ctx context.Context, account *models.ServiceAccount) context.Context {
+	return context.WithValue(ctx, serviceAccountKey, account)
+}
+
+func getServiceAccount(ctx context.Context) *models.ServiceAccount {
+	obj := ctx.Value(serviceAccountKey)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*models.ServiceAccount)
+}
+
 func withExternalHost(ctx context.Context, u *url.URL) context.Context {
 	return context.WithValue(ctx, externalHostKey, u)
 }
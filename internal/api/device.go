@@ -0,0 +1,286 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/supabase/gotrue/internal/metering"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// DeviceCodeGrantType is the grant_type used by devices to poll the token
+// endpoint, see RFC 8628 Section 3.4.
+const DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceCodeResponse is the response of the device authorization endpoint,
+// see RFC 8628 Section 3.2.
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceAuthorizationResponse describes a pending device authorization so
+// that the site can ask the user to approve it.
+type DeviceAuthorizationResponse struct {
+	UserCode  string    `json:"user_code"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DeviceAuthorizationApprovalParams are the parameters the
+// DeviceAuthorizationApproval method accepts.
+type DeviceAuthorizationApprovalParams struct {
+	Approve bool `json:"approve"`
+}
+
+// DeviceCodeGrantParams are the parameters the DeviceCodeGrant method
+// accepts.
+type DeviceCodeGrantParams struct {
+	DeviceCode string `json:"device_code"`
+}
+
+func (a *API) requireDeviceAuthorizationEnabled(w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	if !a.config.DeviceAuthorization.Enabled {
+		return nil, notFoundError("Device authorization is disabled")
+	}
+	return ctx, nil
+}
+
+// DeviceCode starts the login of a device. The device shows the user code
+// and verification URI to the user and polls the token endpoint with the
+// device code until the user approves or denies the login.
+func (a *API) DeviceCode(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	deviceAuthorization, deviceCode, err := models.NewDeviceAuthorization()
+	if err != nil {
+		return internalServerError("Error generating user code").WithInternalError(err)
+	}
+
+	if err := db.Create(deviceAuthorization); err != nil {
+		return internalServerError("Database error creating device authorization").WithInternalError(err)
+	}
+
+	verificationURL, err := url.Parse(config.DeviceAuthorization.VerificationURL)
+	if err != nil {
+		return internalServerError("Invalid device verification URL").WithInternalError(err)
+	}
+
+	userCode := deviceAuthorization.FormattedUserCode()
+
+	q := verificationURL.Query()
+	q.Set("user_code", userCode)
+	verificationURIComplete := *verificationURL
+	verificationURIComplete.RawQuery = q.Encode()
+
+	return sendJSON(w, http.StatusOK, &DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURL.String(),
+		VerificationURIComplete: verificationURIComplete.String(),
+		ExpiresIn:               int(config.DeviceAuthorization.ExpiryDuration.Seconds()),
+		Interval:                int(config.DeviceAuthorization.PollingInterval.Seconds()),
+	})
+}
+
+// DeviceAuthorizationGet returns a pending device authorization so that the
+// site can confirm the user code with the user.
+func (a *API) DeviceAuthorizationGet(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	deviceAuthorization, err := a.loadDeviceAuthorization(db, chi.URLParam(r, "user_code"))
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, &DeviceAuthorizationResponse{
+		UserCode:  deviceAuthorization.FormattedUserCode(),
+		CreatedAt: deviceAuthorization.CreatedAt,
+		ExpiresAt: deviceAuthorization.CreatedAt.Add(a.config.DeviceAuthorization.ExpiryDuration),
+	})
+}
+
+// DeviceAuthorizationApproval records whether the user approves or denies
+// the login of a device.
+func (a *API) DeviceAuthorizationApproval(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	user := getUser(ctx)
+
+	params := &DeviceAuthorizationApprovalParams{}
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Could not read body").WithInternalError(err)
+	}
+
+	if err := json.Unmarshal(body, params); err != nil {
+		return badRequestError("Could not read device authorization params: %v", err)
+	}
+
+	deviceAuthorization, err := a.loadDeviceAuthorization(db, chi.URLParam(r, "user_code"))
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+		action := models.DeviceAuthorizationDeniedAction
+		if params.Approve {
+			action = models.DeviceAuthorizationApprovedAction
+			terr = deviceAuthorization.Approve(tx, user.ID)
+		} else {
+			terr = deviceAuthorization.Deny(tx)
+		}
+		if terr != nil {
+			if models.IsNotFoundError(terr) {
+				// a concurrent request approved or denied it first
+				return conflictError("Device authorization has already been approved or denied")
+			}
+			return internalServerError("Database error updating device authorization").WithInternalError(terr)
+		}
+
+		if terr := models.NewAuditLogEntry(r, tx, user, action, "", map[string]interface{}{
+			"device_authorization_id": deviceAuthorization.ID,
+		}); terr != nil {
+			return internalServerError("Database error updating device authorization").WithInternalError(terr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, deviceAuthorization)
+}
+
+// DeviceCodeGrant is polled by the device with its device code until the
+// user has approved or denied the login, as described in RFC 8628 Section
+// 3.4 and 3.5.
+func (a *API) DeviceCodeGrant(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	if !config.DeviceAuthorization.Enabled {
+		return oauthError("unsupported_grant_type", "")
+	}
+
+	params := &DeviceCodeGrantParams{}
+	if err := readOAuthRequestParams(r, params); err != nil {
+		return err
+	}
+
+	if params.DeviceCode == "" {
+		return oauthError("invalid_request", "device_code is required")
+	}
+
+	deviceAuthorization, err := models.FindDeviceAuthorizationByDeviceCode(db, params.DeviceCode)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid device code")
+		}
+		return internalServerError("Database error finding device authorization").WithInternalError(err)
+	}
+
+	if deviceAuthorization.IsExpired(config.DeviceAuthorization.ExpiryDuration) {
+		return oauthError("expired_token", "Device code has expired")
+	}
+
+	switch deviceAuthorization.Status {
+	case models.DeviceAuthorizationDenied:
+		if err := db.Destroy(deviceAuthorization); err != nil {
+			return internalServerError("Database error deleting device authorization").WithInternalError(err)
+		}
+		return oauthError("access_denied", "The user denied the device authorization")
+
+	case models.DeviceAuthorizationPending:
+		tooOften := deviceAuthorization.IsPolledTooOften(config.DeviceAuthorization.PollingInterval)
+		if err := deviceAuthorization.UpdateLastPolledAt(db); err != nil {
+			return internalServerError("Database error updating device authorization").WithInternalError(err)
+		}
+		if tooOften {
+			return oauthError("slow_down", "Polling too often")
+		}
+		return oauthError("authorization_pending", "The user has not yet approved the device authorization")
+	}
+
+	user, err := models.FindUserByID(db, *deviceAuthorization.UserID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid device code")
+		}
+		return internalServerError("Database error finding user").WithInternalError(err)
+	}
+
+	if user.IsBanned() {
+		return oauthError("invalid_grant", "User is banned")
+	}
+
+	var token *AccessTokenResponse
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+
+		// the device code can only be exchanged once
+		if terr = deviceAuthorization.Consume(tx); terr != nil {
+			if models.IsNotFoundError(terr) {
+				return oauthError("invalid_grant", "Invalid device code")
+			}
+			return terr
+		}
+
+		if terr = models.NewAuditLogEntry(r, tx, user, models.LoginAction, "", map[string]interface{}{
+			"provider": "device_code",
+		}); terr != nil {
+			return terr
+		}
+
+		// the session belongs to the device polling for tokens, not to
+		// the browser where the user approved the authorization
+		var grantParams models.GrantParams
+		grantParams.FillGrantParams(r)
+
+		token, terr = a.issueRefreshToken(ctx, tx, user, models.DeviceCode, grantParams)
+		return terr
+	})
+	if err != nil {
+		return err
+	}
+
+	metering.RecordLogin("device_code", user.ID)
+	return sendJSON(w, http.StatusOK, token)
+}
+
+// loadDeviceAuthorization loads a pending device authorization by its user
+// code.
+func (a *API) loadDeviceAuthorization(db *storage.Connection, userCode string) (*models.DeviceAuthorization, error) {
+	deviceAuthorization, err := models.FindDeviceAuthorizationByUserCode(db, userCode)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, notFoundError("Device authorization not found")
+		}
+		return nil, internalServerError("Database error finding device authorization").WithInternalError(err)
+	}
+
+	if deviceAuthorization.Status != models.DeviceAuthorizationPending {
+		return nil, notFoundError("Device authorization not found")
+	}
+
+	if deviceAuthorization.IsExpired(a.config.DeviceAuthorization.ExpiryDuration) {
+		return nil, badRequestError("Device authorization has expired")
+	}
+
+	return deviceAuthorization, nil
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+type DeviceAuthorizationTestSuite struct {
+	suite.Suite
+	API    *API
+	Config *conf.GlobalConfiguration
+}
+
+func TestDeviceAuthorization(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.DeviceAuthorization.Enabled = true
+
+	ts := &DeviceAuthorizationTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *DeviceAuthorizationTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error creating test user model")
+	now := time.Now()
+	u.EmailConfirmedAt = &now
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+}
+
+func (ts *DeviceAuthorizationTestSuite) userAccessToken() string {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	return data.Token
+}
+
+func (ts *DeviceAuthorizationTestSuite) requestDeviceCode() *DeviceCodeResponse {
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/device/code", nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &DeviceCodeResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *DeviceAuthorizationTestSuite) approve(userCode string, approve bool) *httptest.ResponseRecorder {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"approve": approve,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/device/authorizations/"+userCode, &buffer)
+	req.Header.Set("Authorization", "Bearer "+ts.userAccessToken())
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *DeviceAuthorizationTestSuite) poll(deviceCode string) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("grant_type", DeviceCodeGrantType)
+	form.Set("device_code", deviceCode)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *DeviceAuthorizationTestSuite) requireOAuthError(w *httptest.ResponseRecorder, expectedError string) {
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	data := map[string]interface{}{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), expectedError, data["error"])
+}
+
+func (ts *DeviceAuthorizationTestSuite) TestDeviceAuthorizationFlow() {
+	code := ts.requestDeviceCode()
+	require.NotEmpty(ts.T(), code.DeviceCode)
+	require.Len(ts.T(), code.UserCode, 9)
+	require.Equal(ts.T(), ts.Config.DeviceAuthorization.VerificationURL, code.VerificationURI)
+	require.Contains(ts.T(), code.VerificationURIComplete, "user_code="+code.UserCode)
+	require.Equal(ts.T(), int(ts.Config.DeviceAuthorization.PollingInterval.Seconds()), code.Interval)
+
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "authorization_pending")
+
+	// user codes are accepted without formatting and in lowercase
+	userCode := strings.ToLower(strings.ReplaceAll(code.UserCode, "-", ""))
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/device/authorizations/"+userCode, nil)
+	req.Header.Set("Authorization", "Bearer "+ts.userAccessToken())
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	w = ts.approve(userCode, true)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	w = ts.poll(code.DeviceCode)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.NotEmpty(ts.T(), data.Token)
+	require.NotEmpty(ts.T(), data.RefreshToken)
+	require.Equal(ts.T(), "test@example.com", data.User.GetEmail())
+
+	// the device code can only be exchanged once
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "invalid_grant")
+}
+
+func (ts *DeviceAuthorizationTestSuite) TestDeviceAuthorizationDenied() {
+	code := ts.requestDeviceCode()
+
+	w := ts.approve(code.UserCode, false)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "access_denied")
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "invalid_grant")
+}
+
+func (ts *DeviceAuthorizationTestSuite) TestDeviceAuthorizationResolvedOnce() {
+	code := ts.requestDeviceCode()
+
+	// a copy loaded before the user approves it, as a concurrent request
+	// would have
+	stale, err := models.FindDeviceAuthorizationByUserCode(ts.API.db, code.UserCode)
+	require.NoError(ts.T(), err)
+
+	w := ts.approve(code.UserCode, true)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	w = ts.approve(code.UserCode, false)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+
+	err = stale.Deny(ts.API.db)
+	require.True(ts.T(), models.IsNotFoundError(err))
+
+	deviceAuthorization, err := models.FindDeviceAuthorizationByUserCode(ts.API.db, code.UserCode)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), models.DeviceAuthorizationApproved, deviceAuthorization.Status)
+}
+
+func (ts *DeviceAuthorizationTestSuite) TestDeviceAuthorizationSlowDown() {
+	code := ts.requestDeviceCode()
+
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "authorization_pending")
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "slow_down")
+}
+
+func (ts *DeviceAuthorizationTestSuite) TestDeviceAuthorizationExpired() {
+	code := ts.requestDeviceCode()
+
+	deviceAuthorization, err := models.FindDeviceAuthorizationByDeviceCode(ts.API.db, code.DeviceCode)
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), ts.API.db.RawQuery(
+		"UPDATE "+deviceAuthorization.TableName()+" SET created_at = ? WHERE id = ?",
+		time.Now().Add(-2*ts.Config.DeviceAuthorization.ExpiryDuration), deviceAuthorization.ID,
+	).Exec())
+
+	ts.requireOAuthError(ts.poll(code.DeviceCode), "expired_token")
+
+	w := ts.approve(code.UserCode, true)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *DeviceAuthorizationTestSuite) TestDeviceAuthorizationDisabled() {
+	ts.Config.DeviceAuthorization.Enabled = false
+	defer func() {
+		ts.Config.DeviceAuthorization.Enabled = true
+	}()
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/device/code", nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+
+	ts.requireOAuthError(ts.poll("device-code"), "unsupported_grant_type")
+}
@@ -265,6 +265,18 @@ func handleError(err error, w http.ResponseWriter, r *http.Request) {
 		if jsonErr := sendJSON(w, e.Code, e); jsonErr != nil {
 			handleError(jsonErr, w, r)
 		}
+	case *WeakPasswordError:
+		log.Info(e.Error())
+		output := struct {
+			*HTTPError
+			WeakPassword *WeakPasswordError `json:"weak_password"`
+		}{
+			HTTPError:    unprocessableEntityError("%s", e.Message),
+			WeakPassword: e,
+		}
+		if jsonErr := sendJSON(w, http.StatusUnprocessableEntity, output); jsonErr != nil {
+			handleError(jsonErr, w, r)
+		}
 	case *OAuthError:
 		log.WithError(e.Cause()).Info(e.Error())
 		if jsonErr := sendJSON(w, http.StatusBadRequest, e); jsonErr != nil {
@@ -208,6 +208,7 @@ func (a *API) internalExternalProviderCallback(w http.ResponseWriter, r *http.Re
 			flowState.UserID = &(user.ID)
 			terr = tx.Update(flowState)
 		} else {
+			grantParams.FillGrantParams(r)
 			token, terr = a.issueRefreshToken(ctx, tx, user, models.OAuth, grantParams)
 		}
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 )
 
@@ -48,7 +49,7 @@ func (ts *ExternalTestSuite) createUser(providerId string, email string, name st
 	if avatar != "" {
 		userData["avatar_url"] = avatar
 	}
-	u, err := models.NewUser("", email, "test", ts.Config.JWT.Aud, userData)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", email, "test", ts.Config.JWT.Aud, userData)
 
 	if confirmationToken != "" {
 		u.ConfirmationToken = confirmationToken
@@ -10,9 +10,11 @@ import (
 	"testing"
 	"time"
 
+	jwt "github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/storage/test"
 )
@@ -29,7 +31,7 @@ func TestSignupHookSendInstanceID(t *testing.T) {
 	conn, err := test.SetupDBConnection(globalConfig)
 	require.NoError(t, err)
 
-	user, err := models.NewUser("81234567", "test@truth.com", "thisisapassword", "", nil)
+	user, err := models.NewUser(crypto.DefaultPasswordHashParams, "81234567", "test@truth.com", "thisisapassword", "", nil)
 	require.NoError(t, err)
 
 	var callCount int
@@ -66,7 +68,7 @@ func TestSignupHookFromClaims(t *testing.T) {
 	conn, err := test.SetupDBConnection(globalConfig)
 	require.NoError(t, err)
 
-	user, err := models.NewUser("", "test@truth.com", "thisisapassword", "", nil)
+	user, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@truth.com", "thisisapassword", "", nil)
 	require.NoError(t, err)
 
 	var callCount int
@@ -164,4 +166,128 @@ func TestHookTimeout(t *testing.T) {
 	assert.Equal(t, 3, callCount)
 }
 
+func TestCustomAccessTokenHook(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+	defer api.db.Close()
+
+	require.NoError(t, models.TruncateAll(api.db))
+
+	user, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", config.JWT.Aud, nil)
+	require.NoError(t, err)
+	require.NoError(t, api.db.Create(user))
+
+	var callCount int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		defer squash(r.Body.Close)
+
+		payload := &CustomAccessTokenHookPayload{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(payload))
+		assert.Equal(t, CustomAccessTokenEvent, string(payload.Event))
+		assert.Equal(t, user.ID, payload.User.ID)
+		assert.Equal(t, models.AAL1.String(), payload.AAL)
+		assert.Equal(t, user.ID.String(), payload.Claims["sub"])
+
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"claims": map[string]interface{}{
+				"tenant_id":   "tenant-1",
+				"permissions": []string{"read", "write"},
+				"sub":         user.ID.String(),
+			},
+		}))
+	}))
+	defer svr.Close()
+
+	config.Hook.CustomAccessToken.URL = svr.URL
+	defer func() {
+		config.Hook.CustomAccessToken.URL = ""
+	}()
+
+	token, _, err := api.generateAccessToken(api.db, user, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, callCount)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.JWT.Secret), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-1", claims["tenant_id"])
+	assert.Equal(t, []interface{}{"read", "write"}, claims["permissions"])
+	assert.Equal(t, user.ID.String(), claims["sub"])
+	assert.Equal(t, user.Role, claims["role"])
+}
+
+func TestValidateCustomAccessTokenClaims(t *testing.T) {
+	current := map[string]interface{}{
+		"sub":  "c8a6b6a4-6b5d-4f5e-9d1f-4f5a2f3d8f1e",
+		"exp":  float64(1700000000),
+		"role": "authenticated",
+	}
+
+	cases := []struct {
+		desc   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{
+			desc:   "New claims",
+			claims: map[string]interface{}{"tenant_id": "tenant-1"},
+			valid:  true,
+		},
+		{
+			desc:   "Unchanged protected claim",
+			claims: map[string]interface{}{"exp": float64(1700000000)},
+			valid:  true,
+		},
+		{
+			desc:   "Overridden role",
+			claims: map[string]interface{}{"role": "tenant_admin"},
+			valid:  true,
+		},
+		{
+			desc:   "Changed subject",
+			claims: map[string]interface{}{"sub": "someone-else"},
+			valid:  false,
+		},
+		{
+			desc:   "Changed expiry",
+			claims: map[string]interface{}{"exp": float64(1800000000)},
+			valid:  false,
+		},
+		{
+			desc:   "Empty role",
+			claims: map[string]interface{}{"role": ""},
+			valid:  false,
+		},
+		{
+			desc:   "Admin role",
+			claims: map[string]interface{}{"role": "service_role"},
+			valid:  false,
+		},
+		{
+			desc:   "Audience list",
+			claims: map[string]interface{}{"aud": []interface{}{"a", "b"}},
+			valid:  false,
+		},
+		{
+			desc:   "Non-object app_metadata",
+			claims: map[string]interface{}{"app_metadata": "admin"},
+			valid:  false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			err := validateCustomAccessTokenClaims(current, c.claims, []string{"service_role", "supabase_admin"})
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
 func squash(f func() error) { _ = f }
@@ -6,11 +6,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"reflect"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -34,6 +36,10 @@ const (
 	SignupEvent         = "signup"
 	EmailChangeEvent    = "email_change"
 	LoginEvent          = "login"
+
+	RefreshTokenReuseEvent = "refresh_token_reuse"
+
+	CustomAccessTokenEvent = "custom_access_token"
 )
 
 var defaultTimeout = time.Second * 5
@@ -260,6 +266,148 @@ func triggerHook(ctx context.Context, hookURL *url.URL, secret string, conn *sto
 	return err
 }
 
+// CustomAccessTokenHookPayload is sent to the custom access token hook before
+// an access token is signed.
+type CustomAccessTokenHookPayload struct {
+	Event     HookEvent              `json:"event"`
+	User      *models.User           `json:"user"`
+	SessionID string                 `json:"session_id,omitempty"`
+	Session   *models.Session        `json:"session,omitempty"`
+	AAL       string                 `json:"aal"`
+	AMR       []models.AMREntry      `json:"amr"`
+	Claims    map[string]interface{} `json:"claims"`
+}
+
+// CustomAccessTokenHookResponse holds the claims the custom access token hook
+// adds to the access token or overrides in it.
+type CustomAccessTokenHookResponse struct {
+	Claims map[string]interface{} `json:"claims"`
+}
+
+// protectedAccessTokenClaims can't be changed by the custom access token
+// hook, as they identify the user and session the token was issued for.
+var protectedAccessTokenClaims = []string{"iss", "sub", "iat", "exp", "nbf", "session_id", "aal", "amr", "auth_time", "client_id", "scope", "mfa_policy"}
+
+// triggerCustomAccessTokenHook calls the custom access token hook and returns
+// the claims to sign, with the claims returned by the hook merged in. The
+// hook can't grant any of the adminRoles.
+func triggerCustomAccessTokenHook(config *conf.CustomAccessTokenHookConfiguration, adminRoles []string, user *models.User, session *models.Session, claims *GoTrueClaims) (jwt.MapClaims, error) {
+	current := map[string]interface{}{}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, internalServerError("Failed to serialize access token claims").WithInternalError(err)
+	}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil, internalServerError("Failed to serialize access token claims").WithInternalError(err)
+	}
+
+	payload := CustomAccessTokenHookPayload{
+		Event:   CustomAccessTokenEvent,
+		User:    user,
+		Session: session,
+		AAL:     claims.AuthenticatorAssuranceLevel,
+		AMR:     claims.AuthenticationMethodReference,
+		Claims:  current,
+	}
+	if session != nil {
+		payload.SessionID = session.ID.String()
+	}
+
+	data, err = json.Marshal(&payload)
+	if err != nil {
+		return nil, internalServerError("Failed to serialize the data for custom access token hook").WithInternalError(err)
+	}
+
+	sha, err := checksum(data)
+	if err != nil {
+		return nil, internalServerError("Failed to checksum the data for custom access token hook").WithInternalError(err)
+	}
+
+	w := Webhook{
+		WebhookConfig: &conf.WebhookConfig{
+			URL:        config.URL,
+			Retries:    config.Retries,
+			TimeoutSec: config.TimeoutSec,
+		},
+		jwtSecret: config.Secret,
+		claims: webhookClaims{
+			StandardClaims: jwt.StandardClaims{
+				IssuedAt: time.Now().Unix(),
+				Subject:  user.ID.String(),
+				Issuer:   gotrueIssuer,
+			},
+			SHA256: sha,
+		},
+		payload: data,
+	}
+
+	body, err := w.trigger()
+	if body != nil {
+		defer utilities.SafeClose(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty response leaves the claims unchanged
+	if body == nil {
+		return jwt.MapClaims(current), nil
+	}
+
+	hookRsp := &CustomAccessTokenHookResponse{}
+	if err := json.NewDecoder(body).Decode(hookRsp); err != nil {
+		return nil, internalServerError("Custom access token hook returned malformed JSON: %v", err).WithInternalError(err)
+	}
+
+	if err := validateCustomAccessTokenClaims(current, hookRsp.Claims, adminRoles); err != nil {
+		return nil, internalServerError("Custom access token hook returned invalid claims: %v", err)
+	}
+
+	for name, value := range hookRsp.Claims {
+		current[name] = value
+	}
+
+	return jwt.MapClaims(current), nil
+}
+
+// validateCustomAccessTokenClaims checks that the claims returned by the
+// custom access token hook leave the protected claims unchanged and that
+// the claims GoTrue reads back from access tokens keep their types. The role
+// may be changed, but not to one of the adminRoles, which would give the
+// user access to the admin API.
+func validateCustomAccessTokenClaims(current, custom map[string]interface{}, adminRoles []string) error {
+	for _, name := range protectedAccessTokenClaims {
+		if value, ok := custom[name]; ok && !reflect.DeepEqual(value, current[name]) {
+			return fmt.Errorf("claim %q cannot be changed", name)
+		}
+	}
+
+	for name, value := range custom {
+		switch name {
+		case "role":
+			role, ok := value.(string)
+			if !ok || role == "" {
+				return fmt.Errorf("claim %q must be a non-empty string", name)
+			}
+			if isStringInSlice(role, adminRoles) {
+				return fmt.Errorf("claim %q cannot be an admin role", name)
+			}
+
+		case "aud", "email", "phone":
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("claim %q must be a string", name)
+			}
+
+		case "app_metadata", "user_metadata":
+			if _, ok := value.(map[string]interface{}); !ok && value != nil {
+				return fmt.Errorf("claim %q must be an object", name)
+			}
+		}
+	}
+
+	return nil
+}
+
 func watchForConnection(req *http.Request) (*connectionWatcher, *http.Request) {
 	w := new(connectionWatcher)
 	t := &httptrace.ClientTrace{
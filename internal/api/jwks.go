@@ -0,0 +1,172 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/supabase/gotrue/internal/conf"
+)
+
+// JWK represents a public JSON Web Key as defined in RFC 7517. Only the
+// parameters needed to verify signatures are ever included.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+
+	// RSA public key parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC and OKP public key parameters
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JWKSResponse is the JSON Web Key Set served at /.well-known/jwks.json.
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the public keys that can be used to verify access tokens
+// issued by this server, including retired keys that are still within their
+// grace period. HS256 secrets are never published, so the key set is empty
+// unless asymmetric keys are configured.
+func (a *API) JWKS(w http.ResponseWriter, r *http.Request) error {
+	config := a.config
+
+	keys := []JWK{}
+
+	if config.JWT.IsAsymmetric() {
+		key, err := publicJWK(config.JWT.KeyID, config.JWT.Algorithm, config.JWT.SigningKey.Public())
+		if err != nil {
+			return internalServerError("Unable to encode JWT signing key").WithInternalError(err)
+		}
+
+		keys = append(keys, *key)
+	}
+
+	now := time.Now()
+
+	for _, retired := range config.JWT.RetiredKeys {
+		if retired.Algorithm == conf.JWTAlgorithmHS256 || retired.IsExpired(now) {
+			continue
+		}
+
+		key, err := publicJWK(retired.KeyID, retired.Algorithm, retired.PublicKey)
+		if err != nil {
+			return internalServerError("Unable to encode retired JWT key").WithInternalError(err)
+		}
+
+		keys = append(keys, *key)
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=600")
+
+	return sendJSON(w, http.StatusOK, &JWKSResponse{
+		Keys: keys,
+	})
+}
+
+// publicJWK encodes a public key as a JWK.
+func publicJWK(kid, algorithm string, publicKey crypto.PublicKey) (*JWK, error) {
+	jwk := &JWK{
+		KeyID:     kid,
+		Use:       "sig",
+		Algorithm: algorithm,
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return jwk, nil
+}
+
+// signingMethodAndKey returns the method and key used to sign access tokens.
+func signingMethodAndKey(config *conf.JWTConfiguration) (jwt.SigningMethod, interface{}) {
+	if config.IsAsymmetric() {
+		return jwt.GetSigningMethod(config.Algorithm), config.SigningKey
+	}
+
+	return jwt.SigningMethodHS256, []byte(config.Secret)
+}
+
+// validSigningMethods lists the algorithms accepted on incoming access
+// tokens. HS256 is always accepted as administrative tokens such as the
+// service_role key are signed with the shared secret.
+func validSigningMethods(config *conf.JWTConfiguration) []string {
+	methods := []string{jwt.SigningMethodHS256.Name}
+
+	if config.IsAsymmetric() {
+		methods = append(methods, config.Algorithm)
+	}
+
+	now := time.Now()
+
+	for _, retired := range config.RetiredKeys {
+		if !retired.IsExpired(now) {
+			methods = append(methods, retired.Algorithm)
+		}
+	}
+
+	return methods
+}
+
+// verificationKey returns a jwt.Keyfunc that resolves the key used to verify
+// an access token. Tokens carrying the kid of a retired key still within its
+// grace period are verified with that key, all others with the current key.
+func verificationKey(config *conf.JWTConfiguration) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		alg := token.Method.Alg()
+
+		if kid, ok := token.Header["kid"].(string); ok && kid != "" && kid != config.KeyID {
+			if retired := config.RetiredKey(kid, time.Now()); retired != nil {
+				if alg != retired.Algorithm {
+					return nil, fmt.Errorf("unexpected signing method %q for key %q", alg, kid)
+				}
+
+				return retired.VerificationKey(), nil
+			}
+		}
+
+		if alg == jwt.SigningMethodHS256.Name {
+			return []byte(config.Secret), nil
+		}
+
+		if config.IsAsymmetric() && alg == config.Algorithm {
+			return config.SigningKey.Public(), nil
+		}
+
+		return nil, fmt.Errorf("unexpected signing method %q", alg)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 )
 
@@ -36,13 +37,13 @@ func TestLogout(t *testing.T) {
 func (ts *LogoutTestSuite) SetupTest() {
 	models.TruncateAll(ts.API.db)
 
-	u, err := models.NewUser("", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
 
 	// generate access token to use for logout
 	var t string
-	t, _, err = generateAccessToken(ts.API.db, u, nil, &ts.Config.JWT)
+	t, _, err = ts.API.generateAccessToken(ts.API.db, u, nil)
 	require.NoError(ts.T(), err)
 	ts.token = t
 }
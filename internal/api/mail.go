@@ -153,6 +153,9 @@ func (a *API) GenerateLink(w http.ResponseWriter, r *http.Request) error {
 				if len(params.Password) < config.PasswordMinLength {
 					return invalidPasswordLengthError(config.PasswordMinLength)
 				}
+				if err := validatePasswordPolicy(&config.PasswordPolicy, params.Password, passwordUserIdentifiers(params.Email, "", params.Data)); err != nil {
+					return err
+				}
 				signupParams := &SignupParams{
 					Email:    params.Email,
 					Password: params.Password,
@@ -2,8 +2,11 @@ package api
 
 import (
 	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"net/url"
 
@@ -11,19 +14,30 @@ import (
 	svg "github.com/ajstarks/svgo"
 	"github.com/boombuler/barcode/qr"
 	"github.com/gofrs/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
 	"github.com/pquerna/otp/totp"
+	"github.com/supabase/gotrue/internal/api/sms_provider"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/metering"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/storage"
 	"github.com/supabase/gotrue/internal/utilities"
+	"github.com/supabase/gotrue/internal/webauthn"
 )
 
 const DefaultQRSize = 3
 
+// totpPeriod is the number of seconds a TOTP code is valid for.
+const totpPeriod = 30
+
 type EnrollFactorParams struct {
 	FriendlyName string `json:"friendly_name"`
 	FactorType   string `json:"factor_type"`
 	Issuer       string `json:"issuer"`
+
+	// Phone is the number to send the codes of a phone factor to.
+	Phone string `json:"phone"`
 }
 
 type TOTPObject struct {
@@ -33,19 +47,38 @@ type TOTPObject struct {
 }
 
 type EnrollFactorResponse struct {
-	ID   uuid.UUID  `json:"id"`
-	Type string     `json:"type"`
-	TOTP TOTPObject `json:"totp,omitempty"`
+	ID    uuid.UUID   `json:"id"`
+	Type  string      `json:"type"`
+	TOTP  *TOTPObject `json:"totp,omitempty"`
+	Phone string      `json:"phone,omitempty"`
 }
 
 type VerifyFactorParams struct {
 	ChallengeID uuid.UUID `json:"challenge_id"`
 	Code        string    `json:"code"`
+
+	// WebAuthn is the credential returned by the browser for a webauthn
+	// factor, a CredentialCreationResponse for an unverified factor and
+	// a CredentialAssertionResponse otherwise.
+	WebAuthn json.RawMessage `json:"web_authn,omitempty"`
+
+	// TrustDevice trusts the device the factor is verified on, so that
+	// signing in on it later skips the factor challenge.
+	TrustDevice bool `json:"trust_device"`
+}
+
+// WebAuthnChallengeObject holds the options to pass to
+// navigator.credentials.create() to register the credential of an
+// unverified webauthn factor, or to navigator.credentials.get() to use it.
+type WebAuthnChallengeObject struct {
+	CredentialCreationOptions *webauthn.PublicKeyCredentialCreationOptions `json:"credential_creation_options,omitempty"`
+	CredentialRequestOptions  *webauthn.PublicKeyCredentialRequestOptions  `json:"credential_request_options,omitempty"`
 }
 
 type ChallengeFactorResponse struct {
-	ID        uuid.UUID `json:"id"`
-	ExpiresAt int64     `json:"expires_at"`
+	ID        uuid.UUID                `json:"id"`
+	ExpiresAt int64                    `json:"expires_at"`
+	WebAuthn  *WebAuthnChallengeObject `json:"web_authn,omitempty"`
 }
 
 type UnenrollFactorResponse struct {
@@ -57,6 +90,16 @@ const (
 	QRCodeGenerationErrorMessage   = "Error generating QR Code"
 )
 
+// factorSecretKeys returns the keys factor secrets are encrypted with, or
+// nil when they are stored unencrypted.
+func (a *API) factorSecretKeys() (*crypto.EnvelopeKeys, error) {
+	keys, err := a.config.MFA.GetSecretEncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.NewEnvelopeKeys(a.config.MFA.SecretEncryptionKeyID, keys), nil
+}
+
 func (a *API) EnrollFactor(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 	user := getUser(ctx)
@@ -73,22 +116,21 @@ func (a *API) EnrollFactor(w http.ResponseWriter, r *http.Request) error {
 		return badRequestError("invalid body: unable to parse JSON").WithInternalError(err)
 	}
 
-	if user.IsSSOUser {
-		return unprocessableEntityError("MFA enrollment only supported for non-SSO users at this time")
-	}
-
-	if params.FactorType != models.TOTP {
-		return badRequestError("factor_type needs to be totp")
-	}
-
-	if params.Issuer == "" {
-		u, err := url.ParseRequestURI(config.SiteURL)
-		if err != nil {
-			return internalServerError("site url is improperly formatted")
+	switch params.FactorType {
+	case models.TOTP:
+	case models.WebAuthn:
+		if !config.MFA.WebAuthn.Enabled {
+			return badRequestError("WebAuthn factors are disabled")
+		}
+	case models.Phone:
+		if !config.MFA.Phone.Enabled {
+			return badRequestError("Phone factors are disabled")
+		}
+		if params.Phone, err = validatePhone(params.Phone); err != nil {
+			return err
 		}
-		issuer = u.Host
-	} else {
-		issuer = params.Issuer
+	default:
+		return badRequestError("factor_type needs to be totp, webauthn or phone")
 	}
 
 	// Read from DB for certainty
@@ -112,27 +154,63 @@ func (a *API) EnrollFactor(w http.ResponseWriter, r *http.Request) error {
 		return forbiddenError("Maximum number of enrolled factors reached, unenroll to continue")
 	}
 
-	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer:      issuer,
-		AccountName: user.GetEmail(),
-	})
-	if err != nil {
-		return internalServerError(QRCodeGenerationErrorMessage).WithInternalError(err)
+	response := &EnrollFactorResponse{
+		Type: params.FactorType,
 	}
-	var buf bytes.Buffer
-	svgData := svg.New(&buf)
-	qrCode, _ := qr.Encode(key.String(), qr.M, qr.Auto)
-	qs := goqrsvg.NewQrSVG(qrCode, DefaultQRSize)
-	qs.StartQrSVG(svgData)
-	if err = qs.WriteQrSVG(svgData); err != nil {
-		return internalServerError(QRCodeGenerationErrorMessage).WithInternalError(err)
+	secret := ""
+
+	// the credential of a webauthn factor is registered when the factor
+	// is verified
+	if params.FactorType == models.TOTP {
+		if params.Issuer == "" {
+			u, err := url.ParseRequestURI(config.SiteURL)
+			if err != nil {
+				return internalServerError("site url is improperly formatted")
+			}
+			issuer = u.Host
+		} else {
+			issuer = params.Issuer
+		}
+
+		key, err := totp.Generate(totp.GenerateOpts{
+			Issuer:      issuer,
+			AccountName: user.GetEmail(),
+		})
+		if err != nil {
+			return internalServerError(QRCodeGenerationErrorMessage).WithInternalError(err)
+		}
+		var buf bytes.Buffer
+		svgData := svg.New(&buf)
+		qrCode, _ := qr.Encode(key.String(), qr.M, qr.Auto)
+		qs := goqrsvg.NewQrSVG(qrCode, DefaultQRSize)
+		qs.StartQrSVG(svgData)
+		if err = qs.WriteQrSVG(svgData); err != nil {
+			return internalServerError(QRCodeGenerationErrorMessage).WithInternalError(err)
+		}
+		svgData.End()
+
+		secret = key.Secret()
+		response.TOTP = &TOTPObject{
+			// See: https://css-tricks.com/probably-dont-base64-svg/
+			QRCode: buf.String(),
+			Secret: secret,
+			URI:    key.URL(),
+		}
+	}
+
+	secretKeys, err := a.factorSecretKeys()
+	if err != nil {
+		return internalServerError("Error loading factor secret encryption keys").WithInternalError(err)
 	}
-	svgData.End()
 
-	factor, err := models.NewFactor(user, params.FriendlyName, params.FactorType, models.FactorStateUnverified, key.Secret())
+	factor, err := models.NewFactor(secretKeys, user, params.FriendlyName, params.FactorType, models.FactorStateUnverified, secret)
 	if err != nil {
 		return internalServerError("database error creating factor").WithInternalError(err)
 	}
+	if params.FactorType == models.Phone {
+		factor.Phone = storage.NullString(params.Phone)
+		response.Phone = params.Phone
+	}
 	err = a.db.Transaction(func(tx *storage.Connection) error {
 		if terr := tx.Create(factor); terr != nil {
 			return terr
@@ -148,16 +226,8 @@ func (a *API) EnrollFactor(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	return sendJSON(w, http.StatusOK, &EnrollFactorResponse{
-		ID:   factor.ID,
-		Type: models.TOTP,
-		TOTP: TOTPObject{
-			// See: https://css-tricks.com/probably-dont-base64-svg/
-			QRCode: buf.String(),
-			Secret: factor.Secret,
-			URI:    key.URL(),
-		},
-	})
+	response.ID = factor.ID
+	return sendJSON(w, http.StatusOK, response)
 }
 
 func (a *API) ChallengeFactor(w http.ResponseWriter, r *http.Request) error {
@@ -166,12 +236,31 @@ func (a *API) ChallengeFactor(w http.ResponseWriter, r *http.Request) error {
 
 	user := getUser(ctx)
 	factor := getFactor(ctx)
+
+	if factor.IsLocked(time.Now()) {
+		return tooManyRequestsError("Too many failed verification attempts, try again later")
+	}
+
 	ipAddress := utilities.GetIPAddress(r)
 	challenge, err := models.NewChallenge(factor, ipAddress)
 	if err != nil {
 		return internalServerError("Database error creating challenge").WithInternalError(err)
 	}
 
+	var webAuthnChallenge *WebAuthnChallengeObject
+	var phoneCode string
+	sendPhoneCode := false
+	switch factor.FactorType {
+	case models.WebAuthn:
+		if webAuthnChallenge, err = a.newWebAuthnChallenge(user, factor, challenge); err != nil {
+			return err
+		}
+	case models.Phone:
+		if phoneCode, sendPhoneCode, err = a.newPhoneFactorCode(factor, challenge); err != nil {
+			return err
+		}
+	}
+
 	err = a.db.Transaction(func(tx *storage.Connection) error {
 		if terr := tx.Create(challenge); terr != nil {
 			return terr
@@ -188,9 +277,21 @@ func (a *API) ChallengeFactor(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	// the challenge is created before the code is sent, so that a code is
+	// never sent for a challenge that can't be verified
+	if sendPhoneCode {
+		if err := a.sendPhoneFactorCode(factor, phoneCode); err != nil {
+			if terr := a.db.Destroy(challenge); terr != nil {
+				return internalServerError("Database error deleting challenge").WithInternalError(terr)
+			}
+			return err
+		}
+	}
+
 	return sendJSON(w, http.StatusOK, &ChallengeFactorResponse{
 		ID:        challenge.ID,
 		ExpiresAt: challenge.GetExpiryTime(config.MFA.ChallengeExpiryDuration).Unix(),
+		WebAuthn:  webAuthnChallenge,
 	})
 }
 
@@ -213,10 +314,18 @@ func (a *API) VerifyFactor(w http.ResponseWriter, r *http.Request) error {
 		return badRequestError("invalid body: unable to parse JSON").WithInternalError(err)
 	}
 
+	if params.TrustDevice && !config.MFA.TrustedDevices.Enabled {
+		return badRequestError("Trusted devices are disabled")
+	}
+
 	if !factor.IsOwnedBy(user) {
 		return internalServerError(InvalidFactorOwnerErrorMessage)
 	}
 
+	if factor.IsLocked(time.Now()) {
+		return tooManyRequestsError("Too many failed verification attempts, try again later")
+	}
+
 	challenge, err := models.FindChallengeByChallengeID(a.db, params.ChallengeID)
 	if err != nil {
 		if models.IsNotFoundError(err) {
@@ -243,8 +352,34 @@ func (a *API) VerifyFactor(w http.ResponseWriter, r *http.Request) error {
 		return badRequestError("%v has expired, verify against another challenge or create a new challenge.", challenge.ID)
 	}
 
-	if valid := totp.Validate(params.Code, factor.Secret); !valid {
-		return badRequestError("Invalid TOTP code entered")
+	authenticationMethod := models.TOTPSignIn
+	var credential *webauthn.Credential
+	var totpCounter *int64
+	switch factor.FactorType {
+	case models.WebAuthn:
+		if credential, err = a.verifyWebAuthnFactor(r, user, factor, challenge, params); err != nil {
+			return err
+		}
+		authenticationMethod = models.WebAuthnSignIn
+	case models.Phone:
+		if err := a.verifyPhoneFactor(r, user, factor, challenge, params); err != nil {
+			return err
+		}
+		authenticationMethod = models.PhoneSignIn
+	default:
+		secretKeys, err := a.factorSecretKeys()
+		if err != nil {
+			return internalServerError("Error loading factor secret encryption keys").WithInternalError(err)
+		}
+		secret, err := factor.GetSecret(secretKeys)
+		if err != nil {
+			return internalServerError("Error reading factor secret").WithInternalError(err)
+		}
+		counter, valid := validateTOTPCode(params.Code, secret, time.Now())
+		if !valid {
+			return a.recordFailedVerification(r, user, factor, badRequestError("Invalid TOTP code entered"))
+		}
+		totpCounter = &counter
 	}
 
 	var token *AccessTokenResponse
@@ -256,9 +391,26 @@ func (a *API) VerifyFactor(w http.ResponseWriter, r *http.Request) error {
 		}); terr != nil {
 			return terr
 		}
+		if totpCounter != nil {
+			accepted, terr := factor.UpdateLastTOTPCounter(tx, *totpCounter)
+			if terr != nil {
+				return terr
+			}
+			if !accepted {
+				return badRequestError("TOTP code has already been used")
+			}
+		}
+		if terr = factor.ResetFailedVerifications(tx); terr != nil {
+			return terr
+		}
 		if terr = challenge.Verify(tx); terr != nil {
 			return terr
 		}
+		if credential != nil {
+			if terr = factor.UpdateWebAuthnCredential(tx, credential); terr != nil {
+				return terr
+			}
+		}
 		if !factor.IsVerified() {
 			if terr = factor.UpdateStatus(tx, models.FactorStateVerified); terr != nil {
 				return terr
@@ -268,12 +420,17 @@ func (a *API) VerifyFactor(w http.ResponseWriter, r *http.Request) error {
 		if terr != nil {
 			return terr
 		}
-		token, terr = a.updateMFASessionAndClaims(r, tx, user, models.TOTPSignIn, models.GrantParams{
+		token, terr = a.updateMFASessionAndClaims(r, tx, user, authenticationMethod, models.GrantParams{
 			FactorID: &factor.ID,
 		})
 		if terr != nil {
 			return terr
 		}
+		if params.TrustDevice {
+			if token.TrustedDeviceToken, terr = a.trustDevice(r, tx, user, factor); terr != nil {
+				return terr
+			}
+		}
 		if terr = a.setCookieTokens(config, token, false, w); terr != nil {
 			return internalServerError("Failed to set JWT cookie. %s", terr)
 		}
@@ -294,6 +451,261 @@ func (a *API) VerifyFactor(w http.ResponseWriter, r *http.Request) error {
 
 }
 
+// validateTOTPCode checks a TOTP code like totp.Validate, allowing for one
+// time step of clock skew, and returns the time step the code belongs to.
+func validateTOTPCode(code, secret string, now time.Time) (int64, bool) {
+	counter := now.Unix() / totpPeriod
+	for _, c := range []int64{counter, counter + 1, counter - 1} {
+		valid, err := hotp.ValidateCustom(code, uint64(c), secret, hotp.ValidateOpts{
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if valid {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// recordFailedVerification counts a failed attempt to verify the factor and
+// locks it after too many consecutive failures. It returns err, the error
+// of the failed attempt.
+func (a *API) recordFailedVerification(r *http.Request, user *models.User, factor *models.Factor, err error) error {
+	config := a.config
+
+	terr := a.db.Transaction(func(tx *storage.Connection) error {
+		locked, terr := factor.RecordFailedVerification(tx, config.MFA.MaxVerificationAttempts, config.MFA.VerificationLockoutDuration)
+		if terr != nil {
+			return terr
+		}
+
+		if locked {
+			return models.NewAuditLogEntry(r, tx, user, models.FactorLockedAction, r.RemoteAddr, map[string]interface{}{
+				"factor_id":    factor.ID,
+				"locked_until": factor.LockedUntil,
+			})
+		}
+		return nil
+	})
+	if terr != nil {
+		return internalServerError("Database error updating factor").WithInternalError(terr)
+	}
+
+	return err
+}
+
+// webAuthnRelyingParty returns the relying party webauthn credentials are
+// registered with.
+func (a *API) webAuthnRelyingParty() *webauthn.RelyingParty {
+	config := a.config
+
+	return &webauthn.RelyingParty{
+		ID:      config.MFA.WebAuthn.RPID,
+		Name:    config.MFA.WebAuthn.RPDisplayName,
+		Origins: config.MFA.WebAuthn.RPOrigins,
+		Timeout: time.Second * time.Duration(config.MFA.ChallengeExpiryDuration),
+	}
+}
+
+// newWebAuthnChallenge sets the challenge of a webauthn factor and returns
+// the options to register its credential if the factor is unverified, or to
+// use it otherwise.
+func (a *API) newWebAuthnChallenge(user *models.User, factor *models.Factor, challenge *models.Challenge) (*WebAuthnChallengeObject, error) {
+	if !a.config.MFA.WebAuthn.Enabled {
+		return nil, badRequestError("WebAuthn factors are disabled")
+	}
+
+	relyingParty := a.webAuthnRelyingParty()
+	webAuthnChallenge := crypto.SecureToken(32)
+	challenge.WebAuthnChallenge = &webAuthnChallenge
+
+	if factor.IsVerified() {
+		if factor.WebAuthnCredential == nil {
+			return nil, internalServerError("WebAuthn factor has no credential")
+		}
+
+		return &WebAuthnChallengeObject{
+			CredentialRequestOptions: relyingParty.RequestOptions(webAuthnChallenge, []*webauthn.Credential{&factor.WebAuthnCredential.Credential}, webauthn.UserVerificationPreferred),
+		}, nil
+	}
+
+	// a credential can only be registered once per user
+	credentials, err := models.FindVerifiedWebAuthnCredentials(a.db, user)
+	if err != nil {
+		return nil, internalServerError("Database error finding factors").WithInternalError(err)
+	}
+
+	name := user.GetEmail()
+	if name == "" {
+		name = user.GetPhone()
+	}
+
+	// the user handle is the user ID, so that passkeys identify the user
+	return &WebAuthnChallengeObject{
+		CredentialCreationOptions: relyingParty.CreationOptions(webAuthnChallenge, webauthn.UserEntity{
+			ID:          base64.RawURLEncoding.EncodeToString(user.ID.Bytes()),
+			Name:        name,
+			DisplayName: name,
+		}, credentials, webauthn.ResidentKeyPreferred),
+	}, nil
+}
+
+// verifyWebAuthnFactor verifies the credential sent for a webauthn factor
+// and returns the credential to store: the newly registered credential of
+// an unverified factor, or the credential with its updated signature
+// counter. Invalid credentials count as failed verifications.
+func (a *API) verifyWebAuthnFactor(r *http.Request, user *models.User, factor *models.Factor, challenge *models.Challenge, params *VerifyFactorParams) (*webauthn.Credential, error) {
+	if !a.config.MFA.WebAuthn.Enabled {
+		return nil, badRequestError("WebAuthn factors are disabled")
+	}
+
+	if challenge.FactorID != factor.ID || challenge.WebAuthnChallenge == nil {
+		return nil, badRequestError("Challenge does not belong to the factor")
+	}
+
+	if len(params.WebAuthn) == 0 {
+		return nil, badRequestError("web_authn is required to verify a webauthn factor")
+	}
+
+	relyingParty := a.webAuthnRelyingParty()
+
+	if !factor.IsVerified() {
+		response := &webauthn.CredentialCreationResponse{}
+		if err := json.Unmarshal(params.WebAuthn, response); err != nil {
+			return nil, badRequestError("invalid body: unable to parse web_authn").WithInternalError(err)
+		}
+
+		credential, err := relyingParty.VerifyRegistration(*challenge.WebAuthnChallenge, response, false)
+		if err != nil {
+			return nil, a.recordFailedVerification(r, user, factor, badRequestError("Invalid WebAuthn credential").WithInternalError(err))
+		}
+
+		if _, err := models.FindVerifiedWebAuthnFactorByCredentialID(a.db, user, credential.ID); err == nil {
+			return nil, badRequestError("WebAuthn credential is already registered")
+		} else if !models.IsNotFoundError(err) {
+			return nil, internalServerError("Database error finding factors").WithInternalError(err)
+		}
+
+		return credential, nil
+	}
+
+	if factor.WebAuthnCredential == nil {
+		return nil, internalServerError("WebAuthn factor has no credential")
+	}
+
+	response := &webauthn.CredentialAssertionResponse{}
+	if err := json.Unmarshal(params.WebAuthn, response); err != nil {
+		return nil, badRequestError("invalid body: unable to parse web_authn").WithInternalError(err)
+	}
+
+	credential := factor.WebAuthnCredential.Credential
+	signCount, err := relyingParty.VerifyAssertion(*challenge.WebAuthnChallenge, &credential, response, false)
+	if err != nil {
+		return nil, a.recordFailedVerification(r, user, factor, badRequestError("Invalid WebAuthn credential").WithInternalError(err))
+	}
+	credential.SignCount = signCount
+
+	return &credential, nil
+}
+
+// newPhoneFactorCode checks that a code can be sent to the phone of a phone
+// factor and stores the hash of a new code on the challenge. With the Twilio
+// Verify provider the code is generated and checked by Twilio instead. It
+// returns the code and whether it has to be sent, which test OTPs aren't.
+func (a *API) newPhoneFactorCode(factor *models.Factor, challenge *models.Challenge) (string, bool, error) {
+	config := a.config
+
+	if !config.MFA.Phone.Enabled {
+		return "", false, badRequestError("Phone factors are disabled")
+	}
+
+	latest, err := models.FindLatestChallengeByFactorID(a.db, factor.ID)
+	if err != nil && !models.IsNotFoundError(err) {
+		return "", false, internalServerError("Database error finding challenge").WithInternalError(err)
+	}
+	if latest != nil && !latest.CreatedAt.Add(config.Sms.MaxFrequency).Before(time.Now()) {
+		until := time.Until(latest.CreatedAt.Add(config.Sms.MaxFrequency)) / time.Second
+		return "", false, tooManyRequestsError("For security purposes, you can only request this once every %d seconds.", until)
+	}
+
+	phone := string(factor.Phone)
+	otp, ok := config.Sms.GetTestOTP(phone, time.Now())
+	if !ok {
+		if otp, err = crypto.GenerateOtp(config.Sms.OtpLength); err != nil {
+			return "", false, internalServerError("error generating otp").WithInternalError(err)
+		}
+
+		if config.Sms.IsTwilioVerifyProvider() {
+			return otp, true, nil
+		}
+	}
+
+	otpCode := crypto.GenerateTokenHash(phone, otp)
+	challenge.OtpCode = &otpCode
+	return otp, !ok, nil
+}
+
+// sendPhoneFactorCode sends the code of a challenge to the phone of a phone
+// factor.
+func (a *API) sendPhoneFactorCode(factor *models.Factor, otp string) error {
+	config := a.config
+
+	smsProvider, err := sms_provider.GetSmsProvider(*config)
+	if err != nil {
+		return internalServerError("Unable to get SMS provider").WithInternalError(err)
+	}
+
+	message, err := generateSMSFromTemplate(config.Sms.SMSTemplate, otp)
+	if err != nil {
+		return err
+	}
+
+	if _, err := smsProvider.SendMessage(string(factor.Phone), message, sms_provider.SMSProvider, otp); err != nil {
+		return badRequestError("Error sending sms OTP: %v", err)
+	}
+	return nil
+}
+
+// verifyPhoneFactor checks the code entered for a phone factor against the
+// code sent for the challenge. Wrong codes count as failed verifications.
+func (a *API) verifyPhoneFactor(r *http.Request, user *models.User, factor *models.Factor, challenge *models.Challenge, params *VerifyFactorParams) error {
+	config := a.config
+
+	if !config.MFA.Phone.Enabled {
+		return badRequestError("Phone factors are disabled")
+	}
+
+	if challenge.FactorID != factor.ID {
+		return badRequestError("Challenge does not belong to the factor")
+	}
+
+	phone := string(factor.Phone)
+
+	if challenge.OtpCode != nil {
+		otpCode := crypto.GenerateTokenHash(phone, params.Code)
+		if subtle.ConstantTimeCompare([]byte(otpCode), []byte(*challenge.OtpCode)) != 1 {
+			return a.recordFailedVerification(r, user, factor, badRequestError("Invalid code entered"))
+		}
+		return nil
+	}
+
+	if !config.Sms.IsTwilioVerifyProvider() {
+		return badRequestError("Challenge does not belong to the factor")
+	}
+
+	smsProvider, err := sms_provider.GetSmsProvider(*config)
+	if err != nil {
+		return internalServerError("Unable to get SMS provider").WithInternalError(err)
+	}
+	if err := smsProvider.(*sms_provider.TwilioVerifyProvider).VerifyOTP(phone, params.Code); err != nil {
+		return a.recordFailedVerification(r, user, factor, badRequestError("Invalid code entered").WithInternalError(err))
+	}
+	return nil
+}
+
 func (a *API) UnenrollFactor(w http.ResponseWriter, r *http.Request) error {
 	var err error
 	ctx := r.Context()
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+type PhoneFactorTestSuite struct {
+	suite.Suite
+	API    *API
+	Config *conf.GlobalConfiguration
+}
+
+func TestPhoneFactor(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.MFA.Phone.Enabled = true
+	config.Sms.MaxFrequency = 0
+	config.Sms.TestOTP = map[string]string{
+		"123456789": "123456",
+	}
+
+	ts := &PhoneFactorTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *PhoneFactorTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error creating test user model")
+	now := time.Now()
+	u.EmailConfirmedAt = &now
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+}
+
+func (ts *PhoneFactorTestSuite) signIn() string {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=password", "", map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data.Token
+}
+
+func (ts *PhoneFactorTestSuite) enroll(token string) string {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors", token, map[string]interface{}{
+		"factor_type":   models.Phone,
+		"friendly_name": "mobile",
+		"phone":         "+1 23456789",
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &EnrollFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	require.Equal(ts.T(), models.Phone, data.Type)
+	require.Equal(ts.T(), "123456789", data.Phone)
+	require.Nil(ts.T(), data.TOTP)
+	return data.ID.String()
+}
+
+func (ts *PhoneFactorTestSuite) challenge(token, factorID string) string {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/challenge", factorID), token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &ChallengeFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data.ID.String()
+}
+
+func (ts *PhoneFactorTestSuite) TestPhoneFactor() {
+	token := ts.signIn()
+	factorID := ts.enroll(token)
+
+	challengeID := ts.challenge(token, factorID)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), token, map[string]interface{}{
+		"challenge_id": challengeID,
+		"code":         "000000",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), token, map[string]interface{}{
+		"challenge_id": challengeID,
+		"code":         "123456",
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+
+	ctx, err := ts.API.parseJWTClaims(data.Token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	claims := getClaims(ctx)
+	require.Equal(ts.T(), models.AAL2.String(), claims.AuthenticatorAssuranceLevel)
+	require.Equal(ts.T(), models.PhoneSignIn.String(), claims.AuthenticationMethodReference[0].Method)
+
+	factor, err := models.FindFactorByFactorID(ts.API.db, uuid.Must(uuid.FromString(factorID)))
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), factor.IsVerified())
+	require.Equal(ts.T(), "123456789", string(factor.Phone))
+
+	// the code can only be used once
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), data.Token, map[string]interface{}{
+		"challenge_id": challengeID,
+		"code":         "123456",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *PhoneFactorTestSuite) TestChallengeMaxFrequency() {
+	ts.Config.Sms.MaxFrequency = time.Minute
+	defer func() {
+		ts.Config.Sms.MaxFrequency = 0
+	}()
+
+	token := ts.signIn()
+	factorID := ts.enroll(token)
+	ts.challenge(token, factorID)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/challenge", factorID), token, nil)
+	require.Equal(ts.T(), http.StatusTooManyRequests, w.Code)
+}
+
+func (ts *PhoneFactorTestSuite) TestChallengeSendFailure() {
+	token := ts.signIn()
+	factorID := ts.enroll(token)
+
+	// without a test OTP the code is sent with the SMS provider, which isn't
+	// configured
+	testOTP, smsProvider := ts.Config.Sms.TestOTP, ts.Config.Sms.Provider
+	ts.Config.Sms.TestOTP = nil
+	ts.Config.Sms.Provider = ""
+	defer func() {
+		ts.Config.Sms.TestOTP = testOTP
+		ts.Config.Sms.Provider = smsProvider
+	}()
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/challenge", factorID), token, nil)
+	require.Equal(ts.T(), http.StatusInternalServerError, w.Code)
+
+	// the challenge is deleted, so that it doesn't count towards the max
+	// frequency
+	_, err := models.FindLatestChallengeByFactorID(ts.API.db, uuid.Must(uuid.FromString(factorID)))
+	require.True(ts.T(), models.IsNotFoundError(err))
+}
+
+func (ts *PhoneFactorTestSuite) TestEnrollInvalidPhone() {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors", ts.signIn(), map[string]interface{}{
+		"factor_type": models.Phone,
+		"phone":       "not a phone",
+	})
+	require.Equal(ts.T(), http.StatusUnprocessableEntity, w.Code)
+}
+
+func (ts *PhoneFactorTestSuite) TestPhoneFactorDisabled() {
+	ts.Config.MFA.Phone.Enabled = false
+	defer func() {
+		ts.Config.MFA.Phone.Enabled = true
+	}()
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors", ts.signIn(), map[string]interface{}{
+		"factor_type": models.Phone,
+		"phone":       "123456789",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// MFAPolicyClaims describe the MFA requirement of the user in the access
+// token. They are only present when the user is required to use MFA.
+type MFAPolicyClaims struct {
+	Required bool `json:"required"`
+
+	// EnrollBy is the UNIX time at which the enrollment grace period ends,
+	// after which sessions below aal2 can only use the factor endpoints.
+	EnrollBy int64 `json:"enroll_by"`
+}
+
+// isMFARequired returns whether the user is required to use MFA, either by
+// an admin, by the configured enforcement policy or by the SSO provider of
+// an SSO user.
+func (a *API) isMFARequired(tx *storage.Connection, user *models.User) (bool, error) {
+	if user.MFARequired || a.config.MFA.Enforcement.Applies(user.Role, user.Aud) {
+		return true, nil
+	}
+
+	if !user.IsSSOUser {
+		return false, nil
+	}
+
+	providerID, err := models.FindSSOProviderIDForUser(tx, user.ID)
+	if err != nil || providerID == "" {
+		return false, err
+	}
+
+	provider, err := models.FindSSOProviderByID(tx, uuid.FromStringOrNil(providerID))
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return provider.MFARequired, nil
+}
+
+// mfaPolicyClaims returns the MFA policy claims of the user, or nil if the
+// user isn't required to use MFA. The enrollment grace period starts the
+// first time a token is issued to a user the policy applies to.
+func (a *API) mfaPolicyClaims(tx *storage.Connection, user *models.User) (*MFAPolicyClaims, error) {
+	required, err := a.isMFARequired(tx, user)
+	if err != nil {
+		return nil, internalServerError("Database error finding SSO provider").WithInternalError(err)
+	}
+	if !required {
+		return nil, nil
+	}
+
+	if err := user.StartMFAEnforcement(tx); err != nil {
+		return nil, internalServerError("Database error updating user").WithInternalError(err)
+	}
+
+	return &MFAPolicyClaims{
+		Required: true,
+		EnrollBy: user.MFARequiredSince.Add(a.config.MFA.Enforcement.GracePeriod).Unix(),
+	}, nil
+}
+
+// requireMFAPolicy rejects sessions of users that are required to use MFA
+// once their grace period has ended, until they reach aal2. Such sessions
+// can still enroll and verify factors.
+func (a *API) requireMFAPolicy(w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	claims := getClaims(ctx)
+	if claims == nil || claims.MFAPolicy == nil || !claims.MFAPolicy.Required {
+		return ctx, nil
+	}
+
+	if claims.AuthenticatorAssuranceLevel == models.AAL2.String() || time.Now().Unix() < claims.MFAPolicy.EnrollBy {
+		return ctx, nil
+	}
+
+	return nil, forbiddenError("MFA is required, verify a factor to continue")
+}
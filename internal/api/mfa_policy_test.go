@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+func mfaPolicyOf(ts *MFATestSuite, token string) *MFAPolicyClaims {
+	ctx, err := ts.API.parseJWTClaims(token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	return getClaims(ctx).MFAPolicy
+}
+
+func updateUserMetadata(ts *MFATestSuite, token string) int {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", token, map[string]interface{}{
+		"data": map[string]interface{}{"updated": true},
+	})
+	return w.Code
+}
+
+func (ts *MFATestSuite) TestMFARequiredByAdmin() {
+	email := "test1@example.com"
+	password := "test123"
+	signUpResp := signUp(ts, email, password)
+	require.Nil(ts.T(), mfaPolicyOf(ts, signUpResp.Token))
+
+	user, err := models.FindUserByID(ts.API.db, signUpResp.User.ID)
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), user.SetMFARequired(ts.API.db, true))
+
+	token := passwordSignIn(ts, email, password)
+	policy := mfaPolicyOf(ts, token)
+	require.NotNil(ts.T(), policy)
+	require.True(ts.T(), policy.Required)
+	require.LessOrEqual(ts.T(), policy.EnrollBy, time.Now().Unix())
+
+	// without a grace period the session is restricted to the factor endpoints
+	require.Equal(ts.T(), http.StatusForbidden, updateUserMetadata(ts, token))
+	w := performJSONRequest(ts.T(), ts.API, http.MethodGet, "/user", token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	aal2Token := enrollAndVerify(ts, signUpResp.User, token).Token
+	require.NotNil(ts.T(), mfaPolicyOf(ts, aal2Token))
+	require.Equal(ts.T(), http.StatusOK, updateUserMetadata(ts, aal2Token))
+}
+
+func (ts *MFATestSuite) TestMFARequiredByPolicy() {
+	enforcement := ts.Config.MFA.Enforcement
+	ts.Config.MFA.Enforcement = conf.MFAEnforcementConfiguration{
+		Audiences:   []string{ts.Config.JWT.Aud},
+		GracePeriod: time.Hour,
+	}
+	defer func() {
+		ts.Config.MFA.Enforcement = enforcement
+	}()
+
+	token := signUp(ts, "test1@example.com", "test123").Token
+	policy := mfaPolicyOf(ts, token)
+	require.NotNil(ts.T(), policy)
+	require.True(ts.T(), policy.Required)
+	require.Greater(ts.T(), policy.EnrollBy, time.Now().Add(59*time.Minute).Unix())
+
+	// the session isn't restricted during the grace period
+	require.Equal(ts.T(), http.StatusOK, updateUserMetadata(ts, token))
+}
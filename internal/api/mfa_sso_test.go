@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+func (ts *MFATestSuite) TestSSOUserMFA() {
+	provider := &models.SSOProvider{
+		SAMLProvider: models.SAMLProvider{
+			EntityID:    "https://accounts.google.com/o/saml2?idpid=EXAMPLE-A",
+			MetadataXML: validSAMLIDPMetadata("https://accounts.google.com/o/saml2?idpid=EXAMPLE-A"),
+		},
+		MFARequired: true,
+	}
+	require.NoError(ts.T(), ts.API.db.Eager().Create(provider))
+
+	user, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "sso@example.com", "", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err)
+	user.IsSSOUser = true
+	require.NoError(ts.T(), ts.API.db.Create(user))
+
+	identity, err := models.NewIdentity(user, "sso:"+provider.ID.String(), map[string]interface{}{
+		"sub":   "sso-subject",
+		"email": "sso@example.com",
+	})
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), ts.API.db.Create(identity))
+
+	refreshToken, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, user, models.GrantParams{})
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), models.AddClaimToSession(ts.API.db, *refreshToken.SessionId, models.SSOSAML))
+
+	token, _, err := ts.API.generateAccessToken(ts.API.db, user, refreshToken.SessionId)
+	require.NoError(ts.T(), err)
+
+	// the provider requires its users to use MFA
+	policy := mfaPolicyOf(ts, token)
+	require.NotNil(ts.T(), policy)
+	require.True(ts.T(), policy.Required)
+
+	aal2Token := enrollAndVerify(ts, user, token).Token
+
+	ctx, err := ts.API.parseJWTClaims(aal2Token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	claims := getClaims(ctx)
+	require.Equal(ts.T(), models.AAL2.String(), claims.AuthenticatorAssuranceLevel)
+
+	amr := claims.AuthenticationMethodReference
+	require.Len(ts.T(), amr, 2)
+	require.Equal(ts.T(), models.TOTPSignIn.String(), amr[0].Method)
+	require.Equal(ts.T(), models.SSOSAML.String(), amr[1].Method)
+	require.Equal(ts.T(), provider.ID.String(), amr[1].Provider)
+}
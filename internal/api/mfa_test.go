@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,8 +12,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/pquerna/otp"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/utilities"
 
@@ -46,11 +49,11 @@ func TestMFA(t *testing.T) {
 func (ts *MFATestSuite) SetupTest() {
 	models.TruncateAll(ts.API.db)
 	// Create user
-	u, err := models.NewUser("123456789", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
 	// Create Factor
-	f, err := models.NewFactor(u, "test_factor", models.TOTP, models.FactorStateUnverified, "secretkey")
+	f, err := models.NewFactor(nil, u, "test_factor", models.TOTP, models.FactorStateUnverified, "secretkey")
 	require.NoError(ts.T(), err, "Error creating test factor model")
 	require.NoError(ts.T(), ts.API.db.Create(f), "Error saving new test factor")
 	// Create corresponding sessoin
@@ -124,7 +127,7 @@ func (ts *MFATestSuite) TestEnrollFactor() {
 			user, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
 			ts.Require().NoError(err)
 
-			token, _, err := generateAccessToken(ts.API.db, user, nil, &ts.Config.JWT)
+			token, _, err := ts.API.generateAccessToken(ts.API.db, user, nil)
 			require.NoError(ts.T(), err)
 
 			w := httptest.NewRecorder()
@@ -161,7 +164,7 @@ func (ts *MFATestSuite) TestChallengeFactor() {
 	require.NoError(ts.T(), err)
 	f := factors[0]
 
-	token, _, err := generateAccessToken(ts.API.db, u, nil, &ts.Config.JWT)
+	token, _, err := ts.API.generateAccessToken(ts.API.db, u, nil)
 	require.NoError(ts.T(), err, "Error generating access token")
 
 	var buffer bytes.Buffer
@@ -206,7 +209,7 @@ func (ts *MFATestSuite) TestMFAVerifyFactor() {
 			user, err := models.FindUserByEmailAndAudience(ts.API.db, ts.TestEmail, ts.Config.JWT.Aud)
 			ts.Require().NoError(err)
 			var buffer bytes.Buffer
-			r, err := models.GrantAuthenticatedUser(ts.API.db, user, models.GrantParams{})
+			r, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, user, models.GrantParams{})
 			require.NoError(ts.T(), err)
 			sharedSecret := ts.TestOTPKey.Secret()
 			factors, err := models.FindFactorsByUser(ts.API.db, user)
@@ -222,7 +225,7 @@ func (ts *MFATestSuite) TestMFAVerifyFactor() {
 			secondarySession.FactorID = &f.ID
 			require.NoError(ts.T(), ts.API.db.Create(secondarySession), "Error saving test session")
 
-			token, _, err := generateAccessToken(ts.API.db, user, r.SessionId, &ts.Config.JWT)
+			token, _, err := ts.API.generateAccessToken(ts.API.db, user, r.SessionId)
 
 			require.NoError(ts.T(), err)
 
@@ -270,6 +273,108 @@ func (ts *MFATestSuite) TestMFAVerifyFactor() {
 	}
 }
 
+func enrollTOTPFactor(ts *MFATestSuite, token string) *EnrollFactorResponse {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors", token, map[string]string{
+		"friendly_name": "john",
+		"factor_type":   models.TOTP,
+		"issuer":        ts.TestDomain,
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	enrollResp := &EnrollFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(enrollResp))
+	return enrollResp
+}
+
+func challengeAndVerify(ts *MFATestSuite, token string, factorID uuid.UUID, code string) *httptest.ResponseRecorder {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/challenge", factorID), token, nil)
+	if w.Code != http.StatusOK {
+		return w
+	}
+
+	challengeResp := &ChallengeFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(challengeResp))
+
+	return performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), token, map[string]interface{}{
+		"challenge_id": challengeResp.ID,
+		"code":         code,
+	})
+}
+
+func (ts *MFATestSuite) TestMFAVerifyFactorRejectsReplayedCode() {
+	token := signUp(ts, "test1@example.com", "test123").Token
+	factor := enrollTOTPFactor(ts, token)
+
+	code, err := totp.GenerateCode(factor.TOTP.Secret, time.Now().UTC())
+	require.NoError(ts.T(), err)
+
+	w := challengeAndVerify(ts, token, factor.ID, code)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	w = challengeAndVerify(ts, token, factor.ID, code)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *MFATestSuite) TestMFAVerifyFactorWithEncryptedSecret() {
+	mfa := ts.Config.MFA
+	ts.Config.MFA.SecretEncryptionKeyID = "key-1"
+	ts.Config.MFA.SecretEncryptionKeys = map[string]string{
+		"key-1": base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)),
+	}
+	defer func() {
+		ts.Config.MFA = mfa
+	}()
+
+	token := signUp(ts, "test1@example.com", "test123").Token
+	factor := enrollTOTPFactor(ts, token)
+
+	f, err := models.FindFactorByFactorID(ts.API.db, factor.ID)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), strings.HasPrefix(f.Secret, crypto.EnvelopePrefix("key-1")))
+
+	code, err := totp.GenerateCode(factor.TOTP.Secret, time.Now().UTC())
+	require.NoError(ts.T(), err)
+
+	w := challengeAndVerify(ts, token, factor.ID, code)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+}
+
+func (ts *MFATestSuite) TestMFAVerifyFactorLockout() {
+	maxAttempts := ts.Config.MFA.MaxVerificationAttempts
+	ts.Config.MFA.MaxVerificationAttempts = 2
+	defer func() {
+		ts.Config.MFA.MaxVerificationAttempts = maxAttempts
+	}()
+
+	token := signUp(ts, "test1@example.com", "test123").Token
+	factor := enrollTOTPFactor(ts, token)
+
+	wrongCode, err := totp.GenerateCode(factor.TOTP.Secret, time.Now().UTC().Add(-5*time.Minute))
+	require.NoError(ts.T(), err)
+
+	for i := 0; i < 2; i++ {
+		w := challengeAndVerify(ts, token, factor.ID, wrongCode)
+		require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+	}
+
+	f, err := models.FindFactorByFactorID(ts.API.db, factor.ID)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), f.IsLocked(time.Now()))
+
+	// even a valid code is rejected while the factor is locked
+	code, err := totp.GenerateCode(factor.TOTP.Secret, time.Now().UTC())
+	require.NoError(ts.T(), err)
+
+	w := challengeAndVerify(ts, token, factor.ID, code)
+	require.Equal(ts.T(), http.StatusTooManyRequests, w.Code)
+
+	// the factor can be used again once the lockout has passed
+	require.NoError(ts.T(), ts.API.db.RawQuery("UPDATE auth.mfa_factors SET locked_until = ? WHERE id = ?", time.Now().Add(-time.Minute), factor.ID).Exec())
+
+	w = challengeAndVerify(ts, token, factor.ID, code)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+}
+
 func (ts *MFATestSuite) TestUnenrollVerifiedFactor() {
 	cases := []struct {
 		desc             string
@@ -318,7 +423,7 @@ func (ts *MFATestSuite) TestUnenrollVerifiedFactor() {
 
 			var buffer bytes.Buffer
 
-			token, _, err := generateAccessToken(ts.API.db, u, &s.ID, &ts.Config.JWT)
+			token, _, err := ts.API.generateAccessToken(ts.API.db, u, &s.ID)
 			require.NoError(ts.T(), err)
 
 			w := httptest.NewRecorder()
@@ -360,7 +465,7 @@ func (ts *MFATestSuite) TestUnenrollUnverifiedFactor() {
 
 	var buffer bytes.Buffer
 
-	token, _, err := generateAccessToken(ts.API.db, u, &s.ID, &ts.Config.JWT)
+	token, _, err := ts.API.generateAccessToken(ts.API.db, u, &s.ID)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
 		"factor_id": f.ID,
@@ -0,0 +1,481 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/observability"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// OAuthAuthorizationResponse describes a pending authorization request from
+// a third-party OAuth client, so that the site can ask the user to consent.
+type OAuthAuthorizationResponse struct {
+	AuthorizationID string                   `json:"authorization_id"`
+	Client          OAuthAuthorizationClient `json:"client"`
+	RedirectURI     string                   `json:"redirect_uri"`
+	Scopes          []string                 `json:"scopes"`
+	Consented       bool                     `json:"consented"`
+}
+
+// OAuthAuthorizationClient is the public information about an OAuth client
+// shown to the user on the consent page.
+type OAuthAuthorizationClient struct {
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+}
+
+// OAuthConsentParams are the parameters the OAuthAuthorizationConsent method
+// accepts.
+type OAuthConsentParams struct {
+	Approve bool `json:"approve"`
+}
+
+// OAuthConsentResponse tells the site where to send the user after they
+// approved or denied an authorization request.
+type OAuthConsentResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// AuthorizationCodeGrantParams are the parameters the AuthorizationCodeGrant
+// method accepts.
+type AuthorizationCodeGrantParams struct {
+	Code         string `json:"code"`
+	RedirectURI  string `json:"redirect_uri"`
+	CodeVerifier string `json:"code_verifier"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+func (a *API) requireOAuthServerEnabled(w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	if !a.config.OAuthServer.Enabled {
+		return nil, notFoundError("OAuth server is disabled")
+	}
+	return ctx, nil
+}
+
+// requireFirstPartySession rejects access tokens issued to third-party OAuth
+// clients, which must not be able to manage the user's account.
+func (a *API) requireFirstPartySession(w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	claims := getClaims(ctx)
+	if claims != nil && claims.ClientID != "" {
+		return nil, forbiddenError("This endpoint cannot be used with tokens issued to OAuth clients")
+	}
+	return ctx, nil
+}
+
+// OAuthAuthorize starts the authorization_code flow of a third-party OAuth
+// client and sends the user to the consent page on the site.
+func (a *API) OAuthAuthorize(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	config := a.config
+	query := r.URL.Query()
+
+	clientID, err := uuid.FromString(query.Get("client_id"))
+	if err != nil {
+		return badRequestError("Invalid client_id")
+	}
+
+	client, err := models.FindOAuthClientByID(db, clientID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return badRequestError("Invalid client_id")
+		}
+		return internalServerError("Database error finding OAuth client").WithInternalError(err)
+	}
+
+	redirectURI := query.Get("redirect_uri")
+	redirectURIProvided := redirectURI != ""
+	if !redirectURIProvided && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+
+	if !client.HasRedirectURI(redirectURI) {
+		return badRequestError("redirect_uri is not registered for this client")
+	}
+
+	// the redirect URI is trusted from here on, so errors are reported
+	// back to the client
+	state := query.Get("state")
+
+	if query.Get("response_type") != "code" {
+		return a.redirectOAuthError(w, r, redirectURI, state, oauthError("unsupported_response_type", "Only the code response type is supported"))
+	}
+
+	scopes := models.ParseScopes(query.Get("scope"))
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+
+	if !client.AllowsScopes(scopes) {
+		return a.redirectOAuthError(w, r, redirectURI, state, oauthError("invalid_scope", "Requested scope is not allowed for this client"))
+	}
+
+	codeChallenge := query.Get("code_challenge")
+	codeChallengeMethod := query.Get("code_challenge_method")
+	if codeChallenge == "" {
+		return a.redirectOAuthError(w, r, redirectURI, state, oauthError("invalid_request", "code_challenge is required"))
+	}
+
+	// the plain method would leak the verifier with the authorization
+	// request, so only S256 is accepted
+	method, err := models.ParseCodeChallengeMethod(codeChallengeMethod)
+	if err != nil || method != models.SHA256 {
+		return a.redirectOAuthError(w, r, redirectURI, state, oauthError("invalid_request", "code_challenge_method must be S256"))
+	}
+
+	if err := validatePKCEParams(codeChallengeMethod, codeChallenge); err != nil {
+		return a.redirectOAuthError(w, r, redirectURI, state, err)
+	}
+
+	flowState := models.NewOAuthAuthorizationFlowState(client.ID, redirectURI, redirectURIProvided, models.FormatScopes(scopes), state, query.Get("nonce"), codeChallenge, method)
+	if err := db.Create(flowState); err != nil {
+		return a.redirectOAuthError(w, r, redirectURI, state, internalServerError("Database error creating authorization request").WithInternalError(err))
+	}
+
+	consentURL, err := url.Parse(config.OAuthServer.ConsentURL)
+	if err != nil {
+		return internalServerError("Invalid OAuth consent URL").WithInternalError(err)
+	}
+
+	q := consentURL.Query()
+	q.Set("authorization_id", flowState.ID.String())
+	consentURL.RawQuery = q.Encode()
+
+	http.Redirect(w, r, consentURL.String(), http.StatusFound)
+	return nil
+}
+
+// OAuthAuthorizationGet returns a pending authorization request so that the
+// site can render the consent page.
+func (a *API) OAuthAuthorizationGet(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	user := getUser(ctx)
+
+	flowState, client, err := a.loadOAuthAuthorization(db, chi.URLParam(r, "authorization_id"))
+	if err != nil {
+		return err
+	}
+
+	scopes := models.ParseScopes(flowState.Scopes.String())
+
+	consented := false
+	consent, err := models.FindOAuthConsent(db, user.ID, client.ID)
+	if err != nil && !models.IsNotFoundError(err) {
+		return internalServerError("Database error finding OAuth consent").WithInternalError(err)
+	} else if consent != nil {
+		consented = consent.Covers(scopes)
+	}
+
+	return sendJSON(w, http.StatusOK, &OAuthAuthorizationResponse{
+		AuthorizationID: flowState.ID.String(),
+		Client: OAuthAuthorizationClient{
+			ClientID: client.ID.String(),
+			Name:     client.Name,
+		},
+		RedirectURI: flowState.RedirectURI.String(),
+		Scopes:      scopes,
+		Consented:   consented,
+	})
+}
+
+// OAuthAuthorizationConsent records whether the user approves or denies a
+// pending authorization request and returns where to redirect the user.
+func (a *API) OAuthAuthorizationConsent(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	user := getUser(ctx)
+
+	params := &OAuthConsentParams{}
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Could not read body").WithInternalError(err)
+	}
+
+	if err := json.Unmarshal(body, params); err != nil {
+		return badRequestError("Could not read consent params: %v", err)
+	}
+
+	flowState, client, err := a.loadOAuthAuthorization(db, chi.URLParam(r, "authorization_id"))
+	if err != nil {
+		return err
+	}
+
+	redirectURL, err := url.Parse(flowState.RedirectURI.String())
+	if err != nil {
+		return internalServerError("Invalid redirect URI").WithInternalError(err)
+	}
+
+	q := redirectURL.Query()
+	if flowState.OAuthState != "" {
+		q.Set("state", flowState.OAuthState.String())
+	}
+
+	err = db.Transaction(func(tx *storage.Connection) error {
+		if !params.Approve {
+			if terr := models.NewAuditLogEntry(r, tx, user, models.OAuthConsentDeniedAction, "", map[string]interface{}{
+				"client_id": client.ID,
+			}); terr != nil {
+				return terr
+			}
+
+			q.Set("error", "access_denied")
+			q.Set("error_description", "The user denied the authorization request")
+
+			return tx.Destroy(flowState)
+		}
+
+		scopes := models.ParseScopes(flowState.Scopes.String())
+
+		if terr := models.GrantOAuthConsent(tx, user.ID, client.ID, scopes); terr != nil {
+			return terr
+		}
+
+		if terr := models.NewAuditLogEntry(r, tx, user, models.OAuthConsentGrantedAction, "", map[string]interface{}{
+			"client_id": client.ID,
+			"scopes":    flowState.Scopes.String(),
+		}); terr != nil {
+			return terr
+		}
+
+		flowState.UserID = &user.ID
+		if terr := tx.UpdateOnly(flowState, "user_id", "updated_at"); terr != nil {
+			return terr
+		}
+
+		q.Set("code", flowState.AuthCode)
+
+		return nil
+	})
+	if err != nil {
+		return internalServerError("Database error recording OAuth consent").WithInternalError(err)
+	}
+
+	redirectURL.RawQuery = q.Encode()
+
+	return sendJSON(w, http.StatusOK, &OAuthConsentResponse{
+		RedirectTo: redirectURL.String(),
+	})
+}
+
+// AuthorizationCodeGrant exchanges an authorization code, issued to a
+// third-party OAuth client after the user consented, for tokens.
+func (a *API) AuthorizationCodeGrant(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	if !config.OAuthServer.Enabled {
+		return oauthError("unsupported_grant_type", "")
+	}
+
+	params := &AuthorizationCodeGrantParams{}
+	if err := readOAuthRequestParams(r, params); err != nil {
+		return err
+	}
+
+	if params.Code == "" || params.CodeVerifier == "" {
+		return oauthError("invalid_request", "code and code_verifier are required")
+	}
+
+	client, err := a.authenticateOAuthClient(db, r, params.ClientID, params.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	flowState, err := models.FindFlowStateByAuthCode(db, params.Code)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid authorization code")
+		}
+		return internalServerError("Database error finding authorization code").WithInternalError(err)
+	}
+
+	if !flowState.IsOAuthAuthorization() || *flowState.OAuthClientID != client.ID || flowState.UserID == nil {
+		return oauthError("invalid_grant", "Invalid authorization code")
+	}
+
+	if flowState.IsExpired(config.External.FlowStateExpiryDuration) {
+		return oauthError("invalid_grant", "Authorization code has expired")
+	}
+
+	// the redirect_uri has to be repeated only when the authorization
+	// request included it (RFC 6749 section 4.1.3)
+	if (flowState.RedirectURIProvided || params.RedirectURI != "") && params.RedirectURI != flowState.RedirectURI.String() {
+		return oauthError("invalid_grant", "redirect_uri does not match the authorization request")
+	}
+
+	if err := flowState.VerifyPKCE(params.CodeVerifier); err != nil {
+		return oauthError("invalid_grant", err.Error())
+	}
+
+	user, err := models.FindUserByID(db, *flowState.UserID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid authorization code")
+		}
+		return internalServerError("Database error finding user").WithInternalError(err)
+	}
+
+	if user.IsBanned() {
+		return oauthError("invalid_grant", "User is banned")
+	}
+
+	var token *AccessTokenResponse
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+
+		// the code can only be used once, concurrent exchanges of the
+		// same code are rejected by all but one request
+		if terr = flowState.Consume(tx); terr != nil {
+			if models.IsNotFoundError(terr) {
+				return oauthError("invalid_grant", "Invalid authorization code")
+			}
+			return internalServerError("Database error consuming authorization code").WithInternalError(terr)
+		}
+
+		if terr = models.NewAuditLogEntry(r, tx, user, models.LoginAction, "", map[string]interface{}{
+			"provider_type": flowState.ProviderType,
+			"client_id":     client.ID,
+		}); terr != nil {
+			return terr
+		}
+
+		grantParams := models.GrantParams{
+			OAuthClientID: &client.ID,
+			Scopes:        flowState.Scopes.String(),
+			Nonce:         flowState.Nonce.String(),
+		}
+		grantParams.FillGrantParams(r)
+
+		token, terr = a.issueRefreshToken(ctx, tx, user, models.OAuthAuthorizationCode, grantParams)
+		return terr
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, token)
+}
+
+// loadOAuthAuthorization loads a pending authorization request that has not
+// yet been approved or denied.
+func (a *API) loadOAuthAuthorization(db *storage.Connection, authorizationID string) (*models.FlowState, *models.OAuthClient, error) {
+	if _, err := uuid.FromString(authorizationID); err != nil {
+		return nil, nil, notFoundError("Authorization request not found")
+	}
+
+	flowState, err := models.FindFlowStateByID(db, authorizationID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, nil, notFoundError("Authorization request not found")
+		}
+		return nil, nil, internalServerError("Database error finding authorization request").WithInternalError(err)
+	}
+
+	if !flowState.IsOAuthAuthorization() || flowState.UserID != nil {
+		return nil, nil, notFoundError("Authorization request not found")
+	}
+
+	if flowState.IsExpired(a.config.External.FlowStateExpiryDuration) {
+		return nil, nil, badRequestError("Authorization request has expired")
+	}
+
+	client, err := models.FindOAuthClientByID(db, *flowState.OAuthClientID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, nil, notFoundError("Authorization request not found")
+		}
+		return nil, nil, internalServerError("Database error finding OAuth client").WithInternalError(err)
+	}
+
+	return flowState, client, nil
+}
+
+// authenticateOAuthClient authenticates an OAuth client using HTTP Basic
+// authentication or the client_id and client_secret request parameters.
+// Public clients, which have no secret, only need to identify themselves.
+func (a *API) authenticateOAuthClient(db *storage.Connection, r *http.Request, clientID, clientSecret string) (*models.OAuthClient, error) {
+	if username, password, ok := r.BasicAuth(); ok {
+		clientID, clientSecret = username, password
+	}
+
+	id, err := uuid.FromString(clientID)
+	if err != nil {
+		return nil, oauthError("invalid_client", "Invalid client credentials")
+	}
+
+	client, err := models.FindOAuthClientByID(db, id)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, oauthError("invalid_client", "Invalid client credentials")
+		}
+		return nil, internalServerError("Database error finding OAuth client").WithInternalError(err)
+	}
+
+	if client.IsConfidential() && !client.VerifySecret(clientSecret) {
+		return nil, oauthError("invalid_client", "Invalid client credentials")
+	}
+
+	return client, nil
+}
+
+// redirectOAuthError reports an error of an authorization request back to
+// the client, as described in RFC 6749 Section 4.1.2.1.
+func (a *API) redirectOAuthError(w http.ResponseWriter, r *http.Request, redirectURI, state string, err error) error {
+	u, perr := url.Parse(redirectURI)
+	if perr != nil {
+		return err
+	}
+
+	q := getErrorQueryString(err, getRequestID(r.Context()), observability.GetLogEntry(r), u.Query())
+	if state != "" {
+		q.Set("state", state)
+	}
+
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+	return nil
+}
+
+// readOAuthRequestParams reads the parameters of a request made by an OAuth
+// client, which are usually form encoded but may also be sent as JSON.
+func readOAuthRequestParams(r *http.Request, params interface{}) error {
+	var body []byte
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return oauthError("invalid_request", "Could not parse form body")
+		}
+
+		values := make(map[string]string, len(r.PostForm))
+		for key := range r.PostForm {
+			values[key] = r.PostForm.Get(key)
+		}
+
+		var err error
+		if body, err = json.Marshal(values); err != nil {
+			return internalServerError("Could not read body").WithInternalError(err)
+		}
+	} else {
+		var err error
+		if body, err = getBodyBytes(r); err != nil {
+			return internalServerError("Could not read body").WithInternalError(err)
+		}
+	}
+
+	if err := json.Unmarshal(body, params); err != nil {
+		return oauthError("invalid_request", "Could not parse request body")
+	}
+
+	return nil
+}
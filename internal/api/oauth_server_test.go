@@ -0,0 +1,382 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+const oauthTestRedirectURI = "https://client.example.com/callback"
+
+type OAuthServerTestSuite struct {
+	suite.Suite
+	API      *API
+	Config   *conf.GlobalConfiguration
+	AdminJWT string
+}
+
+func TestOAuthServer(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.OAuthServer.Enabled = true
+
+	ts := &OAuthServerTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *OAuthServerTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	claims := &GoTrueClaims{
+		Role: "supabase_admin",
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.Config.JWT.Secret))
+	require.NoError(ts.T(), err, "Error generating admin jwt")
+	ts.AdminJWT = token
+
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error creating test user model")
+	now := time.Now()
+	u.EmailConfirmedAt = &now
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+}
+
+func (ts *OAuthServerTestSuite) createClient(confidential bool) *OAuthClientResponse {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"name":          "Test Client",
+		"redirect_uris": []string{oauthTestRedirectURI},
+		"scopes":        []string{"openid", "email"},
+		"confidential":  confidential,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/admin/oauth/clients", &buffer)
+	req.Header.Set("Authorization", "Bearer "+ts.AdminJWT)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusCreated, w.Code)
+
+	client := &OAuthClientResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(client))
+	return client
+}
+
+func (ts *OAuthServerTestSuite) userAccessToken() string {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	return data.Token
+}
+
+// authorize runs the authorization request and consent steps and returns the
+// resulting authorization code. The redirect URI is omitted when it is empty.
+func (ts *OAuthServerTestSuite) authorize(clientID, redirectURI, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", clientID)
+	if redirectURI != "" {
+		query.Set("redirect_uri", redirectURI)
+	}
+	query.Set("scope", "openid email")
+	query.Set("state", "client-state")
+	query.Set("nonce", "client-nonce")
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "s256")
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/oauth/authorize?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusFound, w.Code)
+
+	consentURL, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), strings.HasPrefix(consentURL.String(), ts.Config.OAuthServer.ConsentURL))
+
+	authorizationID := consentURL.Query().Get("authorization_id")
+	require.NotEmpty(ts.T(), authorizationID)
+
+	userToken := ts.userAccessToken()
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/oauth/authorizations/"+authorizationID, nil)
+	req.Header.Set("Authorization", "Bearer "+userToken)
+	w = httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	authorization := OAuthAuthorizationResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&authorization))
+	require.Equal(ts.T(), clientID, authorization.Client.ClientID)
+	require.Equal(ts.T(), []string{"openid", "email"}, authorization.Scopes)
+	require.False(ts.T(), authorization.Consented)
+
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"approve": true,
+	}))
+
+	req = httptest.NewRequest(http.MethodPost, "http://localhost/oauth/authorizations/"+authorizationID, &buffer)
+	req.Header.Set("Authorization", "Bearer "+userToken)
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	consent := OAuthConsentResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&consent))
+
+	redirectTo, err := url.Parse(consent.RedirectTo)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), strings.HasPrefix(consent.RedirectTo, oauthTestRedirectURI))
+	require.Equal(ts.T(), "client-state", redirectTo.Query().Get("state"))
+
+	code := redirectTo.Query().Get("code")
+	require.NotEmpty(ts.T(), code)
+	return code
+}
+
+func (ts *OAuthServerTestSuite) exchangeCode(clientID, clientSecret, redirectURI, code, codeVerifier string) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	if redirectURI != "" {
+		form.Set("redirect_uri", redirectURI)
+	}
+	form.Set("code_verifier", codeVerifier)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *OAuthServerTestSuite) TestAuthorizationCodeFlow() {
+	ts.Config.OIDC.Enabled = true
+	defer func() {
+		ts.Config.OIDC.Enabled = false
+	}()
+
+	client := ts.createClient(true)
+	require.NotEmpty(ts.T(), client.ClientSecret)
+
+	codeVerifier := "this-is-a-sufficiently-long-code-verifier-for-pkce-tests"
+	hashed := sha256.Sum256([]byte(codeVerifier))
+	codeChallenge := base64.RawURLEncoding.EncodeToString(hashed[:])
+
+	code := ts.authorize(client.ID.String(), oauthTestRedirectURI, codeChallenge)
+
+	w := ts.exchangeCode(client.ID.String(), client.ClientSecret, oauthTestRedirectURI, code, codeVerifier)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.NotEmpty(ts.T(), data.Token)
+	require.NotEmpty(ts.T(), data.RefreshToken)
+
+	claims := &GoTrueClaims{}
+	p := jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Name}}
+	_, err := p.ParseWithClaims(data.Token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(ts.Config.JWT.Secret), nil
+	})
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), client.ID.String(), claims.ClientID)
+	require.Equal(ts.T(), "openid email", claims.Scope)
+
+	// the ID token echoes the nonce of the authorization request
+	idTokenClaims := &IDTokenClaims{}
+	_, _, err = p.ParseUnverified(data.IDToken, idTokenClaims)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), "client-nonce", idTokenClaims.Nonce)
+
+	// the code can only be used once
+	w = ts.exchangeCode(client.ID.String(), client.ClientSecret, oauthTestRedirectURI, code, codeVerifier)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	// tokens issued to clients can't manage the user's account
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/user", nil)
+	req.Header.Set("Authorization", "Bearer "+data.Token)
+	w = httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusForbidden, w.Code)
+}
+
+func (ts *OAuthServerTestSuite) TestAuthorizationCodeGrantFailures() {
+	client := ts.createClient(true)
+	codeVerifier := "this-is-a-sufficiently-long-code-verifier-for-pkce-tests"
+	hashed := sha256.Sum256([]byte(codeVerifier))
+	code := ts.authorize(client.ID.String(), oauthTestRedirectURI, base64.RawURLEncoding.EncodeToString(hashed[:]))
+
+	cases := []struct {
+		desc         string
+		clientSecret string
+		codeVerifier string
+		expected     int
+	}{
+		{
+			desc:         "Invalid client secret",
+			clientSecret: "invalid",
+			codeVerifier: codeVerifier,
+			expected:     http.StatusBadRequest,
+		},
+		{
+			desc:         "Invalid code verifier",
+			clientSecret: client.ClientSecret,
+			codeVerifier: "this-is-not-the-code-verifier-used-for-the-challenge",
+			expected:     http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		ts.Run(c.desc, func() {
+			w := ts.exchangeCode(client.ID.String(), c.clientSecret, oauthTestRedirectURI, code, c.codeVerifier)
+			require.Equal(ts.T(), c.expected, w.Code)
+
+			data := map[string]interface{}{}
+			require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+			require.Contains(ts.T(), []string{"invalid_client", "invalid_grant"}, data["error"])
+		})
+	}
+}
+
+func (ts *OAuthServerTestSuite) TestAuthorizationCodeGrantRedirectURI() {
+	client := ts.createClient(true)
+	codeVerifier := "this-is-a-sufficiently-long-code-verifier-for-pkce-tests"
+	hashed := sha256.Sum256([]byte(codeVerifier))
+	codeChallenge := base64.RawURLEncoding.EncodeToString(hashed[:])
+
+	// the redirect_uri has to be repeated when the authorization request
+	// included it
+	code := ts.authorize(client.ID.String(), oauthTestRedirectURI, codeChallenge)
+	w := ts.exchangeCode(client.ID.String(), client.ClientSecret, "", code, codeVerifier)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	// and may be left out when it didn't, but has to match when it is sent
+	code = ts.authorize(client.ID.String(), "", codeChallenge)
+	w = ts.exchangeCode(client.ID.String(), client.ClientSecret, "https://other.example.com/callback", code, codeVerifier)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = ts.exchangeCode(client.ID.String(), client.ClientSecret, "", code, codeVerifier)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+}
+
+func (ts *OAuthServerTestSuite) refreshToken(clientID, clientSecret, refreshToken string) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("refresh_token", refreshToken)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=refresh_token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if clientID != "" {
+		req.SetBasicAuth(clientID, clientSecret)
+	}
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *OAuthServerTestSuite) TestRefreshTokenGrantAuthenticatesConfidentialClients() {
+	client := ts.createClient(true)
+	codeVerifier := "this-is-a-sufficiently-long-code-verifier-for-pkce-tests"
+	hashed := sha256.Sum256([]byte(codeVerifier))
+	code := ts.authorize(client.ID.String(), oauthTestRedirectURI, base64.RawURLEncoding.EncodeToString(hashed[:]))
+
+	w := ts.exchangeCode(client.ID.String(), client.ClientSecret, oauthTestRedirectURI, code, codeVerifier)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+
+	// without or with invalid client credentials
+	w = ts.refreshToken("", "", data.RefreshToken)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = ts.refreshToken(client.ID.String(), "invalid", data.RefreshToken)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	// with the credentials of another client
+	other := ts.createClient(true)
+	w = ts.refreshToken(other.ID.String(), other.ClientSecret, data.RefreshToken)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = ts.refreshToken(client.ID.String(), client.ClientSecret, data.RefreshToken)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+}
+
+func (ts *OAuthServerTestSuite) TestAuthorizeInvalidRequests() {
+	client := ts.createClient(false)
+	require.Empty(ts.T(), client.ClientSecret)
+
+	// unregistered redirect URIs are never redirected to
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost/oauth/authorize?response_type=code&client_id=%s&redirect_uri=%s", client.ID, url.QueryEscape("https://evil.example.com/callback")), nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	// other errors are reported back to the client
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost/oauth/authorize?response_type=code&client_id=%s&scope=admin&state=abc&code_challenge=%s", client.ID, strings.Repeat("a", 43)), nil)
+	w = httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusFound, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), "invalid_scope", location.Query().Get("error"))
+	require.Equal(ts.T(), "abc", location.Query().Get("state"))
+
+	// only the S256 code challenge method is supported
+	for _, method := range []string{"", "plain"} {
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost/oauth/authorize?response_type=code&client_id=%s&state=abc&code_challenge=%s&code_challenge_method=%s", client.ID, strings.Repeat("a", 43), method), nil)
+		w = httptest.NewRecorder()
+		ts.API.handler.ServeHTTP(w, req)
+		require.Equal(ts.T(), http.StatusFound, w.Code)
+
+		location, err = url.Parse(w.Header().Get("Location"))
+		require.NoError(ts.T(), err)
+		require.Equal(ts.T(), "invalid_request", location.Query().Get("error"))
+	}
+}
+
+func (ts *OAuthServerTestSuite) TestAuthorizeDisabled() {
+	ts.Config.OAuthServer.Enabled = false
+	defer func() {
+		ts.Config.OAuthServer.Enabled = true
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/oauth/authorize", nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+}
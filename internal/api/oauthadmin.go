@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/observability"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// defaultOAuthClientScopes are the scopes allowed for clients that are
+// registered without an explicit list of scopes.
+var defaultOAuthClientScopes = []string{"openid", "email", "phone", "profile"}
+
+// OAuthClientParams are the parameters used to register or update an OAuth
+// client.
+type OAuthClientParams struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+
+	// Confidential clients are issued a secret. Defaults to true, set to
+	// false for clients that can't keep a secret such as mobile apps.
+	Confidential *bool `json:"confidential"`
+}
+
+// OAuthClientResponse is an OAuth client, including its secret when it has
+// just been generated.
+type OAuthClientResponse struct {
+	*models.OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+func (p *OAuthClientParams) validate(forUpdate bool) error {
+	if !forUpdate && p.Name == "" {
+		return badRequestError("name is required")
+	}
+
+	if !forUpdate && len(p.RedirectURIs) == 0 {
+		return badRequestError("At least one redirect URI is required")
+	}
+
+	for _, redirectURI := range p.RedirectURIs {
+		u, err := url.ParseRequestURI(redirectURI)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return badRequestError("Redirect URI %q is not an absolute URL", redirectURI)
+		}
+
+		if u.Fragment != "" {
+			return badRequestError("Redirect URI %q must not include a fragment", redirectURI)
+		}
+	}
+
+	return nil
+}
+
+// loadOAuthClient looks for a client_id parameter in the URL route and loads
+// the OAuth client with that ID into the context.
+func (a *API) loadOAuthClient(w http.ResponseWriter, r *http.Request) (context.Context, error) {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	clientID, err := uuid.FromString(chi.URLParam(r, "client_id"))
+	if err != nil {
+		return nil, notFoundError("OAuth client not found")
+	}
+
+	client, err := models.FindOAuthClientByID(db, clientID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, notFoundError("OAuth client not found")
+		}
+		return nil, internalServerError("Database error finding OAuth client").WithInternalError(err)
+	}
+
+	observability.LogEntrySetField(r, "oauth_client_id", client.ID.String())
+
+	return withOAuthClient(ctx, client), nil
+}
+
+// adminOAuthClientsList lists all registered OAuth clients.
+func (a *API) adminOAuthClientsList(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	clients, err := models.FindAllOAuthClients(db)
+	if err != nil {
+		return internalServerError("Database error finding OAuth clients").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, map[string]interface{}{
+		"items": clients,
+	})
+}
+
+// adminOAuthClientsCreate registers a new OAuth client. The client secret is
+// only ever returned in this response.
+func (a *API) adminOAuthClientsCreate(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Unable to read request body").WithInternalError(err)
+	}
+
+	var params OAuthClientParams
+	if err := json.Unmarshal(body, &params); err != nil {
+		return badRequestError("Unable to parse JSON").WithInternalError(err)
+	}
+
+	if err := params.validate(false /* <- forUpdate */); err != nil {
+		return err
+	}
+
+	scopes := params.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOAuthClientScopes
+	}
+
+	confidential := params.Confidential == nil || *params.Confidential
+
+	client, secret := models.NewOAuthClient(params.Name, params.RedirectURIs, scopes, confidential)
+
+	if err := db.Create(client); err != nil {
+		return internalServerError("Database error creating OAuth client").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusCreated, &OAuthClientResponse{
+		OAuthClient:  client,
+		ClientSecret: secret,
+	})
+}
+
+// adminOAuthClientsGet returns an OAuth client.
+func (a *API) adminOAuthClientsGet(w http.ResponseWriter, r *http.Request) error {
+	client := getOAuthClient(r.Context())
+
+	return sendJSON(w, http.StatusOK, client)
+}
+
+// adminOAuthClientsUpdate updates the name, redirect URIs or scopes of an
+// OAuth client.
+func (a *API) adminOAuthClientsUpdate(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	client := getOAuthClient(ctx)
+
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Unable to read request body").WithInternalError(err)
+	}
+
+	var params OAuthClientParams
+	if err := json.Unmarshal(body, &params); err != nil {
+		return badRequestError("Unable to parse JSON").WithInternalError(err)
+	}
+
+	if err := params.validate(true /* <- forUpdate */); err != nil {
+		return err
+	}
+
+	if params.Confidential != nil {
+		return badRequestError("Clients cannot be changed between confidential and public")
+	}
+
+	if params.Name != "" {
+		client.Name = params.Name
+	}
+
+	if len(params.RedirectURIs) > 0 {
+		client.RedirectURIs = params.RedirectURIs
+	}
+
+	if len(params.Scopes) > 0 {
+		client.Scopes = params.Scopes
+	}
+
+	if err := db.UpdateOnly(client, "name", "redirect_uris", "scopes", "updated_at"); err != nil {
+		return internalServerError("Database error updating OAuth client").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, client)
+}
+
+// adminOAuthClientsRegenerateSecret issues a new secret to a confidential
+// OAuth client, invalidating the previous one.
+func (a *API) adminOAuthClientsRegenerateSecret(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	client := getOAuthClient(ctx)
+
+	if !client.IsConfidential() {
+		return badRequestError("Public clients do not have a secret")
+	}
+
+	secret := client.GenerateSecret()
+
+	if err := db.UpdateOnly(client, "client_secret_hash", "updated_at"); err != nil {
+		return internalServerError("Database error updating OAuth client").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, &OAuthClientResponse{
+		OAuthClient:  client,
+		ClientSecret: secret,
+	})
+}
+
+// adminOAuthClientsDelete deletes an OAuth client, together with all of the
+// sessions and consents issued to it.
+func (a *API) adminOAuthClientsDelete(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	client := getOAuthClient(ctx)
+
+	if err := db.Transaction(func(tx *storage.Connection) error {
+		return tx.Destroy(client)
+	}); err != nil {
+		return internalServerError("Database error deleting OAuth client").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, client)
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// OpenIDProviderMetadata is the OpenID Provider Metadata served at
+// /.well-known/openid-configuration, as defined in OpenID Connect Discovery
+// 1.0.
+type OpenIDProviderMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint,omitempty"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint                string   `json:"revocation_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint,omitempty"`
+}
+
+// IDTokenClaims are the claims of an OpenID Connect ID token.
+type IDTokenClaims struct {
+	jwt.StandardClaims
+	Email                         string   `json:"email,omitempty"`
+	EmailVerified                 bool     `json:"email_verified,omitempty"`
+	Phone                         string   `json:"phone_number,omitempty"`
+	PhoneVerified                 bool     `json:"phone_number_verified,omitempty"`
+	Name                          string   `json:"name,omitempty"`
+	Picture                       string   `json:"picture,omitempty"`
+	AuthenticatorAssuranceLevel   string   `json:"acr,omitempty"`
+	AuthenticationMethodReference []string `json:"amr,omitempty"`
+	AuthTime                      int64    `json:"auth_time,omitempty"`
+	SessionId                     string   `json:"sid,omitempty"`
+	Nonce                         string   `json:"nonce,omitempty"`
+}
+
+// UserInfoResponse is the response of the UserInfo endpoint.
+type UserInfoResponse struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
+	Phone         string `json:"phone_number,omitempty"`
+	PhoneVerified bool   `json:"phone_number_verified"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+func (a *API) requireOIDCEnabled(w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	if !a.config.OIDC.Enabled {
+		return nil, notFoundError("OpenID Connect is disabled")
+	}
+	return ctx, nil
+}
+
+// OpenIDConfiguration serves the OpenID Provider Metadata.
+func (a *API) OpenIDConfiguration(w http.ResponseWriter, r *http.Request) error {
+	config := a.config
+	baseURL := strings.TrimSuffix(config.API.ExternalURL, "/")
+
+	metadata := &OpenIDProviderMetadata{
+		Issuer:                           config.JWT.Issuer,
+		JWKSURI:                          baseURL + "/.well-known/jwks.json",
+		TokenEndpoint:                    baseURL + "/token",
+		UserinfoEndpoint:                 baseURL + "/userinfo",
+		ResponseTypesSupported:           []string{"token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{config.JWT.Algorithm},
+		ScopesSupported:                  []string{"openid", "email", "phone", "profile"},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "acr", "amr", "sid", "nonce",
+			"email", "email_verified", "phone_number", "phone_number_verified",
+			"name", "picture",
+		},
+		GrantTypesSupported: []string{"password", "refresh_token"},
+	}
+
+	if config.OAuthServer.Enabled {
+		metadata.AuthorizationEndpoint = baseURL + "/oauth/authorize"
+		metadata.ResponseTypesSupported = []string{"code"}
+		metadata.GrantTypesSupported = append(metadata.GrantTypesSupported, "authorization_code")
+		metadata.CodeChallengeMethodsSupported = []string{"S256"}
+		metadata.TokenEndpointAuthMethodsSupported = []string{"client_secret_basic", "client_secret_post", "none"}
+		metadata.IntrospectionEndpoint = baseURL + "/token/introspect"
+		metadata.RevocationEndpoint = baseURL + "/token/revoke"
+	}
+
+	if config.DeviceAuthorization.Enabled {
+		metadata.DeviceAuthorizationEndpoint = baseURL + "/device/code"
+		metadata.GrantTypesSupported = append(metadata.GrantTypesSupported, DeviceCodeGrantType)
+	}
+
+	if config.ServiceAccounts.Enabled {
+		metadata.GrantTypesSupported = append(metadata.GrantTypesSupported, "client_credentials")
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=600")
+
+	return sendJSON(w, http.StatusOK, metadata)
+}
+
+// UserInfo returns the claims about the authenticated user.
+func (a *API) UserInfo(w http.ResponseWriter, r *http.Request) error {
+	user := getUser(r.Context())
+	if user == nil {
+		return unauthorizedError("Invalid token: missing user")
+	}
+
+	name, picture := userProfile(user)
+
+	return sendJSON(w, http.StatusOK, &UserInfoResponse{
+		Subject:       user.ID.String(),
+		Email:         user.GetEmail(),
+		EmailVerified: user.IsConfirmed(),
+		Phone:         user.GetPhone(),
+		PhoneVerified: user.IsPhoneConfirmed(),
+		Name:          name,
+		Picture:       picture,
+		UpdatedAt:     user.UpdatedAt.Unix(),
+	})
+}
+
+// generateIDToken issues an OpenID Connect ID token for the user and session,
+// built from the same claims as the access token. The nonce of the
+// authorization request, if any, is echoed back to the client.
+func generateIDToken(tx *storage.Connection, user *models.User, sessionId *uuid.UUID, nonce string, config *conf.JWTConfiguration) (string, error) {
+	claims, _, err := accessTokenClaims(tx, user, sessionId, config)
+	if err != nil {
+		return "", err
+	}
+
+	amr := make([]string, 0, len(claims.AuthenticationMethodReference))
+	for _, entry := range claims.AuthenticationMethodReference {
+		amr = append(amr, entry.Method)
+	}
+
+	name, picture := userProfile(user)
+
+	standardClaims := claims.StandardClaims
+	if claims.ClientID != "" {
+		// ID tokens issued to OAuth clients are intended for them
+		standardClaims.Audience = claims.ClientID
+	}
+
+	return signJWT(&IDTokenClaims{
+		StandardClaims:                standardClaims,
+		Email:                         claims.Email,
+		EmailVerified:                 user.IsConfirmed(),
+		Phone:                         claims.Phone,
+		PhoneVerified:                 user.IsPhoneConfirmed(),
+		Name:                          name,
+		Picture:                       picture,
+		AuthenticatorAssuranceLevel:   claims.AuthenticatorAssuranceLevel,
+		AuthenticationMethodReference: amr,
+		AuthTime:                      claims.AuthTime,
+		SessionId:                     claims.SessionId,
+		Nonce:                         nonce,
+	}, config)
+}
+
+// userProfile extracts the standard name and picture profile claims from the
+// user metadata, as populated by the external providers.
+func userProfile(user *models.User) (name string, picture string) {
+	for _, key := range []string{"name", "full_name"} {
+		if value, ok := user.UserMetaData[key].(string); ok && value != "" {
+			name = value
+			break
+		}
+	}
+
+	for _, key := range []string{"picture", "avatar_url"} {
+		if value, ok := user.UserMetaData[key].(string); ok && value != "" {
+			picture = value
+			break
+		}
+	}
+
+	return name, picture
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+type OpenIDTestSuite struct {
+	suite.Suite
+	API    *API
+	Config *conf.GlobalConfiguration
+}
+
+func TestOpenID(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	config.OIDC.Enabled = true
+	config.JWT.Algorithm = conf.JWTAlgorithmES256
+	config.JWT.SigningKey = signingKey
+	config.JWT.KeyID = "test-key"
+	config.JWT.Issuer = config.API.ExternalURL
+
+	ts := &OpenIDTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *OpenIDTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, map[string]interface{}{
+		"full_name":  "Test User",
+		"avatar_url": "https://example.com/avatar.png",
+	})
+	require.NoError(ts.T(), err, "Error creating test user model")
+	now := time.Now()
+	u.EmailConfirmedAt = &now
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+}
+
+func (ts *OpenIDTestSuite) TestOpenIDConfiguration() {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/.well-known/openid-configuration", nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	var metadata OpenIDProviderMetadata
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&metadata))
+	require.Equal(ts.T(), ts.Config.JWT.Issuer, metadata.Issuer)
+	require.Equal(ts.T(), ts.Config.API.ExternalURL+"/.well-known/jwks.json", metadata.JWKSURI)
+	require.Equal(ts.T(), []string{conf.JWTAlgorithmES256}, metadata.IDTokenSigningAlgValuesSupported)
+	require.Equal(ts.T(), []string{"password", "refresh_token"}, metadata.GrantTypesSupported)
+	require.Empty(ts.T(), metadata.CodeChallengeMethodsSupported)
+}
+
+func (ts *OpenIDTestSuite) TestOpenIDConfigurationDisabled() {
+	ts.Config.OIDC.Enabled = false
+	defer func() {
+		ts.Config.OIDC.Enabled = true
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/.well-known/openid-configuration", nil)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+}
+
+func (ts *OpenIDTestSuite) TestIDTokenAndUserInfo() {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.NotEmpty(ts.T(), data.IDToken)
+
+	claims := &IDTokenClaims{}
+	p := jwt.Parser{ValidMethods: []string{conf.JWTAlgorithmES256}}
+	idToken, err := p.ParseWithClaims(data.IDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return ts.Config.JWT.SigningKey.Public(), nil
+	})
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), "test-key", idToken.Header["kid"])
+	require.Equal(ts.T(), data.User.ID.String(), claims.Subject)
+	require.Equal(ts.T(), ts.Config.JWT.Issuer, claims.Issuer)
+	require.Equal(ts.T(), "test@example.com", claims.Email)
+	require.True(ts.T(), claims.EmailVerified)
+	require.Equal(ts.T(), []string{models.PasswordGrant.String()}, claims.AuthenticationMethodReference)
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer "+data.Token)
+	w = httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	userInfo := UserInfoResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&userInfo))
+	require.Equal(ts.T(), data.User.ID.String(), userInfo.Subject)
+	require.Equal(ts.T(), "test@example.com", userInfo.Email)
+	require.Equal(ts.T(), "Test User", userInfo.Name)
+	require.Equal(ts.T(), "https://example.com/avatar.png", userInfo.Picture)
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/metering"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+	"github.com/supabase/gotrue/internal/utilities"
+	"github.com/supabase/gotrue/internal/webauthn"
+)
+
+// PasskeyChallengeResponse is the response of the passkey challenge
+// endpoint. The request options are passed to navigator.credentials.get().
+type PasskeyChallengeResponse struct {
+	ID        uuid.UUID                `json:"id"`
+	ExpiresAt int64                    `json:"expires_at"`
+	WebAuthn  *WebAuthnChallengeObject `json:"web_authn"`
+}
+
+// PasskeyGrantParams are the parameters the PasskeyGrant method accepts.
+type PasskeyGrantParams struct {
+	ChallengeID uuid.UUID                             `json:"challenge_id"`
+	WebAuthn    *webauthn.CredentialAssertionResponse `json:"web_authn"`
+}
+
+func (a *API) requirePasskeyLoginEnabled(w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	if !a.config.MFA.WebAuthn.Enabled || !a.config.MFA.WebAuthn.PasskeyLoginEnabled {
+		return nil, notFoundError("Passkey login is disabled")
+	}
+	return ctx, nil
+}
+
+// PasskeyChallenge starts signing in with a passkey. Any passkey of the site
+// can be used, the user is identified by the passkey.
+func (a *API) PasskeyChallenge(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	challenge := models.NewPasskeyChallenge(utilities.GetIPAddress(r))
+	if err := db.Create(challenge); err != nil {
+		return internalServerError("Database error creating challenge").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, &PasskeyChallengeResponse{
+		ID:        challenge.ID,
+		ExpiresAt: challenge.GetExpiryTime(config.MFA.ChallengeExpiryDuration).Unix(),
+		WebAuthn: &WebAuthnChallengeObject{
+			CredentialRequestOptions: a.webAuthnRelyingParty().RequestOptions(challenge.Challenge, nil, webauthn.UserVerificationRequired),
+		},
+	})
+}
+
+// PasskeyGrant signs in with a passkey, a verified webauthn factor that is
+// a discoverable credential. As the authenticator verifies the user, the
+// session is issued at AAL2.
+func (a *API) PasskeyGrant(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	if !config.MFA.WebAuthn.Enabled || !config.MFA.WebAuthn.PasskeyLoginEnabled {
+		return oauthError("unsupported_grant_type", "")
+	}
+
+	params := &PasskeyGrantParams{}
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Could not read body").WithInternalError(err)
+	}
+
+	if err := json.Unmarshal(body, params); err != nil {
+		return badRequestError("Could not read passkey grant params: %v", err)
+	}
+
+	if params.WebAuthn == nil {
+		return badRequestError("web_authn is required")
+	}
+
+	userHandle, err := params.WebAuthn.UserHandle()
+	if err != nil {
+		return oauthError("invalid_grant", "Invalid passkey")
+	}
+
+	userID, err := uuid.FromBytes(userHandle)
+	if err != nil {
+		return oauthError("invalid_grant", "Invalid passkey")
+	}
+
+	credentialID, err := params.WebAuthn.CredentialID()
+	if err != nil {
+		return oauthError("invalid_grant", "Invalid passkey")
+	}
+
+	user, err := models.FindUserByID(db, userID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid passkey")
+		}
+		return internalServerError("Database error finding user").WithInternalError(err)
+	}
+
+	if user.IsBanned() {
+		return oauthError("invalid_grant", "User is banned")
+	}
+
+	factor, err := models.FindVerifiedWebAuthnFactorByCredentialID(db, user, credentialID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid passkey")
+		}
+		return internalServerError("Database error finding factor").WithInternalError(err)
+	}
+
+	// the challenge can only be used once, also when the passkey turns out
+	// to be invalid
+	var challenge *models.PasskeyChallenge
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+		if challenge, terr = models.FindPasskeyChallengeByID(tx, params.ChallengeID, true); terr != nil {
+			if models.IsNotFoundError(terr) {
+				return oauthError("invalid_grant", "Invalid challenge")
+			}
+			return internalServerError("Database error finding challenge").WithInternalError(terr)
+		}
+
+		if terr = tx.Destroy(challenge); terr != nil {
+			return internalServerError("Database error deleting challenge").WithInternalError(terr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if challenge.HasExpired(config.MFA.ChallengeExpiryDuration) || challenge.IPAddress != utilities.GetIPAddress(r) {
+		return oauthError("invalid_grant", "Invalid challenge")
+	}
+
+	credential := factor.WebAuthnCredential.Credential
+	signCount, err := a.webAuthnRelyingParty().VerifyAssertion(challenge.Challenge, &credential, params.WebAuthn, true)
+	if err != nil {
+		return oauthError("invalid_grant", "Invalid passkey").WithInternalError(err)
+	}
+	credential.SignCount = signCount
+
+	var token *AccessTokenResponse
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+
+		if terr = factor.UpdateWebAuthnCredential(tx, &credential); terr != nil {
+			return terr
+		}
+
+		if terr = models.NewAuditLogEntry(r, tx, user, models.LoginAction, "", map[string]interface{}{
+			"provider":  "passkey",
+			"factor_id": factor.ID,
+		}); terr != nil {
+			return terr
+		}
+
+		if terr = triggerEventHooks(ctx, tx, LoginEvent, user, config); terr != nil {
+			return terr
+		}
+
+		grantParams := models.GrantParams{
+			FactorID: &factor.ID,
+			AAL:      models.AAL2.String(),
+		}
+		grantParams.FillGrantParams(r)
+
+		token, terr = a.issueRefreshToken(ctx, tx, user, models.WebAuthnSignIn, grantParams)
+		if terr != nil {
+			return terr
+		}
+
+		if terr = a.setCookieTokens(config, token, false, w); terr != nil {
+			return internalServerError("Failed to set JWT cookie. %s", terr)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	metering.RecordLogin("passkey", user.ID)
+	return sendJSON(w, http.StatusOK, token)
+}
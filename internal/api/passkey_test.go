@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/webauthn/webauthntest"
+)
+
+type PasskeyTestSuite struct {
+	suite.Suite
+	API    *API
+	Config *conf.GlobalConfiguration
+}
+
+func TestPasskey(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.MFA.WebAuthn.Enabled = true
+	config.MFA.WebAuthn.PasskeyLoginEnabled = true
+
+	ts := &PasskeyTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *PasskeyTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error creating test user model")
+	now := time.Now()
+	u.EmailConfirmedAt = &now
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+}
+
+func (ts *PasskeyTestSuite) newAuthenticator() *webauthntest.Authenticator {
+	return webauthntest.NewAuthenticator(ts.Config.MFA.WebAuthn.RPOrigins[0])
+}
+
+func (ts *PasskeyTestSuite) signIn() *AccessTokenResponse {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=password", "", map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *PasskeyTestSuite) challenge(token, factorID string) *ChallengeFactorResponse {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/challenge", factorID), token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &ChallengeFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	require.NotNil(ts.T(), data.WebAuthn)
+	return data
+}
+
+// enroll registers the credential of the authenticator as a webauthn factor.
+func (ts *PasskeyTestSuite) enroll(authenticator *webauthntest.Authenticator) (string, *AccessTokenResponse) {
+	token := ts.signIn().Token
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors", token, map[string]interface{}{
+		"factor_type":   models.WebAuthn,
+		"friendly_name": "laptop",
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	enrollResp := &EnrollFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(enrollResp))
+	require.Equal(ts.T(), models.WebAuthn, enrollResp.Type)
+	require.Nil(ts.T(), enrollResp.TOTP)
+	factorID := enrollResp.ID.String()
+
+	challenge := ts.challenge(token, factorID)
+	require.NotNil(ts.T(), challenge.WebAuthn.CredentialCreationOptions)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), token, map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"web_authn":    authenticator.Create(challenge.WebAuthn.CredentialCreationOptions),
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return factorID, data
+}
+
+func (ts *PasskeyTestSuite) passkeyChallenge() *PasskeyChallengeResponse {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/passkey/challenge", "", nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &PasskeyChallengeResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *PasskeyTestSuite) TestWebAuthnFactor() {
+	authenticator := ts.newAuthenticator()
+	factorID, token := ts.enroll(authenticator)
+
+	session, err := models.FindSessionByUserID(ts.API.db, token.User.ID)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), session.IsAAL2())
+
+	factor, err := models.FindFactorByFactorID(ts.API.db, *session.FactorID)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), factorID, factor.ID.String())
+	require.True(ts.T(), factor.IsVerified())
+	require.Equal(ts.T(), authenticator.CredentialID, factor.WebAuthnCredential.ID)
+
+	// a verified factor is challenged with an assertion
+	challenge := ts.challenge(token.Token, factorID)
+	require.NotNil(ts.T(), challenge.WebAuthn.CredentialRequestOptions)
+	require.Len(ts.T(), challenge.WebAuthn.CredentialRequestOptions.AllowCredentials, 1)
+
+	other := ts.newAuthenticator()
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), token.Token, map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"web_authn":    other.Get(challenge.WebAuthn.CredentialRequestOptions),
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	// the invalid credential counts towards the lockout of the factor
+	factor, err = models.FindFactorByFactorID(ts.API.db, factor.ID)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 1, factor.FailedVerificationAttempts)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factorID), token.Token, map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"web_authn":    authenticator.Get(challenge.WebAuthn.CredentialRequestOptions),
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	factor, err = models.FindFactorByFactorID(ts.API.db, factor.ID)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), authenticator.SignCount, factor.WebAuthnCredential.SignCount)
+	require.Equal(ts.T(), 0, factor.FailedVerificationAttempts)
+}
+
+func (ts *PasskeyTestSuite) TestWebAuthnFactorDisabled() {
+	ts.Config.MFA.WebAuthn.Enabled = false
+	defer func() {
+		ts.Config.MFA.WebAuthn.Enabled = true
+	}()
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors", ts.signIn().Token, map[string]interface{}{
+		"factor_type": models.WebAuthn,
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/passkey/challenge", "", nil)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+}
+
+func (ts *PasskeyTestSuite) TestPasskeyLogin() {
+	authenticator := ts.newAuthenticator()
+	ts.enroll(authenticator)
+
+	challenge := ts.passkeyChallenge()
+	require.Empty(ts.T(), challenge.WebAuthn.CredentialRequestOptions.AllowCredentials)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=passkey", "", map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"web_authn":    authenticator.Get(challenge.WebAuthn.CredentialRequestOptions),
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	require.NotEmpty(ts.T(), data.RefreshToken)
+	require.Equal(ts.T(), "test@example.com", data.User.GetEmail())
+
+	ctx, err := ts.API.parseJWTClaims(data.Token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	claims := getClaims(ctx)
+	require.Equal(ts.T(), models.AAL2.String(), claims.AuthenticatorAssuranceLevel)
+	require.Equal(ts.T(), models.WebAuthnSignIn.String(), claims.AuthenticationMethodReference[0].Method)
+
+	// the challenge can only be used once
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=passkey", "", map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"web_authn":    authenticator.Get(challenge.WebAuthn.CredentialRequestOptions),
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *PasskeyTestSuite) TestPasskeyLoginRequiresUserVerification() {
+	authenticator := ts.newAuthenticator()
+	ts.enroll(authenticator)
+
+	// user presence is enough for a second factor, but not to sign in
+	authenticator.UserVerified = false
+
+	challenge := ts.passkeyChallenge()
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=passkey", "", map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"web_authn":    authenticator.Get(challenge.WebAuthn.CredentialRequestOptions),
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	data := map[string]interface{}{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), "invalid_grant", data["error"])
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha1" //#nosec G505 -- The leaked passwords corpus is indexed by SHA-1.
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/supabase/gotrue/internal/conf"
+)
+
+// Reasons a password can be rejected by the password policy for.
+const (
+	WeakPasswordCharacters     = "characters"
+	WeakPasswordUserIdentifier = "user_identifier"
+	WeakPasswordEntropy        = "entropy"
+	WeakPasswordLeaked         = "leaked"
+)
+
+const leakedPasswordMessage = "Password is known to have been leaked, please choose another one"
+
+// WeakPasswordError is returned when a password doesn't satisfy the
+// password policy. It lists all the reasons the password was rejected for,
+// so that clients can tell users what to change.
+type WeakPasswordError struct {
+	Message string   `json:"-"`
+	Reasons []string `json:"reasons"`
+}
+
+func (e *WeakPasswordError) Error() string {
+	return e.Message
+}
+
+// passwordCharacterClass returns the character class of the password policy
+// the character belongs to.
+func passwordCharacterClass(r rune) string {
+	switch {
+	case unicode.IsLower(r):
+		return "lowercase"
+	case unicode.IsUpper(r):
+		return "uppercase"
+	case unicode.IsDigit(r):
+		return "digits"
+	default:
+		return "symbols"
+	}
+}
+
+// passwordPoolSizes are the number of characters in each character class
+// that are assumed when estimating the entropy of a password.
+var passwordPoolSizes = map[string]float64{
+	"lowercase": 26,
+	"uppercase": 26,
+	"digits":    10,
+	"symbols":   33,
+}
+
+// passwordEntropy estimates the entropy of the password in bits. Each
+// character adds log2 of the size of the pool made of the character
+// classes the password uses, except that a character repeating the
+// previous one or continuing a sequence like "abc" or "321" adds one bit.
+func passwordEntropy(password string) float64 {
+	classes := make(map[string]bool)
+	for _, r := range password {
+		classes[passwordCharacterClass(r)] = true
+	}
+
+	pool := 0.0
+	for class := range classes {
+		pool += passwordPoolSizes[class]
+	}
+	bits := math.Log2(pool)
+
+	entropy := 0.0
+	var previous rune
+	for i, r := range []rune(password) {
+		if i > 0 && (r == previous || r == previous+1 || r == previous-1) {
+			entropy += 1
+		} else {
+			entropy += bits
+		}
+		previous = r
+	}
+
+	return entropy
+}
+
+// isLeakedPassword looks the SHA-1 hash of the password up in the leaked
+// passwords directory, which is in the Have I Been Pwned range format. Only
+// the range file of the first 5 characters of the hash is read.
+func isLeakedPassword(path, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password)) //#nosec G401 -- Not used to store passwords.
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(path, prefix+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineSuffix, count, found := strings.Cut(line, ":")
+		if !strings.EqualFold(lineSuffix, suffix) {
+			continue
+		}
+		// padded ranges contain made up hashes with a count of 0
+		if found {
+			if n, err := strconv.Atoi(count); err == nil && n == 0 {
+				continue
+			}
+		}
+		return true, nil
+	}
+
+	return false, scanner.Err()
+}
+
+// passwordUserIdentifiers returns the parts of the email address, phone
+// number and username that a password isn't allowed to contain.
+func passwordUserIdentifiers(email, phone string, data map[string]interface{}) []string {
+	identifiers := []string{}
+	if email != "" {
+		identifiers = append(identifiers, email)
+		if local, _, found := strings.Cut(email, "@"); found {
+			identifiers = append(identifiers, local)
+		}
+	}
+	if phone != "" {
+		identifiers = append(identifiers, strings.TrimPrefix(phone, "+"))
+	}
+	if username, ok := data["username"].(string); ok && username != "" {
+		identifiers = append(identifiers, username)
+	}
+	return identifiers
+}
+
+// validatePasswordPolicy checks the password against the password policy.
+// The identifiers are values identifying the user the password must not
+// contain, see passwordUserIdentifiers.
+func validatePasswordPolicy(policy *conf.PasswordPolicyConfiguration, password string, identifiers []string) error {
+	var reasons, messages []string
+
+	if len(policy.RequiredCharacters) > 0 {
+		classes := make(map[string]bool)
+		for _, r := range password {
+			classes[passwordCharacterClass(r)] = true
+		}
+
+		missing := []string{}
+		for _, class := range policy.RequiredCharacters {
+			if !classes[class] {
+				missing = append(missing, class)
+			}
+		}
+		if len(missing) > 0 {
+			reasons = append(reasons, WeakPasswordCharacters)
+			messages = append(messages, fmt.Sprintf("Password should contain at least one character of each of: %s", strings.Join(missing, ", ")))
+		}
+	}
+
+	if policy.RejectUserIdentifiers {
+		lowered := strings.ToLower(password)
+		for _, identifier := range identifiers {
+			// very short identifiers would reject too many passwords
+			if len(identifier) >= 3 && strings.Contains(lowered, strings.ToLower(identifier)) {
+				reasons = append(reasons, WeakPasswordUserIdentifier)
+				messages = append(messages, "Password should not contain your email address, phone number or username")
+				break
+			}
+		}
+	}
+
+	if policy.MinEntropy > 0 && passwordEntropy(password) < policy.MinEntropy {
+		reasons = append(reasons, WeakPasswordEntropy)
+		messages = append(messages, "Password is too easy to guess")
+	}
+
+	if policy.LeakedPasswordsPath != "" {
+		leaked, err := isLeakedPassword(policy.LeakedPasswordsPath, password)
+		if err != nil {
+			return internalServerError("Error checking leaked passwords").WithInternalError(err)
+		}
+		if leaked {
+			reasons = append(reasons, WeakPasswordLeaked)
+			messages = append(messages, leakedPasswordMessage)
+		}
+	}
+
+	if len(reasons) > 0 {
+		return &WeakPasswordError{
+			Message: strings.Join(messages, ". "),
+			Reasons: reasons,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/gotrue/internal/conf"
+)
+
+func TestPasswordEntropy(t *testing.T) {
+	require.Equal(t, 0.0, passwordEntropy(""))
+
+	// repeated characters and sequences add little entropy
+	require.Less(t, passwordEntropy("aaaaaaaaaaaa"), passwordEntropy("qzmtrwxkpbvc"))
+	require.Less(t, passwordEntropy("abcdefghijkl"), passwordEntropy("qzmtrwxkpbvc"))
+	require.Less(t, passwordEntropy("987654321"), passwordEntropy("927361548"))
+
+	// using more character classes adds entropy
+	require.Less(t, passwordEntropy("qzmtrwxk"), passwordEntropy("qZm7r#xK"))
+}
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	policy := &conf.PasswordPolicyConfiguration{
+		RequiredCharacters:    []string{"lowercase", "uppercase", "digits", "symbols"},
+		RejectUserIdentifiers: true,
+		MinEntropy:            40,
+	}
+	identifiers := passwordUserIdentifiers("jane.doe@example.com", "+15551234567", map[string]interface{}{
+		"username": "janedoe",
+	})
+
+	examples := []struct {
+		password string
+		reasons  []string
+	}{
+		{
+			password: "qZm7r#xK2!vW",
+		},
+		{
+			password: "qzm7r#xk2!vw",
+			reasons:  []string{WeakPasswordCharacters},
+		},
+		{
+			password: "Jane.Doe#2023",
+			reasons:  []string{WeakPasswordUserIdentifier},
+		},
+		{
+			password: "x!JANEDOE9",
+			reasons:  []string{WeakPasswordUserIdentifier},
+		},
+		{
+			password: "Q#!15551234567%",
+			reasons:  []string{WeakPasswordCharacters, WeakPasswordUserIdentifier},
+		},
+		{
+			password: "Aa1!",
+			reasons:  []string{WeakPasswordEntropy},
+		},
+		{
+			password: "abcdefgh",
+			reasons:  []string{WeakPasswordCharacters, WeakPasswordEntropy},
+		},
+	}
+
+	for _, example := range examples {
+		err := validatePasswordPolicy(policy, example.password, identifiers)
+		if example.reasons == nil {
+			require.NoError(t, err, example.password)
+			continue
+		}
+
+		weakPasswordError, ok := err.(*WeakPasswordError)
+		require.True(t, ok, example.password)
+		require.Equal(t, example.reasons, weakPasswordError.Reasons, example.password)
+	}
+
+	// an empty policy accepts any password
+	require.NoError(t, validatePasswordPolicy(&conf.PasswordPolicyConfiguration{}, "jane", identifiers))
+}
+
+// writeLeakedPasswords writes a leaked passwords directory containing the
+// SHA-1 hash of "password", 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+func writeLeakedPasswords(t *testing.T) string {
+	path := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(path, "5BAA6.txt"), []byte(
+		"003D68EB55068C33ACE09247EE4C639306B:3\r\n"+
+			"1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n"+
+			"1E4C9B93F3F0682250B6CF8331B7EE68FD9:0\r\n",
+	), 0600))
+	return path
+}
+
+func TestIsLeakedPassword(t *testing.T) {
+	path := writeLeakedPasswords(t)
+
+	leaked, err := isLeakedPassword(path, "password")
+	require.NoError(t, err)
+	require.True(t, leaked)
+
+	// no range file exists for the prefix of this hash
+	leaked, err = isLeakedPassword(path, "qZm7r#xK2!vW")
+	require.NoError(t, err)
+	require.False(t, leaked)
+
+	leaked, err = isLeakedPassword(filepath.Join(path, "missing"), "password")
+	require.NoError(t, err)
+	require.False(t, leaked)
+}
+
+func TestValidatePasswordPolicyLeaked(t *testing.T) {
+	policy := &conf.PasswordPolicyConfiguration{
+		LeakedPasswordsPath: writeLeakedPasswords(t),
+	}
+
+	err := validatePasswordPolicy(policy, "password", nil)
+	weakPasswordError, ok := err.(*WeakPasswordError)
+	require.True(t, ok)
+	require.Equal(t, []string{WeakPasswordLeaked}, weakPasswordError.Reasons)
+
+	require.NoError(t, validatePasswordPolicy(policy, "qZm7r#xK2!vW", nil))
+}
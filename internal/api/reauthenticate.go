@@ -1,8 +1,10 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/supabase/gotrue/internal/api/sms_provider"
 	"github.com/supabase/gotrue/internal/conf"
@@ -74,10 +76,147 @@ func (a *API) Reauthenticate(w http.ResponseWriter, r *http.Request) error {
 	return sendJSON(w, http.StatusOK, ret)
 }
 
-// verifyReauthentication checks if the nonce provided is valid
-func (a *API) verifyReauthentication(nonce string, tx *storage.Connection, config *conf.GlobalConfiguration, user *models.User) error {
+// ReauthenticateParams are the parameters of a reauthentication in the
+// current session, with either the password of the user or the nonce sent
+// by Reauthenticate.
+type ReauthenticateParams struct {
+	Password string `json:"password"`
+	Nonce    string `json:"nonce"`
+}
+
+// ReauthenticateVerify reauthenticates the user in the current session and
+// issues new tokens with an updated auth_time. Verifying an MFA factor
+// reauthenticates the user as well.
+func (a *API) ReauthenticateVerify(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	user := getUser(ctx)
+	session := getSession(ctx)
+	if session == nil {
+		return badRequestError("Reauthentication requires a session")
+	}
+
+	params := &ReauthenticateParams{}
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return badRequestError("Could not read body").WithInternalError(err)
+	}
+	if err := json.Unmarshal(body, params); err != nil {
+		return badRequestError("Could not read reauthentication params: %v", err)
+	}
+
+	if (params.Password == "") == (params.Nonce == "") {
+		return badRequestError("Reauthentication requires either a password or a nonce")
+	}
+
+	if params.Password != "" && !user.Authenticate(params.Password) {
+		return a.recordFailedReauthentication(r, user, session, badRequestError(InvalidLoginMessage))
+	}
+
+	var token *AccessTokenResponse
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+		authMethod := models.PasswordGrant
+		if params.Password == "" {
+			if terr = a.verifyReauthentication(r, params.Nonce, tx, config, user, session); terr != nil {
+				return terr
+			}
+			authMethod = models.OTP
+		}
+
+		if terr = models.NewAuditLogEntry(r, tx, user, models.UserReauthenticatedAction, "", map[string]interface{}{
+			"method": authMethod.String(),
+		}); terr != nil {
+			return terr
+		}
+
+		if terr = session.ResetFailedReauthentications(tx); terr != nil {
+			return terr
+		}
+
+		token, terr = a.updateMFASessionAndClaims(r, tx, user, authMethod, models.GrantParams{
+			FactorID: session.FactorID,
+		})
+		return terr
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, token)
+}
+
+// recordFailedReauthentication counts a wrong password or nonce entered to
+// reauthenticate, which the per-IP rate limit alone doesn't stop from being
+// guessed with a stolen session. After too many consecutive failures the
+// session is signed out. It returns err, the error of the failed attempt,
+// unless the session was signed out.
+func (a *API) recordFailedReauthentication(r *http.Request, user *models.User, session *models.Session, err error) error {
+	if session == nil {
+		return err
+	}
+
+	maxAttempts := a.config.Security.ReauthenticationMaxAttempts
+	signedOut := false
+
+	terr := a.db.Transaction(func(tx *storage.Connection) error {
+		attempts, terr := session.RecordFailedReauthentication(tx)
+		if terr != nil {
+			return terr
+		}
+
+		if maxAttempts <= 0 || attempts < maxAttempts {
+			return nil
+		}
+
+		if terr := models.NewAuditLogEntry(r, tx, user, models.LogoutAction, "", map[string]interface{}{
+			"session_id": session.ID,
+			"reason":     "too_many_failed_reauthentications",
+		}); terr != nil {
+			return terr
+		}
+
+		signedOut = true
+		return models.LogoutSession(tx, session.ID)
+	})
+	if terr != nil {
+		return internalServerError("Database error updating session").WithInternalError(terr)
+	}
+
+	if signedOut {
+		return unauthorizedError("Too many failed reauthentication attempts, sign in again")
+	}
+	return err
+}
+
+// requireRecentAuthentication checks that the user actively authenticated
+// in the session within the configured max age before a sensitive change.
+// Otherwise the nonce sent by Reauthenticate has to be provided, and it
+// returns whether it was verified.
+func (a *API) requireRecentAuthentication(r *http.Request, tx *storage.Connection, user *models.User, session *models.Session, nonce string) (bool, error) {
+	config := a.config
+	maxAge := config.Security.ReauthenticationMaxAge
+	if maxAge == 0 || (session != nil && session.IsRecentlyAuthenticated(time.Now(), maxAge)) {
+		return false, nil
+	}
+
+	if nonce == "" {
+		return false, forbiddenError("Reauthentication required")
+	}
+
+	if err := a.verifyReauthentication(r, nonce, tx, config, user, session); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// verifyReauthentication checks if the nonce provided is valid. Wrong or
+// expired nonces count as failed reauthentications in the session.
+func (a *API) verifyReauthentication(r *http.Request, nonce string, tx *storage.Connection, config *conf.GlobalConfiguration, user *models.User, session *models.Session) error {
 	if user.ReauthenticationToken == "" || user.ReauthenticationSentAt == nil {
-		return badRequestError(InvalidNonceMessage)
+		return a.recordFailedReauthentication(r, user, session, badRequestError(InvalidNonceMessage))
 	}
 	var isValid bool
 	if user.GetEmail() != "" {
@@ -87,7 +226,7 @@ func (a *API) verifyReauthentication(nonce string, tx *storage.Connection, confi
 		if config.Sms.IsTwilioVerifyProvider() {
 			smsProvider, _ := sms_provider.GetSmsProvider(*config)
 			if err := smsProvider.(*sms_provider.TwilioVerifyProvider).VerifyOTP(string(user.Phone), nonce); err != nil {
-				return expiredTokenError("Token has expired or is invalid").WithInternalError(err)
+				return a.recordFailedReauthentication(r, user, session, expiredTokenError("Token has expired or is invalid").WithInternalError(err))
 			}
 			return nil
 		} else {
@@ -98,7 +237,7 @@ func (a *API) verifyReauthentication(nonce string, tx *storage.Connection, confi
 		return unprocessableEntityError("Reauthentication requires an email or a phone number")
 	}
 	if !isValid {
-		return badRequestError(InvalidNonceMessage)
+		return a.recordFailedReauthentication(r, user, session, badRequestError(InvalidNonceMessage))
 	}
 	if err := user.ConfirmReauthentication(tx); err != nil {
 		return internalServerError("Error during reauthentication").WithInternalError(err)
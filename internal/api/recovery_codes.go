@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/supabase/gotrue/internal/metering"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// RecoveryCodesResponse is the response of generating recovery codes. The
+// codes are only returned once.
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes"`
+}
+
+// RecoveryCodesStatusResponse is the number of recovery codes a user can
+// still redeem.
+type RecoveryCodesStatusResponse struct {
+	Remaining int `json:"remaining"`
+}
+
+// RedeemRecoveryCodeParams are the parameters the RedeemRecoveryCode method
+// accepts.
+type RedeemRecoveryCodeParams struct {
+	Code string `json:"code"`
+}
+
+// requireVerifiedFactor ensures that the user has a verified factor, as
+// recovery codes replace a factor challenge.
+func (a *API) requireVerifiedFactor(user *models.User) error {
+	factors, err := models.FindFactorsByUser(a.db, user)
+	if err != nil {
+		return internalServerError("Database error finding factors").WithInternalError(err)
+	}
+
+	for _, factor := range factors {
+		if factor.IsVerified() {
+			return nil
+		}
+	}
+	return badRequestError("A verified factor is required to use recovery codes")
+}
+
+// GenerateRecoveryCodes replaces the recovery codes of the user with a new
+// set of codes.
+func (a *API) GenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+	session := getSession(ctx)
+
+	if session == nil || !session.IsAAL2() {
+		return badRequestError("AAL2 required to generate recovery codes")
+	}
+
+	if err := a.requireVerifiedFactor(user); err != nil {
+		return err
+	}
+
+	var codes []string
+	err := a.db.Transaction(func(tx *storage.Connection) error {
+		var terr error
+		if codes, terr = models.GenerateRecoveryCodes(tx, user); terr != nil {
+			return internalServerError("Database error generating recovery codes").WithInternalError(terr)
+		}
+
+		if terr = models.NewAuditLogEntry(r, tx, user, models.GenerateRecoveryCodesAction, r.RemoteAddr, map[string]interface{}{
+			"session_id": session.ID,
+		}); terr != nil {
+			return terr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, &RecoveryCodesResponse{
+		Codes: codes,
+	})
+}
+
+// GetRecoveryCodes returns how many recovery codes the user can still
+// redeem, but not the codes themselves.
+func (a *API) GetRecoveryCodes(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+
+	remaining, err := models.CountUnusedRecoveryCodes(a.db, user)
+	if err != nil {
+		return internalServerError("Database error counting recovery codes").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, &RecoveryCodesStatusResponse{
+		Remaining: remaining,
+	})
+}
+
+// DeleteRecoveryCodes revokes all recovery codes of the user.
+func (a *API) DeleteRecoveryCodes(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+	session := getSession(ctx)
+
+	if session == nil || !session.IsAAL2() {
+		return badRequestError("AAL2 required to delete recovery codes")
+	}
+
+	err := a.db.Transaction(func(tx *storage.Connection) error {
+		if terr := models.DeleteRecoveryCodes(tx, user); terr != nil {
+			return internalServerError("Database error deleting recovery codes").WithInternalError(terr)
+		}
+
+		if terr := models.NewAuditLogEntry(r, tx, user, models.DeleteRecoveryCodesAction, r.RemoteAddr, map[string]interface{}{
+			"session_id": session.ID,
+		}); terr != nil {
+			return terr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+// RedeemRecoveryCode uses a recovery code in place of a factor challenge to
+// upgrade the session to AAL2. Each code can only be redeemed once.
+func (a *API) RedeemRecoveryCode(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+	config := a.config
+
+	params := &RedeemRecoveryCodeParams{}
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Could not read body").WithInternalError(err)
+	}
+
+	if err := json.Unmarshal(body, params); err != nil {
+		return badRequestError("invalid body: unable to parse JSON").WithInternalError(err)
+	}
+
+	if params.Code == "" {
+		return badRequestError("code is required")
+	}
+
+	if err := a.requireVerifiedFactor(user); err != nil {
+		return err
+	}
+
+	if user.IsRecoveryCodesLocked(time.Now()) {
+		return tooManyRequestsError("Too many invalid recovery codes entered, try again later")
+	}
+
+	var token *AccessTokenResponse
+	invalidCode := false
+	err = a.db.Transaction(func(tx *storage.Connection) error {
+		recoveryCode, terr := models.RedeemRecoveryCode(tx, user, params.Code)
+		if terr != nil {
+			if models.IsNotFoundError(terr) {
+				invalidCode = true
+				return badRequestError("Invalid recovery code entered")
+			}
+			return internalServerError("Database error redeeming recovery code").WithInternalError(terr)
+		}
+
+		if terr = models.NewAuditLogEntry(r, tx, user, models.RecoveryCodeRedeemedAction, r.RemoteAddr, map[string]interface{}{
+			"recovery_code_id": recoveryCode.ID,
+		}); terr != nil {
+			return terr
+		}
+
+		if terr = user.ResetFailedRecoveryCodes(tx); terr != nil {
+			return terr
+		}
+
+		if user, terr = models.FindUserByID(tx, user.ID); terr != nil {
+			return terr
+		}
+
+		token, terr = a.updateMFASessionAndClaims(r, tx, user, models.RecoveryCodeSignIn, models.GrantParams{})
+		if terr != nil {
+			return terr
+		}
+
+		if terr = a.setCookieTokens(config, token, false, w); terr != nil {
+			return internalServerError("Failed to set JWT cookie. %s", terr)
+		}
+
+		if terr = models.InvalidateSessionsWithAALLessThan(tx, user.ID, models.AAL2.String()); terr != nil {
+			return internalServerError("Failed to update sessions. %s", terr)
+		}
+		return nil
+	})
+	if err != nil {
+		if invalidCode {
+			return a.recordFailedRecoveryCode(r, user, err)
+		}
+		return err
+	}
+	metering.RecordLogin(string(models.MFACodeLoginAction), user.ID)
+
+	return sendJSON(w, http.StatusOK, token)
+}
+
+// recordFailedRecoveryCode counts an invalid recovery code and locks
+// recovery codes after too many consecutive ones, with the same limits as
+// factor verification. It returns err, the error of the failed attempt.
+func (a *API) recordFailedRecoveryCode(r *http.Request, user *models.User, err error) error {
+	config := a.config
+
+	terr := a.db.Transaction(func(tx *storage.Connection) error {
+		locked, terr := user.RecordFailedRecoveryCode(tx, config.MFA.MaxVerificationAttempts, config.MFA.VerificationLockoutDuration)
+		if terr != nil {
+			return terr
+		}
+
+		if locked {
+			return models.NewAuditLogEntry(r, tx, user, models.RecoveryCodesLockedAction, r.RemoteAddr, map[string]interface{}{
+				"locked_until": user.RecoveryCodesLockedUntil,
+			})
+		}
+		return nil
+	})
+	if terr != nil {
+		return internalServerError("Database error updating user").WithInternalError(terr)
+	}
+
+	return err
+}
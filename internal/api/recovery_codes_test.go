@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+func remainingRecoveryCodes(ts *MFATestSuite, token string) int {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodGet, "/factors/recovery_codes", token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &RecoveryCodesStatusResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data.Remaining
+}
+
+func passwordSignIn(ts *MFATestSuite, email, password string) string {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=password", "", map[string]interface{}{
+		"email":    email,
+		"password": password,
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data.Token
+}
+
+func (ts *MFATestSuite) TestRecoveryCodes() {
+	email := "test1@example.com"
+	password := "test123"
+	aal2Token := signUpAndVerify(ts, email, password).Token
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes", aal2Token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	codes := &RecoveryCodesResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(codes))
+	require.Len(ts.T(), codes.Codes, models.RecoveryCodeCount)
+
+	aal1Token := passwordSignIn(ts, email, password)
+	require.Equal(ts.T(), models.RecoveryCodeCount, remainingRecoveryCodes(ts, aal1Token))
+
+	// generating the codes again requires AAL2
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes", aal1Token, nil)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", aal1Token, map[string]interface{}{
+		"code": "AAAA-AAAA-AAAA-AAAA",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	// codes are accepted without separators and in lower case
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", aal1Token, map[string]interface{}{
+		"code": strings.ToLower(strings.ReplaceAll(codes.Codes[0], "-", "")),
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+
+	ctx, err := ts.API.parseJWTClaims(data.Token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	claims := getClaims(ctx)
+	require.Equal(ts.T(), models.AAL2.String(), claims.AuthenticatorAssuranceLevel)
+	require.Equal(ts.T(), models.RecoveryCodeSignIn.String(), claims.AuthenticationMethodReference[0].Method)
+
+	// each code can only be redeemed once
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", passwordSignIn(ts, email, password), map[string]interface{}{
+		"code": codes.Codes[0],
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+	require.Equal(ts.T(), models.RecoveryCodeCount-1, remainingRecoveryCodes(ts, data.Token))
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodDelete, "/factors/recovery_codes", data.Token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+	require.Equal(ts.T(), 0, remainingRecoveryCodes(ts, data.Token))
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", aal1Token, map[string]interface{}{
+		"code": codes.Codes[1],
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *MFATestSuite) TestUnenrollLastFactorRevokesRecoveryCodes() {
+	email := "test1@example.com"
+	password := "test123"
+	aal2Token := signUpAndVerify(ts, email, password).Token
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes", aal2Token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	codes := &RecoveryCodesResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(codes))
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", passwordSignIn(ts, email, password), map[string]interface{}{
+		"code": codes.Codes[0],
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+
+	user, err := models.FindUserByEmailAndAudience(ts.API.db, email, ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+	factors, err := models.FindFactorsByUser(ts.API.db, user)
+	require.NoError(ts.T(), err)
+	require.Len(ts.T(), factors, 1)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodDelete, "/factors/"+factors[0].ID.String(), data.Token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	// the session verified with the recovery code is downgraded too
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=refresh_token", "", map[string]interface{}{
+		"refresh_token": data.RefreshToken,
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	refreshed := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(refreshed))
+
+	ctx, err := ts.API.parseJWTClaims(refreshed.Token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), models.AAL1.String(), getClaims(ctx).AuthenticatorAssuranceLevel)
+
+	count, err := models.CountUnusedRecoveryCodes(ts.API.db, user)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 0, count)
+}
+
+func (ts *MFATestSuite) TestRecoveryCodesRequireVerifiedFactor() {
+	token := signUp(ts, "test1@example.com", "test123").Token
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", token, map[string]interface{}{
+		"code": "AAAA-AAAA-AAAA-AAAA",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *MFATestSuite) TestRecoveryCodesLockout() {
+	maxAttempts := ts.Config.MFA.MaxVerificationAttempts
+	ts.Config.MFA.MaxVerificationAttempts = 2
+	defer func() {
+		ts.Config.MFA.MaxVerificationAttempts = maxAttempts
+	}()
+
+	email := "test1@example.com"
+	password := "test123"
+	aal2Token := signUpAndVerify(ts, email, password).Token
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes", aal2Token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	codes := &RecoveryCodesResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(codes))
+
+	aal1Token := passwordSignIn(ts, email, password)
+	for i := 0; i < 2; i++ {
+		w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", aal1Token, map[string]interface{}{
+			"code": "AAAA-AAAA-AAAA-AAAA",
+		})
+		require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+	}
+
+	// even a valid code is rejected while recovery codes are locked
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", aal1Token, map[string]interface{}{
+		"code": codes.Codes[0],
+	})
+	require.Equal(ts.T(), http.StatusTooManyRequests, w.Code)
+
+	user, err := models.FindUserByEmailAndAudience(ts.API.db, email, ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), user.IsRecoveryCodesLocked(time.Now()))
+
+	// the codes can be used again once the lockout has passed
+	require.NoError(ts.T(), ts.API.db.RawQuery("UPDATE auth.users SET recovery_codes_locked_until = ? WHERE id = ?", time.Now().Add(-time.Minute), user.ID).Exec())
+
+	otherToken := passwordSignIn(ts, email, password)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/factors/recovery_codes/redeem", aal1Token, map[string]interface{}{
+		"code": codes.Codes[0],
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	// like verifying a factor, redeeming a code signs out the other
+	// sessions of the user below AAL2
+	ctx, err := ts.API.parseJWTClaims(otherToken, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	sessionID, err := uuid.FromString(getClaims(ctx).SessionId)
+	require.NoError(ts.T(), err)
+
+	_, err = models.FindSessionByID(ts.API.db, sessionID, false)
+	require.True(ts.T(), models.IsNotFoundError(err))
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/observability"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// ServiceAccountParams are the parameters used to create or update a
+// service account.
+type ServiceAccountParams struct {
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ServiceAccountResponse is a service account, including its secret when it
+// has just been generated.
+type ServiceAccountResponse struct {
+	*models.ServiceAccount
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+func (a *API) validateServiceAccountParams(p *ServiceAccountParams, forUpdate bool) error {
+	if !forUpdate && p.Name == "" {
+		return badRequestError("name is required")
+	}
+
+	if !forUpdate && p.Role == "" {
+		return badRequestError("role is required")
+	}
+
+	if isStringInSlice(p.Role, a.config.JWT.AdminRoles) {
+		return badRequestError("Service accounts cannot have an administrative role")
+	}
+
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return badRequestError("expires_at must be in the future")
+	}
+
+	return nil
+}
+
+// loadServiceAccount looks for a service_account_id parameter in the URL
+// route and loads the service account with that ID into the context.
+func (a *API) loadServiceAccount(w http.ResponseWriter, r *http.Request) (context.Context, error) {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	accountID, err := uuid.FromString(chi.URLParam(r, "service_account_id"))
+	if err != nil {
+		return nil, notFoundError("Service account not found")
+	}
+
+	account, err := models.FindServiceAccountByID(db, accountID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, notFoundError("Service account not found")
+		}
+		return nil, internalServerError("Database error finding service account").WithInternalError(err)
+	}
+
+	observability.LogEntrySetField(r, "service_account_id", account.ID.String())
+
+	return withServiceAccount(ctx, account), nil
+}
+
+// adminServiceAccountsList lists all service accounts.
+func (a *API) adminServiceAccountsList(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	accounts, err := models.FindAllServiceAccounts(db)
+	if err != nil {
+		return internalServerError("Database error finding service accounts").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, map[string]interface{}{
+		"items": accounts,
+	})
+}
+
+// adminServiceAccountsCreate creates a new service account. The secret is
+// only ever returned in this response.
+func (a *API) adminServiceAccountsCreate(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	adminUser := getAdminUser(ctx)
+
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Unable to read request body").WithInternalError(err)
+	}
+
+	var params ServiceAccountParams
+	if err := json.Unmarshal(body, &params); err != nil {
+		return badRequestError("Unable to parse JSON").WithInternalError(err)
+	}
+
+	if err := a.validateServiceAccountParams(&params, false /* <- forUpdate */); err != nil {
+		return err
+	}
+
+	account, secret := models.NewServiceAccount(params.Name, params.Role, params.Scopes, params.ExpiresAt)
+
+	if err := db.Transaction(func(tx *storage.Connection) error {
+		if terr := tx.Create(account); terr != nil {
+			return terr
+		}
+
+		return models.NewAuditLogEntry(r, tx, adminUser, models.ServiceAccountCreatedAction, "", map[string]interface{}{
+			"service_account_id":   account.ID,
+			"service_account_name": account.Name,
+			"role":                 account.Role,
+		})
+	}); err != nil {
+		return internalServerError("Database error creating service account").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusCreated, &ServiceAccountResponse{
+		ServiceAccount: account,
+		ClientSecret:   secret,
+	})
+}
+
+// adminServiceAccountsGet returns a service account.
+func (a *API) adminServiceAccountsGet(w http.ResponseWriter, r *http.Request) error {
+	account := getServiceAccount(r.Context())
+
+	return sendJSON(w, http.StatusOK, account)
+}
+
+// adminServiceAccountsUpdate updates the name, role, scopes or expiry of a
+// service account. Tokens that were already issued are not affected.
+func (a *API) adminServiceAccountsUpdate(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	adminUser := getAdminUser(ctx)
+	account := getServiceAccount(ctx)
+
+	body, err := getBodyBytes(r)
+	if err != nil {
+		return internalServerError("Unable to read request body").WithInternalError(err)
+	}
+
+	var params ServiceAccountParams
+	if err := json.Unmarshal(body, &params); err != nil {
+		return badRequestError("Unable to parse JSON").WithInternalError(err)
+	}
+
+	if err := a.validateServiceAccountParams(&params, true /* <- forUpdate */); err != nil {
+		return err
+	}
+
+	if params.Name != "" {
+		account.Name = params.Name
+	}
+
+	if params.Role != "" {
+		account.Role = params.Role
+	}
+
+	if params.Scopes != nil {
+		account.Scopes = params.Scopes
+	}
+
+	if params.ExpiresAt != nil {
+		account.ExpiresAt = params.ExpiresAt
+	}
+
+	if err := db.Transaction(func(tx *storage.Connection) error {
+		if terr := tx.UpdateOnly(account, "name", "role", "scopes", "expires_at", "updated_at"); terr != nil {
+			return terr
+		}
+
+		return models.NewAuditLogEntry(r, tx, adminUser, models.ServiceAccountModifiedAction, "", map[string]interface{}{
+			"service_account_id": account.ID,
+		})
+	}); err != nil {
+		return internalServerError("Database error updating service account").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, account)
+}
+
+// adminServiceAccountsRegenerateSecret issues a new secret to a service
+// account, invalidating the previous one.
+func (a *API) adminServiceAccountsRegenerateSecret(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	adminUser := getAdminUser(ctx)
+	account := getServiceAccount(ctx)
+
+	secret := account.GenerateSecret()
+
+	if err := db.Transaction(func(tx *storage.Connection) error {
+		if terr := tx.UpdateOnly(account, "client_secret_hash", "updated_at"); terr != nil {
+			return terr
+		}
+
+		return models.NewAuditLogEntry(r, tx, adminUser, models.ServiceAccountModifiedAction, "", map[string]interface{}{
+			"service_account_id": account.ID,
+			"secret_regenerated": true,
+		})
+	}); err != nil {
+		return internalServerError("Database error updating service account").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, &ServiceAccountResponse{
+		ServiceAccount: account,
+		ClientSecret:   secret,
+	})
+}
+
+// adminServiceAccountsDelete deletes a service account. Tokens that were
+// already issued remain valid until they expire.
+func (a *API) adminServiceAccountsDelete(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	adminUser := getAdminUser(ctx)
+	account := getServiceAccount(ctx)
+
+	if err := db.Transaction(func(tx *storage.Connection) error {
+		if terr := tx.Destroy(account); terr != nil {
+			return terr
+		}
+
+		return models.NewAuditLogEntry(r, tx, adminUser, models.ServiceAccountDeletedAction, "", map[string]interface{}{
+			"service_account_id":   account.ID,
+			"service_account_name": account.Name,
+		})
+	}); err != nil {
+		return internalServerError("Database error deleting service account").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, account)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// SessionResponse describes a session so that users can recognize the
+// devices they are signed in on.
+type SessionResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	RefreshedAt   *time.Time `json:"refreshed_at,omitempty"`
+	NotAfter      *time.Time `json:"not_after,omitempty"`
+	UserAgent     string     `json:"user_agent,omitempty"`
+	IP            string     `json:"ip,omitempty"`
+	AAL           string     `json:"aal"`
+	OAuthClientID *uuid.UUID `json:"oauth_client_id,omitempty"`
+	Current       bool       `json:"current"`
+}
+
+// SessionsResponse lists the sessions of a user.
+type SessionsResponse struct {
+	Sessions []*SessionResponse `json:"sessions"`
+}
+
+func newSessionResponse(session *models.Session, current *models.Session) *SessionResponse {
+	response := &SessionResponse{
+		ID:            session.ID,
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
+		RefreshedAt:   session.RefreshedAt,
+		NotAfter:      session.NotAfter,
+		AAL:           session.GetAAL(),
+		OAuthClientID: session.OAuthClientID,
+		Current:       current != nil && current.ID == session.ID,
+	}
+
+	if session.UserAgent != nil {
+		response.UserAgent = *session.UserAgent
+	}
+
+	if session.IP != nil {
+		response.IP = *session.IP
+	}
+
+	return response
+}
+
+func newSessionsResponse(sessions []*models.Session, current *models.Session) *SessionsResponse {
+	response := &SessionsResponse{
+		Sessions: make([]*SessionResponse, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, newSessionResponse(session, current))
+	}
+
+	return response
+}
+
+// UserSessionsList returns the sessions of the current user.
+func (a *API) UserSessionsList(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	user := getUser(ctx)
+
+	sessions, err := models.FindAllSessionsByUserID(db, user.ID)
+	if err != nil {
+		return internalServerError("Database error finding sessions").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, newSessionsResponse(sessions, getSession(ctx)))
+}
+
+// UserSessionDelete signs the current user out of one of their sessions,
+// e.g. the session of a lost device.
+func (a *API) UserSessionDelete(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+	config := a.config
+	user := getUser(ctx)
+
+	sessionID, err := uuid.FromString(chi.URLParam(r, "session_id"))
+	if err != nil {
+		return badRequestError("session_id must be an UUID")
+	}
+
+	session, err := models.FindSessionByID(db, sessionID, false)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return notFoundError("Session not found")
+		}
+		return internalServerError("Database error finding session").WithInternalError(err)
+	}
+
+	if session.UserID != user.ID {
+		return notFoundError("Session not found")
+	}
+
+	err = db.Transaction(func(tx *storage.Connection) error {
+		if terr := models.NewAuditLogEntry(r, tx, user, models.LogoutAction, "", map[string]interface{}{
+			"session_id": session.ID,
+		}); terr != nil {
+			return terr
+		}
+
+		return models.LogoutSession(tx, session.ID)
+	})
+	if err != nil {
+		return internalServerError("Error deleting session").WithInternalError(err)
+	}
+
+	if current := getSession(ctx); current != nil && current.ID == session.ID {
+		a.clearCookieTokens(config, w)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+type SessionsTestSuite struct {
+	suite.Suite
+	API    *API
+	Config *conf.GlobalConfiguration
+}
+
+func TestSessions(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	ts := &SessionsTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *SessionsTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	for _, email := range []string{"test@example.com", "other@example.com"} {
+		u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", email, "password", ts.Config.JWT.Aud, nil)
+		require.NoError(ts.T(), err, "Error creating test user model")
+		now := time.Now()
+		u.EmailConfirmedAt = &now
+		require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+	}
+}
+
+func (ts *SessionsTestSuite) signIn(email, userAgent string) *AccessTokenResponse {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    email,
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *SessionsTestSuite) listSessions(token string) *SessionsResponse {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/user/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &SessionsResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *SessionsTestSuite) deleteSession(token, sessionID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodDelete, "http://localhost/user/sessions/"+sessionID, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *SessionsTestSuite) TestListSessions() {
+	laptop := ts.signIn("test@example.com", "laptop")
+	ts.signIn("test@example.com", "phone")
+	ts.signIn("other@example.com", "other")
+
+	data := ts.listSessions(laptop.Token)
+	require.Len(ts.T(), data.Sessions, 2)
+
+	userAgents := map[string]bool{}
+	for _, session := range data.Sessions {
+		userAgents[session.UserAgent] = session.Current
+		require.NotEmpty(ts.T(), session.IP)
+		require.Nil(ts.T(), session.RefreshedAt)
+	}
+	require.Equal(ts.T(), map[string]bool{"laptop": true, "phone": false}, userAgents)
+}
+
+func (ts *SessionsTestSuite) TestRefreshUpdatesSession() {
+	token := ts.signIn("test@example.com", "laptop")
+
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"refresh_token": token.RefreshToken,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=refresh_token", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "laptop/2.0")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := ts.listSessions(token.Token)
+	require.Len(ts.T(), data.Sessions, 1)
+	require.Equal(ts.T(), "laptop/2.0", data.Sessions[0].UserAgent)
+	require.NotNil(ts.T(), data.Sessions[0].RefreshedAt)
+}
+
+func (ts *SessionsTestSuite) TestDeleteSession() {
+	laptop := ts.signIn("test@example.com", "laptop")
+	ts.signIn("test@example.com", "phone")
+	other := ts.signIn("other@example.com", "other")
+
+	var phoneSessionID string
+	for _, session := range ts.listSessions(laptop.Token).Sessions {
+		if session.UserAgent == "phone" {
+			phoneSessionID = session.ID.String()
+		}
+	}
+	require.NotEmpty(ts.T(), phoneSessionID)
+
+	// users cannot delete the sessions of other users
+	w := ts.deleteSession(other.Token, phoneSessionID)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+
+	w = ts.deleteSession(laptop.Token, phoneSessionID)
+	require.Equal(ts.T(), http.StatusNoContent, w.Code)
+
+	data := ts.listSessions(laptop.Token)
+	require.Len(ts.T(), data.Sessions, 1)
+	require.Equal(ts.T(), "laptop", data.Sessions[0].UserAgent)
+
+	w = ts.deleteSession(laptop.Token, "not-a-uuid")
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
@@ -28,6 +28,16 @@ type ProviderSettings struct {
 	Zoom         bool `json:"zoom"`
 }
 
+// PasswordPolicySettings describe the password policy, so that clients can
+// check passwords before submitting them.
+type PasswordPolicySettings struct {
+	MinLength             int      `json:"min_length"`
+	RequiredCharacters    []string `json:"required_characters"`
+	RejectUserIdentifiers bool     `json:"reject_user_identifiers"`
+	MinEntropy            float64  `json:"min_entropy"`
+	RejectLeaked          bool     `json:"reject_leaked"`
+}
+
 type Settings struct {
 	ExternalProviders ProviderSettings `json:"external"`
 	DisableSignup     bool             `json:"disable_signup"`
@@ -36,11 +46,18 @@ type Settings struct {
 	SmsProvider       string           `json:"sms_provider"`
 	MFAEnabled        bool             `json:"mfa_enabled"`
 	SAMLEnabled       bool             `json:"saml_enabled"`
+
+	PasswordPolicy PasswordPolicySettings `json:"password_policy"`
 }
 
 func (a *API) Settings(w http.ResponseWriter, r *http.Request) error {
 	config := a.config
 
+	requiredCharacters := config.PasswordPolicy.RequiredCharacters
+	if requiredCharacters == nil {
+		requiredCharacters = []string{}
+	}
+
 	return sendJSON(w, http.StatusOK, &Settings{
 		ExternalProviders: ProviderSettings{
 			Apple:        config.External.Apple.Enabled,
@@ -74,5 +91,13 @@ func (a *API) Settings(w http.ResponseWriter, r *http.Request) error {
 		SmsProvider:       config.Sms.Provider,
 		MFAEnabled:        config.MFA.Enabled,
 		SAMLEnabled:       config.SAML.Enabled,
+
+		PasswordPolicy: PasswordPolicySettings{
+			MinLength:             config.PasswordMinLength,
+			RequiredCharacters:    requiredCharacters,
+			RejectUserIdentifiers: config.PasswordPolicy.RejectUserIdentifiers,
+			MinEntropy:            config.PasswordPolicy.MinEntropy,
+			RejectLeaked:          config.PasswordPolicy.LeakedPasswordsPath != "",
+		},
 	})
 }
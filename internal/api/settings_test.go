@@ -68,3 +68,25 @@ func TestSettings_EmailDisabled(t *testing.T) {
 	p := resp.ExternalProviders
 	require.False(t, p.Email)
 }
+
+func TestSettings_PasswordPolicy(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.PasswordPolicy.RequiredCharacters = []string{"lowercase", "digits"}
+	config.PasswordPolicy.MinEntropy = 40
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/settings", nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	api.handler.ServeHTTP(w, req)
+	require.Equal(t, w.Code, http.StatusOK)
+	resp := Settings{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+
+	require.Equal(t, config.PasswordMinLength, resp.PasswordPolicy.MinLength)
+	require.Equal(t, []string{"lowercase", "digits"}, resp.PasswordPolicy.RequiredCharacters)
+	require.False(t, resp.PasswordPolicy.RejectUserIdentifiers)
+	require.Equal(t, 40.0, resp.PasswordPolicy.MinEntropy)
+}
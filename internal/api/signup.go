@@ -12,6 +12,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/supabase/gotrue/internal/api/provider"
 	"github.com/supabase/gotrue/internal/api/sms_provider"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/metering"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/storage"
@@ -31,17 +33,20 @@ type SignupParams struct {
 	CodeChallenge       string                 `json:"code_challenge"`
 }
 
-func (p *SignupParams) Validate(passwordMinLength int, smsProvider string) error {
+func (p *SignupParams) Validate(config *conf.GlobalConfiguration) error {
 	if p.Password == "" {
 		return unprocessableEntityError("Signup requires a valid password")
 	}
-	if len(p.Password) < passwordMinLength {
-		return invalidPasswordLengthError(passwordMinLength)
+	if len(p.Password) < config.PasswordMinLength {
+		return invalidPasswordLengthError(config.PasswordMinLength)
 	}
 	if p.Email != "" && p.Phone != "" {
 		return unprocessableEntityError("Only an email address or phone number should be provided on signup.")
 	}
-	if p.Provider == "phone" && !sms_provider.IsValidMessageChannel(p.Channel, smsProvider) {
+	if err := validatePasswordPolicy(&config.PasswordPolicy, p.Password, passwordUserIdentifiers(p.Email, p.Phone, p.Data)); err != nil {
+		return err
+	}
+	if p.Provider == "phone" && !sms_provider.IsValidMessageChannel(p.Channel, config.Sms.Provider) {
 		return badRequestError(InvalidChannelError)
 	}
 	// PKCE not needed as phone signups already return access token in body
@@ -93,7 +98,7 @@ func (a *API) Signup(w http.ResponseWriter, r *http.Request) error {
 		return badRequestError("Could not read Signup params: %v", err)
 	}
 	params.ConfigureDefaults()
-	if err := params.Validate(config.PasswordMinLength, config.Sms.Provider); err != nil {
+	if err := params.Validate(config); err != nil {
 		return err
 	}
 
@@ -270,6 +275,7 @@ func (a *API) Signup(w http.ResponseWriter, r *http.Request) error {
 			if terr = triggerEventHooks(ctx, tx, LoginEvent, user, config); terr != nil {
 				return terr
 			}
+			grantParams.FillGrantParams(r)
 			token, terr = a.issueRefreshToken(ctx, tx, user, models.PasswordGrant, grantParams)
 
 			if terr != nil {
@@ -334,12 +340,12 @@ func (a *API) signupNewUser(ctx context.Context, conn *storage.Connection, param
 	var err error
 	switch params.Provider {
 	case "email":
-		user, err = models.NewUser("", params.Email, params.Password, params.Aud, params.Data)
+		user, err = models.NewUser(crypto.NewPasswordHashParams(&config.PasswordHash), "", params.Email, params.Password, params.Aud, params.Data)
 	case "phone":
-		user, err = models.NewUser(params.Phone, "", params.Password, params.Aud, params.Data)
+		user, err = models.NewUser(crypto.NewPasswordHashParams(&config.PasswordHash), params.Phone, "", params.Password, params.Aud, params.Data)
 	default:
 		// handles external provider case
-		user, err = models.NewUser("", params.Email, params.Password, params.Aud, params.Data)
+		user, err = models.NewUser(crypto.NewPasswordHashParams(&config.PasswordHash), "", params.Email, params.Password, params.Aud, params.Data)
 	}
 	if err != nil {
 		return nil, internalServerError("Database error creating user").WithInternalError(err)
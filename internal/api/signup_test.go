@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 )
 
@@ -225,7 +226,7 @@ func (ts *SignupTestSuite) TestSignupTwice() {
 }
 
 func (ts *SignupTestSuite) TestVerifySignup() {
-	user, err := models.NewUser("123456789", "test@example.com", "testing", ts.Config.JWT.Aud, nil)
+	user, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test@example.com", "testing", ts.Config.JWT.Aud, nil)
 	user.ConfirmationToken = "asdf3"
 	now := time.Now()
 	user.ConfirmationSentAt = &now
@@ -253,3 +254,42 @@ func (ts *SignupTestSuite) TestVerifySignup() {
 	require.NotEmpty(ts.T(), v.Get("expires_in"))
 	require.NotEmpty(ts.T(), v.Get("refresh_token"))
 }
+
+func (ts *SignupTestSuite) TestSignupWeakPassword() {
+	passwordPolicy := ts.Config.PasswordPolicy
+	ts.Config.PasswordPolicy = conf.PasswordPolicyConfiguration{
+		RequiredCharacters:    []string{"lowercase", "uppercase", "digits"},
+		RejectUserIdentifiers: true,
+	}
+	defer func() {
+		ts.Config.PasswordPolicy = passwordPolicy
+	}()
+
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "test123",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/signup", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusUnprocessableEntity, w.Code)
+
+	data := struct {
+		Code         int    `json:"code"`
+		Message      string `json:"msg"`
+		WeakPassword struct {
+			Reasons []string `json:"reasons"`
+		} `json:"weak_password"`
+	}{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), http.StatusUnprocessableEntity, data.Code)
+	require.NotEmpty(ts.T(), data.Message)
+	require.Equal(ts.T(), []string{WeakPasswordCharacters, WeakPasswordUserIdentifier}, data.WeakPassword.Reasons)
+
+	_, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.True(ts.T(), models.IsNotFoundError(err))
+}
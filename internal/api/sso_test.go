@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	jwt "github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -476,6 +477,13 @@ func (ts *SSOTestSuite) TestAdminUpdateSSOProvider() {
 				},
 			},
 		},
+		{
+			ID:     providers[1].ID,
+			Status: http.StatusOK,
+			Request: map[string]interface{}{
+				"mfa_required": true,
+			},
+		},
 		{
 			ID:     providers[1].ID,
 			Status: http.StatusOK,
@@ -503,6 +511,10 @@ func (ts *SSOTestSuite) TestAdminUpdateSSOProvider() {
 
 		require.Equal(ts.T(), w.Code, example.Status)
 	}
+
+	provider, err := models.FindSSOProviderByID(ts.API.db, uuid.Must(uuid.FromString(providers[1].ID)))
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), provider.MFARequired)
 }
 
 func (ts *SSOTestSuite) TestAdminDeleteSSOProvider() {
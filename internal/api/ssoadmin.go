@@ -75,6 +75,7 @@ type CreateSSOProviderParams struct {
 	MetadataXML      string                      `json:"metadata_xml"`
 	Domains          []string                    `json:"domains"`
 	AttributeMapping models.SAMLAttributeMapping `json:"attribute_mapping"`
+	MFARequired      *bool                       `json:"mfa_required"`
 }
 
 func (p *CreateSSOProviderParams) validate(forUpdate bool) error {
@@ -225,6 +226,10 @@ func (a *API) adminSSOProvidersCreate(w http.ResponseWriter, r *http.Request) er
 
 	provider.SAMLProvider.AttributeMapping = params.AttributeMapping
 
+	if params.MFARequired != nil {
+		provider.MFARequired = *params.MFARequired
+	}
+
 	for _, domain := range params.Domains {
 		existingProvider, err := models.FindSSOProviderByDomain(db, domain)
 		if err != nil && !models.IsNotFoundError(err) {
@@ -340,6 +345,11 @@ func (a *API) adminSSOProvidersUpdate(w http.ResponseWriter, r *http.Request) er
 		}
 	}
 
+	if params.MFARequired != nil && *params.MFARequired != provider.MFARequired {
+		modified = true
+		provider.MFARequired = *params.MFARequired
+	}
+
 	updateAttributeMapping := !provider.SAMLProvider.AttributeMapping.Equal(&params.AttributeMapping)
 	if updateAttributeMapping {
 		modified = true
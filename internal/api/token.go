@@ -13,8 +13,10 @@ import (
 	"github.com/golang-jwt/jwt"
 
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/metering"
 	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/observability"
 	"github.com/supabase/gotrue/internal/storage"
 )
 
@@ -28,7 +30,11 @@ type GoTrueClaims struct {
 	Role                          string                 `json:"role"`
 	AuthenticatorAssuranceLevel   string                 `json:"aal,omitempty"`
 	AuthenticationMethodReference []models.AMREntry      `json:"amr,omitempty"`
+	AuthTime                      int64                  `json:"auth_time,omitempty"`
 	SessionId                     string                 `json:"session_id,omitempty"`
+	ClientID                      string                 `json:"client_id,omitempty"`
+	Scope                         string                 `json:"scope,omitempty"`
+	MFAPolicy                     *MFAPolicyClaims       `json:"mfa_policy,omitempty"`
 }
 
 // AccessTokenResponse represents an OAuth2 success response
@@ -41,6 +47,12 @@ type AccessTokenResponse struct {
 	User                 *models.User `json:"user"`
 	ProviderAccessToken  string       `json:"provider_token,omitempty"`
 	ProviderRefreshToken string       `json:"provider_refresh_token,omitempty"`
+	IDToken              string       `json:"id_token,omitempty"`
+	TrustedDeviceToken   string       `json:"trusted_device_token,omitempty"`
+
+	// WeakPassword is set when the user signed in with a password that
+	// doesn't satisfy the password policy anymore.
+	WeakPassword *WeakPasswordError `json:"weak_password,omitempty"`
 }
 
 // AsRedirectURL encodes the AccessTokenResponse as a redirect URL that
@@ -60,12 +72,17 @@ type PasswordGrantParams struct {
 	Email    string `json:"email"`
 	Phone    string `json:"phone"`
 	Password string `json:"password"`
+
+	// TrustedDeviceToken is the device token of a trusted device, which
+	// signs the user in at aal2.
+	TrustedDeviceToken string `json:"trusted_device_token"`
 }
 
 // PKCEGrantParams are the parameters the PKCEGrant method accepts
 type PKCEGrantParams struct {
-	AuthCode     string `json:"auth_code"`
-	CodeVerifier string `json:"code_verifier"`
+	AuthCode           string `json:"auth_code"`
+	CodeVerifier       string `json:"code_verifier"`
+	TrustedDeviceToken string `json:"trusted_device_token"`
 }
 
 const useCookieHeader = "x-use-cookie"
@@ -84,6 +101,14 @@ func (a *API) Token(w http.ResponseWriter, r *http.Request) error {
 		return a.IdTokenGrant(ctx, w, r)
 	case "pkce":
 		return a.PKCE(ctx, w, r)
+	case "authorization_code":
+		return a.AuthorizationCodeGrant(ctx, w, r)
+	case "client_credentials":
+		return a.ClientCredentialsGrant(ctx, w, r)
+	case DeviceCodeGrantType:
+		return a.DeviceCodeGrant(ctx, w, r)
+	case "passkey":
+		return a.PasskeyGrant(ctx, w, r)
 	default:
 		return oauthError("unsupported_grant_type", "")
 	}
@@ -147,6 +172,20 @@ func (a *API) ResourceOwnerPasswordGrant(ctx context.Context, w http.ResponseWri
 		return oauthError("invalid_grant", "Phone not confirmed")
 	}
 
+	var weakPassword *WeakPasswordError
+	if config.PasswordPolicy.FlagLeakedPasswordsOnLogin {
+		leaked, err := isLeakedPassword(config.PasswordPolicy.LeakedPasswordsPath, params.Password)
+		if err != nil {
+			// the check is advisory, so it shouldn't prevent signing in
+			observability.GetLogEntry(r).WithError(err).Warn("Error checking leaked passwords")
+		} else if leaked {
+			weakPassword = &WeakPasswordError{
+				Message: leakedPasswordMessage,
+				Reasons: []string{WeakPasswordLeaked},
+			}
+		}
+	}
+
 	var token *AccessTokenResponse
 	err = db.Transaction(func(tx *storage.Connection) error {
 		var terr error
@@ -158,6 +197,13 @@ func (a *API) ResourceOwnerPasswordGrant(ctx context.Context, w http.ResponseWri
 		if terr = triggerEventHooks(ctx, tx, LoginEvent, user, config); terr != nil {
 			return terr
 		}
+		if terr = user.RehashPassword(tx, crypto.NewPasswordHashParams(&config.PasswordHash), params.Password); terr != nil {
+			return internalServerError("Error during password storage").WithInternalError(terr)
+		}
+		grantParams.FillGrantParams(r)
+		if terr = a.trustedDeviceGrant(tx, user, params.TrustedDeviceToken, &grantParams); terr != nil {
+			return terr
+		}
 		token, terr = a.issueRefreshToken(ctx, tx, user, models.PasswordGrant, grantParams)
 
 		if terr != nil {
@@ -172,6 +218,7 @@ func (a *API) ResourceOwnerPasswordGrant(ctx context.Context, w http.ResponseWri
 	if err != nil {
 		return err
 	}
+	token.WeakPassword = weakPassword
 	metering.RecordLogin("password", user.ID)
 	return sendJSON(w, http.StatusOK, token)
 }
@@ -196,7 +243,7 @@ func (a *API) PKCE(ctx context.Context, w http.ResponseWriter, r *http.Request)
 
 	flowState, err := models.FindFlowStateByAuthCode(db, params.AuthCode)
 	// Sanity check in case user ID was not set properly
-	if models.IsNotFoundError(err) || flowState.UserID == nil {
+	if models.IsNotFoundError(err) || flowState.UserID == nil || flowState.IsOAuthAuthorization() {
 		return forbiddenError("invalid flow state, no valid flow state found")
 	} else if err != nil {
 		return err
@@ -225,6 +272,10 @@ func (a *API) PKCE(ctx context.Context, w http.ResponseWriter, r *http.Request)
 		}); terr != nil {
 			return terr
 		}
+		grantParams.FillGrantParams(r)
+		if terr = a.trustedDeviceGrant(tx, user, params.TrustedDeviceToken, &grantParams); terr != nil {
+			return terr
+		}
 		token, terr = a.issueRefreshToken(ctx, tx, user, authMethod, grantParams)
 		if terr != nil {
 			return oauthError("server_error", terr.Error())
@@ -248,25 +299,67 @@ func (a *API) PKCE(ctx context.Context, w http.ResponseWriter, r *http.Request)
 
 }
 
-func generateAccessToken(tx *storage.Connection, user *models.User, sessionId *uuid.UUID, config *conf.JWTConfiguration) (string, int64, error) {
+// generateAccessToken builds and signs an access token for the user and
+// session, calling the custom access token hook first if it is configured.
+func (a *API) generateAccessToken(tx *storage.Connection, user *models.User, sessionId *uuid.UUID) (string, int64, error) {
+	config := a.config
+
+	claims, session, err := accessTokenClaims(tx, user, sessionId, &config.JWT)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if claims.MFAPolicy, err = a.mfaPolicyClaims(tx, user); err != nil {
+		return "", 0, err
+	}
+
+	var signed string
+	if config.Hook.CustomAccessToken.IsEnabled() {
+		customClaims, terr := triggerCustomAccessTokenHook(&config.Hook.CustomAccessToken, config.JWT.AdminRoles, user, session, claims)
+		if terr != nil {
+			return "", 0, terr
+		}
+
+		signed, err = signJWT(customClaims, &config.JWT)
+	} else {
+		signed, err = signJWT(claims, &config.JWT)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	return signed, claims.ExpiresAt, nil
+}
+
+// accessTokenClaims builds the claims of an access token for the user and
+// session. The session is returned if there is one.
+func accessTokenClaims(tx *storage.Connection, user *models.User, sessionId *uuid.UUID, config *conf.JWTConfiguration) (*GoTrueClaims, *models.Session, error) {
 	aal, amr := models.AAL1.String(), []models.AMREntry{}
-	sid := ""
+	sid, clientID, scope := "", "", ""
+	var authTime int64
+	var session *models.Session
 	if sessionId != nil {
 		sid = sessionId.String()
-		session, terr := models.FindSessionByID(tx, *sessionId, false)
+		var terr error
+		session, terr = models.FindSessionByID(tx, *sessionId, false)
 		if terr != nil {
-			return "", 0, terr
+			return nil, nil, terr
 		}
 		aal, amr, terr = session.CalculateAALAndAMR(tx)
 		if terr != nil {
-			return "", 0, terr
+			return nil, nil, terr
+		}
+		authTime = session.GetAuthTime().Unix()
+		if session.OAuthClientID != nil {
+			clientID = session.OAuthClientID.String()
+			scope = session.Scopes.String()
 		}
 	}
 
 	issuedAt := time.Now().UTC()
 	expiresAt := issuedAt.Add(time.Second * time.Duration(config.Exp)).Unix()
 
-	claims := &GoTrueClaims{
+	return &GoTrueClaims{
 		StandardClaims: jwt.StandardClaims{
 			Subject:   user.ID.String(),
 			Audience:  user.Aud,
@@ -282,9 +375,16 @@ func generateAccessToken(tx *storage.Connection, user *models.User, sessionId *u
 		SessionId:                     sid,
 		AuthenticatorAssuranceLevel:   aal,
 		AuthenticationMethodReference: amr,
-	}
+		AuthTime:                      authTime,
+		ClientID:                      clientID,
+		Scope:                         scope,
+	}, session, nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// signJWT signs the claims with the current signing key.
+func signJWT(claims jwt.Claims, config *conf.JWTConfiguration) (string, error) {
+	signingMethod, signingKey := signingMethodAndKey(config)
+	token := jwt.NewWithClaims(signingMethod, claims)
 
 	if config.KeyID != "" {
 		if token.Header == nil {
@@ -294,12 +394,7 @@ func generateAccessToken(tx *storage.Connection, user *models.User, sessionId *u
 		token.Header["kid"] = config.KeyID
 	}
 
-	signed, err := token.SignedString([]byte(config.Secret))
-	if err != nil {
-		return "", 0, err
-	}
-
-	return signed, expiresAt, nil
+	return token.SignedString(signingKey)
 }
 
 func (a *API) issueRefreshToken(ctx context.Context, conn *storage.Connection, user *models.User, authenticationMethod models.AuthenticationMethod, grantParams models.GrantParams) (*AccessTokenResponse, error) {
@@ -308,14 +403,14 @@ func (a *API) issueRefreshToken(ctx context.Context, conn *storage.Connection, u
 	now := time.Now()
 	user.LastSignInAt = &now
 
-	var tokenString string
+	var tokenString, idToken string
 	var expiresAt int64
 	var refreshToken *models.RefreshToken
 
 	err := conn.Transaction(func(tx *storage.Connection) error {
 		var terr error
 
-		refreshToken, terr = models.GrantAuthenticatedUser(tx, user, grantParams)
+		refreshToken, terr = models.GrantAuthenticatedUser(tx, config.Security.RefreshTokenHashKey, user, grantParams)
 		if terr != nil {
 			return internalServerError("Database error granting user").WithInternalError(terr)
 		}
@@ -325,10 +420,23 @@ func (a *API) issueRefreshToken(ctx context.Context, conn *storage.Connection, u
 			return terr
 		}
 
-		tokenString, expiresAt, terr = generateAccessToken(tx, user, refreshToken.SessionId, &config.JWT)
+		if grantParams.TrustedDeviceID != nil {
+			if terr = models.AddClaimToSession(tx, *refreshToken.SessionId, models.TrustedDeviceSignIn); terr != nil {
+				return terr
+			}
+		}
+
+		tokenString, expiresAt, terr = a.generateAccessToken(tx, user, refreshToken.SessionId)
 		if terr != nil {
 			return internalServerError("error generating jwt token").WithInternalError(terr)
 		}
+
+		if config.OIDC.Enabled {
+			idToken, terr = generateIDToken(tx, user, refreshToken.SessionId, grantParams.Nonce, &config.JWT)
+			if terr != nil {
+				return internalServerError("error generating id token").WithInternalError(terr)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -342,13 +450,14 @@ func (a *API) issueRefreshToken(ctx context.Context, conn *storage.Connection, u
 		ExpiresAt:    expiresAt,
 		RefreshToken: refreshToken.Token,
 		User:         user,
+		IDToken:      idToken,
 	}, nil
 }
 
 func (a *API) updateMFASessionAndClaims(r *http.Request, tx *storage.Connection, user *models.User, authenticationMethod models.AuthenticationMethod, grantParams models.GrantParams) (*AccessTokenResponse, error) {
 	ctx := r.Context()
 	config := a.config
-	var tokenString string
+	var tokenString, idToken string
 	var expiresAt int64
 	var refreshToken *models.RefreshToken
 	currentClaims := getClaims(ctx)
@@ -369,7 +478,7 @@ func (a *API) updateMFASessionAndClaims(r *http.Request, tx *storage.Connection,
 			return terr
 		}
 		// Swap to ensure current token is the latest one
-		refreshToken, terr = models.GrantRefreshTokenSwap(r, tx, user, currentToken)
+		refreshToken, terr = models.GrantRefreshTokenSwap(r, tx, config.Security.RefreshTokenHashKey, user, currentToken)
 		if terr != nil {
 			return terr
 		}
@@ -378,6 +487,10 @@ func (a *API) updateMFASessionAndClaims(r *http.Request, tx *storage.Connection,
 			return terr
 		}
 
+		if err := session.UpdateAuthTime(tx); err != nil {
+			return err
+		}
+
 		if err := session.UpdateAssociatedFactor(tx, grantParams.FactorID); err != nil {
 			return err
 		}
@@ -385,11 +498,18 @@ func (a *API) updateMFASessionAndClaims(r *http.Request, tx *storage.Connection,
 			return err
 		}
 
-		tokenString, expiresAt, terr = generateAccessToken(tx, user, &sessionId, &config.JWT)
+		tokenString, expiresAt, terr = a.generateAccessToken(tx, user, &sessionId)
 
 		if terr != nil {
 			return internalServerError("error generating jwt token").WithInternalError(terr)
 		}
+
+		if config.OIDC.Enabled {
+			idToken, terr = generateIDToken(tx, user, &sessionId, "", &config.JWT)
+			if terr != nil {
+				return internalServerError("error generating id token").WithInternalError(terr)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -402,6 +522,7 @@ func (a *API) updateMFASessionAndClaims(r *http.Request, tx *storage.Connection,
 		ExpiresAt:    expiresAt,
 		RefreshToken: refreshToken.Token,
 		User:         user,
+		IDToken:      idToken,
 	}, nil
 }
 
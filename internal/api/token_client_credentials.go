@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// ClientCredentialsGrantParams are the parameters the ClientCredentialsGrant
+// method accepts.
+type ClientCredentialsGrantParams struct {
+	Scope        string `json:"scope"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// ClientCredentialsResponse is the response of the client_credentials grant.
+// No refresh token is issued, see RFC 6749 Section 4.4.3.
+type ClientCredentialsResponse struct {
+	Token     string `json:"access_token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int    `json:"expires_in"`
+	ExpiresAt int64  `json:"expires_at"`
+	Scope     string `json:"scope,omitempty"`
+}
+
+// ClientCredentialsGrant issues a short-lived access token to a service
+// account. The sub claim of the token is the ID of the service account.
+func (a *API) ClientCredentialsGrant(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	db := a.db.WithContext(ctx)
+	config := a.config
+
+	if !config.ServiceAccounts.Enabled {
+		return oauthError("unsupported_grant_type", "")
+	}
+
+	params := &ClientCredentialsGrantParams{}
+	if err := readOAuthRequestParams(r, params); err != nil {
+		return err
+	}
+
+	clientID, clientSecret := params.ClientID, params.ClientSecret
+	if username, password, ok := r.BasicAuth(); ok {
+		clientID, clientSecret = username, password
+	}
+
+	id, err := uuid.FromString(clientID)
+	if err != nil {
+		return oauthError("invalid_client", "Invalid client credentials")
+	}
+
+	account, err := models.FindServiceAccountByID(db, id)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_client", "Invalid client credentials")
+		}
+		return internalServerError("Database error finding service account").WithInternalError(err)
+	}
+
+	if !account.VerifySecret(clientSecret) {
+		return oauthError("invalid_client", "Invalid client credentials")
+	}
+
+	now := time.Now().UTC()
+	if account.IsExpired(now) {
+		return oauthError("invalid_client", "Service account has expired")
+	}
+
+	scopes := models.ParseScopes(params.Scope)
+	if len(scopes) == 0 {
+		scopes = account.Scopes
+	}
+
+	if !account.AllowsScopes(scopes) {
+		return oauthError("invalid_scope", "Requested scope is not allowed for this service account")
+	}
+
+	// tokens never outlive the service account
+	expiresAt := now.Add(time.Second * time.Duration(config.ServiceAccounts.TokenExp))
+	if account.ExpiresAt != nil && account.ExpiresAt.Before(expiresAt) {
+		expiresAt = *account.ExpiresAt
+	}
+
+	scope := models.FormatScopes(scopes)
+
+	claims := &GoTrueClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   account.ID.String(),
+			Audience:  config.JWT.Aud,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+			Issuer:    config.JWT.Issuer,
+		},
+		Role:     account.Role,
+		ClientID: account.ID.String(),
+		Scope:    scope,
+	}
+
+	tokenString, err := signJWT(claims, &config.JWT)
+	if err != nil {
+		return internalServerError("error generating jwt token").WithInternalError(err)
+	}
+
+	if err := db.Transaction(func(tx *storage.Connection) error {
+		return models.NewAuditLogEntry(r, tx, serviceAccountActor(account), models.ServiceAccountTokenAction, "", map[string]interface{}{
+			"service_account_id": account.ID,
+			"scope":              scope,
+		})
+	}); err != nil {
+		return internalServerError("Database error creating audit log entry").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, &ClientCredentialsResponse{
+		Token:     tokenString,
+		TokenType: "bearer",
+		ExpiresIn: int(expiresAt.Sub(now).Seconds()),
+		ExpiresAt: expiresAt.Unix(),
+		Scope:     scope,
+	})
+}
+
+// serviceAccountActor represents a service account as the actor of audit log
+// entries.
+func serviceAccountActor(account *models.ServiceAccount) *models.User {
+	return &models.User{
+		ID:    account.ID,
+		Role:  account.Role,
+		Email: storage.NullString(account.Name),
+	}
+}
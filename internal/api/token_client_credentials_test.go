@@ -0,0 +1,211 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+type ClientCredentialsTestSuite struct {
+	suite.Suite
+	API      *API
+	Config   *conf.GlobalConfiguration
+	AdminJWT string
+}
+
+func TestClientCredentials(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.ServiceAccounts.Enabled = true
+
+	ts := &ClientCredentialsTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *ClientCredentialsTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	claims := &GoTrueClaims{
+		Role: "supabase_admin",
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.Config.JWT.Secret))
+	require.NoError(ts.T(), err, "Error generating admin jwt")
+	ts.AdminJWT = token
+}
+
+func (ts *ClientCredentialsTestSuite) createServiceAccount(params map[string]interface{}) (*httptest.ResponseRecorder, *ServiceAccountResponse) {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(params))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/admin/service_accounts", &buffer)
+	req.Header.Set("Authorization", "Bearer "+ts.AdminJWT)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+
+	account := &ServiceAccountResponse{}
+	if w.Code == http.StatusCreated {
+		require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(account))
+	}
+	return w, account
+}
+
+func (ts *ClientCredentialsTestSuite) requestToken(clientID, clientSecret, scope string) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *ClientCredentialsTestSuite) TestClientCredentialsGrant() {
+	w, account := ts.createServiceAccount(map[string]interface{}{
+		"name":   "Billing worker",
+		"role":   "billing_worker",
+		"scopes": []string{"invoices:read", "invoices:write"},
+	})
+	require.Equal(ts.T(), http.StatusCreated, w.Code)
+	require.NotEmpty(ts.T(), account.ClientSecret)
+
+	w = ts.requestToken(account.ID.String(), account.ClientSecret, "invoices:read")
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := ClientCredentialsResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), "bearer", data.TokenType)
+	require.Equal(ts.T(), "invoices:read", data.Scope)
+	require.Equal(ts.T(), ts.Config.ServiceAccounts.TokenExp, data.ExpiresIn)
+
+	claims := &GoTrueClaims{}
+	p := jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Name}}
+	_, err := p.ParseWithClaims(data.Token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(ts.Config.JWT.Secret), nil
+	})
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), account.ID.String(), claims.Subject)
+	require.Equal(ts.T(), account.ID.String(), claims.ClientID)
+	require.Equal(ts.T(), "billing_worker", claims.Role)
+	require.Equal(ts.T(), "invoices:read", claims.Scope)
+
+	count, err := ts.API.db.Q().Where("payload->>'action' = ?", string(models.ServiceAccountTokenAction)).Count(&models.AuditLogEntry{})
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 1, count)
+}
+
+func (ts *ClientCredentialsTestSuite) TestClientCredentialsGrantFailures() {
+	w, account := ts.createServiceAccount(map[string]interface{}{
+		"name":   "Billing worker",
+		"role":   "billing_worker",
+		"scopes": []string{"invoices:read"},
+	})
+	require.Equal(ts.T(), http.StatusCreated, w.Code)
+
+	expiresAt := time.Now().Add(time.Hour)
+	w, expiring := ts.createServiceAccount(map[string]interface{}{
+		"name":       "Migration worker",
+		"role":       "migration_worker",
+		"expires_at": expiresAt,
+	})
+	require.Equal(ts.T(), http.StatusCreated, w.Code)
+
+	past := time.Now().Add(-time.Minute)
+	expiring.ExpiresAt = &past
+	require.NoError(ts.T(), ts.API.db.UpdateOnly(expiring.ServiceAccount, "expires_at"))
+
+	cases := []struct {
+		desc          string
+		clientID      string
+		clientSecret  string
+		scope         string
+		expectedError string
+	}{
+		{
+			desc:          "Invalid secret",
+			clientID:      account.ID.String(),
+			clientSecret:  "invalid",
+			expectedError: "invalid_client",
+		},
+		{
+			desc:          "Unknown service account",
+			clientID:      "3d7f5b8e-2c6f-4f2e-8f6a-0d1c2b3a4e5f",
+			clientSecret:  account.ClientSecret,
+			expectedError: "invalid_client",
+		},
+		{
+			desc:          "Scope not allowed",
+			clientID:      account.ID.String(),
+			clientSecret:  account.ClientSecret,
+			scope:         "invoices:write",
+			expectedError: "invalid_scope",
+		},
+		{
+			desc:          "Expired service account",
+			clientID:      expiring.ID.String(),
+			clientSecret:  expiring.ClientSecret,
+			expectedError: "invalid_client",
+		},
+	}
+
+	for _, c := range cases {
+		ts.Run(c.desc, func() {
+			w := ts.requestToken(c.clientID, c.clientSecret, c.scope)
+			require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+			data := map[string]interface{}{}
+			require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+			require.Equal(ts.T(), c.expectedError, data["error"])
+		})
+	}
+}
+
+func (ts *ClientCredentialsTestSuite) TestServiceAccountAdminRole() {
+	w, _ := ts.createServiceAccount(map[string]interface{}{
+		"name": "Too powerful",
+		"role": "service_role",
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *ClientCredentialsTestSuite) TestClientCredentialsGrantDisabled() {
+	ts.Config.ServiceAccounts.Enabled = false
+	defer func() {
+		ts.Config.ServiceAccounts.Enabled = true
+	}()
+
+	w, account := ts.createServiceAccount(map[string]interface{}{
+		"name": "Billing worker",
+		"role": "billing_worker",
+	})
+	require.Equal(ts.T(), http.StatusCreated, w.Code)
+
+	w = ts.requestToken(account.ID.String(), account.ClientSecret, "")
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	data := map[string]interface{}{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), "unsupported_grant_type", data["error"])
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// TokenIntrospectionParams are the parameters the TokenIntrospect method
+// accepts.
+type TokenIntrospectionParams struct {
+	Token         string `json:"token"`
+	TokenTypeHint string `json:"token_type_hint"`
+	ClientID      string `json:"client_id"`
+	ClientSecret  string `json:"client_secret"`
+}
+
+// TokenIntrospectionResponse describes a token as defined in RFC 7662
+// Section 2.2. Only Active is set for inactive tokens.
+type TokenIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	Role      string `json:"role,omitempty"`
+	AAL       string `json:"aal,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// TokenIntrospect tells resource servers whether an access or refresh token
+// is active, i.e. whether the session it belongs to is still alive.
+func (a *API) TokenIntrospect(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	params := &TokenIntrospectionParams{}
+	if err := readOAuthRequestParams(r, params); err != nil {
+		return err
+	}
+
+	client, err := a.authenticateTokenRequest(w, r, db, params.ClientID, params.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if client != nil && !client.IsConfidential() {
+		return oauthError("invalid_client", "Public clients cannot introspect tokens")
+	}
+
+	if params.Token == "" {
+		return oauthError("invalid_request", "token is required")
+	}
+
+	// access tokens are JWTs and refresh tokens are not, so the token type
+	// hint is not needed to tell them apart
+	var response *TokenIntrospectionResponse
+	if claims, ok := a.parseTokenClaims(params.Token); ok {
+		response, err = a.introspectAccessToken(db, claims)
+	} else {
+		response, err = a.introspectRefreshToken(db, params.Token)
+	}
+	if err != nil {
+		return err
+	}
+
+	return sendJSON(w, http.StatusOK, response)
+}
+
+func (a *API) introspectAccessToken(db *storage.Connection, claims *GoTrueClaims) (*TokenIntrospectionResponse, error) {
+	inactive := &TokenIntrospectionResponse{Active: false}
+
+	if claims.SessionId == "" && claims.ClientID != "" && claims.ClientID == claims.Subject {
+		return a.introspectServiceAccountToken(db, claims)
+	}
+
+	session, user, err := findTokenSession(db, claims.SessionId)
+	if err != nil {
+		return nil, err
+	} else if session == nil || user == nil || user.IsBanned() || user.ID.String() != claims.Subject {
+		return inactive, nil
+	}
+
+	return &TokenIntrospectionResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Username:  user.GetEmail(),
+		TokenType: "bearer",
+		ExpiresAt: claims.ExpiresAt,
+		IssuedAt:  claims.IssuedAt,
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		Issuer:    claims.Issuer,
+		Role:      claims.Role,
+		AAL:       claims.AuthenticatorAssuranceLevel,
+		SessionID: claims.SessionId,
+	}, nil
+}
+
+// introspectServiceAccountToken introspects tokens issued with the
+// client_credentials grant, which are active as long as the service account
+// exists and has not expired.
+func (a *API) introspectServiceAccountToken(db *storage.Connection, claims *GoTrueClaims) (*TokenIntrospectionResponse, error) {
+	inactive := &TokenIntrospectionResponse{Active: false}
+
+	id, err := uuid.FromString(claims.ClientID)
+	if err != nil {
+		return inactive, nil
+	}
+
+	account, err := models.FindServiceAccountByID(db, id)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return inactive, nil
+		}
+		return nil, internalServerError("Database error finding service account").WithInternalError(err)
+	}
+
+	if account.IsExpired(time.Now()) {
+		return inactive, nil
+	}
+
+	return &TokenIntrospectionResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Username:  account.Name,
+		TokenType: "bearer",
+		ExpiresAt: claims.ExpiresAt,
+		IssuedAt:  claims.IssuedAt,
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		Issuer:    claims.Issuer,
+		Role:      claims.Role,
+	}, nil
+}
+
+func (a *API) introspectRefreshToken(db *storage.Connection, token string) (*TokenIntrospectionResponse, error) {
+	inactive := &TokenIntrospectionResponse{Active: false}
+
+	user, refreshToken, session, err := models.FindUserWithRefreshToken(db, a.config.Security.RefreshTokenHashKey, token, false)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return inactive, nil
+		}
+		return nil, internalServerError("Database error finding refresh token").WithInternalError(err)
+	}
+
+	if refreshToken.Revoked || session == nil || user.IsBanned() || isSessionExpired(session) {
+		return inactive, nil
+	}
+
+	response := &TokenIntrospectionResponse{
+		Active:    true,
+		Scope:     session.Scopes.String(),
+		Username:  user.GetEmail(),
+		IssuedAt:  refreshToken.CreatedAt.Unix(),
+		Subject:   user.ID.String(),
+		Audience:  user.Aud,
+		Issuer:    a.config.JWT.Issuer,
+		Role:      user.Role,
+		AAL:       session.GetAAL(),
+		SessionID: session.ID.String(),
+	}
+
+	if session.OAuthClientID != nil {
+		response.ClientID = session.OAuthClientID.String()
+	}
+
+	if session.NotAfter != nil {
+		response.ExpiresAt = session.NotAfter.Unix()
+	}
+
+	return response, nil
+}
+
+// parseTokenClaims verifies an access token, returning false if it is not a
+// valid JWT issued by this server.
+func (a *API) parseTokenClaims(token string) (*GoTrueClaims, bool) {
+	config := a.config
+
+	claims := &GoTrueClaims{}
+	p := jwt.Parser{ValidMethods: validSigningMethods(&config.JWT)}
+	if _, err := p.ParseWithClaims(token, claims, verificationKey(&config.JWT)); err != nil {
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// findTokenSession finds the live session with the provided ID, together
+// with its user. A nil session is returned if there is no such session or if
+// it has expired.
+func findTokenSession(db *storage.Connection, sessionID string) (*models.Session, *models.User, error) {
+	id, err := uuid.FromString(sessionID)
+	if err != nil || id == uuid.Nil {
+		return nil, nil, nil
+	}
+
+	session, err := models.FindSessionByID(db, id, false)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, internalServerError("Database error finding session").WithInternalError(err)
+	}
+
+	if isSessionExpired(session) {
+		return nil, nil, nil
+	}
+
+	user, err := models.FindUserByID(db, session.UserID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, internalServerError("Database error finding user").WithInternalError(err)
+	}
+
+	return session, user, nil
+}
+
+func isSessionExpired(session *models.Session) bool {
+	return session.NotAfter != nil && time.Now().UTC().After(*session.NotAfter)
+}
+
+// authenticateTokenRequest authenticates requests to the introspection and
+// revocation endpoints, which can be made by OAuth clients with their
+// credentials or by the service role with a Bearer token. A nil client is
+// returned for the service role.
+func (a *API) authenticateTokenRequest(w http.ResponseWriter, r *http.Request, db *storage.Connection, clientID, clientSecret string) (*models.OAuthClient, error) {
+	if _, err := a.extractBearerToken(r); err == nil {
+		if _, err := a.requireAdminCredentials(w, r); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if !a.config.OAuthServer.Enabled {
+		return nil, oauthError("invalid_client", "Client authentication is not available as the OAuth server is disabled")
+	}
+
+	return a.authenticateOAuthClient(db, r, clientID, clientSecret)
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+type TokenIntrospectionTestSuite struct {
+	suite.Suite
+	API      *API
+	Config   *conf.GlobalConfiguration
+	AdminJWT string
+}
+
+func TestTokenIntrospection(t *testing.T) {
+	api, config, err := setupAPIForTest()
+	require.NoError(t, err)
+
+	config.OAuthServer.Enabled = true
+	config.RateLimitHeader = "My-Custom-Header"
+
+	ts := &TokenIntrospectionTestSuite{
+		API:    api,
+		Config: config,
+	}
+	defer api.db.Close()
+
+	suite.Run(t, ts)
+}
+
+func (ts *TokenIntrospectionTestSuite) SetupTest() {
+	models.TruncateAll(ts.API.db)
+
+	claims := &GoTrueClaims{
+		Role: "supabase_admin",
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ts.Config.JWT.Secret))
+	require.NoError(ts.T(), err, "Error generating admin jwt")
+	ts.AdminJWT = token
+
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	require.NoError(ts.T(), err, "Error creating test user model")
+	now := time.Now()
+	u.EmailConfirmedAt = &now
+	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
+}
+
+func (ts *TokenIntrospectionTestSuite) login() *AccessTokenResponse {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *TokenIntrospectionTestSuite) tokenRequest(path, token string, authorize func(req *http.Request)) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("token", token)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost"+path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	authorize(req)
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	return w
+}
+
+func (ts *TokenIntrospectionTestSuite) asServiceRole(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+ts.AdminJWT)
+}
+
+func (ts *TokenIntrospectionTestSuite) introspect(token string) *TokenIntrospectionResponse {
+	w := ts.tokenRequest("/token/introspect", token, ts.asServiceRole)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &TokenIntrospectionResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return data
+}
+
+func (ts *TokenIntrospectionTestSuite) TestIntrospectAndRevoke() {
+	tokens := ts.login()
+
+	accessToken := ts.introspect(tokens.Token)
+	require.True(ts.T(), accessToken.Active)
+	require.Equal(ts.T(), tokens.User.ID.String(), accessToken.Subject)
+	require.Equal(ts.T(), "test@example.com", accessToken.Username)
+	require.Equal(ts.T(), "bearer", accessToken.TokenType)
+	require.NotEmpty(ts.T(), accessToken.SessionID)
+
+	refreshToken := ts.introspect(tokens.RefreshToken)
+	require.True(ts.T(), refreshToken.Active)
+	require.Equal(ts.T(), accessToken.SessionID, refreshToken.SessionID)
+
+	w := ts.tokenRequest("/token/revoke", tokens.RefreshToken, ts.asServiceRole)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	require.False(ts.T(), ts.introspect(tokens.Token).Active)
+	require.False(ts.T(), ts.introspect(tokens.RefreshToken).Active)
+
+	// revoking a token again is not an error
+	w = ts.tokenRequest("/token/revoke", tokens.RefreshToken, ts.asServiceRole)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+}
+
+func (ts *TokenIntrospectionTestSuite) TestIntrospectUnknownToken() {
+	data := ts.introspect("not-a-token")
+	require.False(ts.T(), data.Active)
+	require.Empty(ts.T(), data.Subject)
+}
+
+func (ts *TokenIntrospectionTestSuite) TestRequiresAuthentication() {
+	tokens := ts.login()
+
+	cases := []struct {
+		desc      string
+		authorize func(req *http.Request)
+		expected  int
+	}{
+		{
+			desc:      "No credentials",
+			authorize: func(req *http.Request) {},
+			expected:  http.StatusBadRequest,
+		},
+		{
+			desc: "User access token",
+			authorize: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+tokens.Token)
+			},
+			expected: http.StatusUnauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		ts.Run(c.desc, func() {
+			w := ts.tokenRequest("/token/introspect", tokens.Token, c.authorize)
+			require.Equal(ts.T(), c.expected, w.Code)
+
+			w = ts.tokenRequest("/token/revoke", tokens.Token, c.authorize)
+			require.Equal(ts.T(), c.expected, w.Code)
+		})
+	}
+
+	require.True(ts.T(), ts.introspect(tokens.Token).Active)
+}
+
+func (ts *TokenIntrospectionTestSuite) TestRateLimitClientCredentials() {
+	guessSecret := func(req *http.Request) {
+		req.Header.Set("My-Custom-Header", "1.2.3.4")
+		req.SetBasicAuth(uuid.Must(uuid.NewV4()).String(), "guessed-secret")
+	}
+
+	// It rate limits after 30 requests, across both endpoints
+	for i := 0; i < 15; i++ {
+		w := ts.tokenRequest("/token/introspect", "not-a-token", guessSecret)
+		require.NotEqual(ts.T(), http.StatusTooManyRequests, w.Code)
+
+		w = ts.tokenRequest("/token/revoke", "not-a-token", guessSecret)
+		require.NotEqual(ts.T(), http.StatusTooManyRequests, w.Code)
+	}
+
+	w := ts.tokenRequest("/token/introspect", "not-a-token", guessSecret)
+	require.Equal(ts.T(), http.StatusTooManyRequests, w.Code)
+
+	w = ts.tokenRequest("/token/revoke", "not-a-token", guessSecret)
+	require.Equal(ts.T(), http.StatusTooManyRequests, w.Code)
+}
+
+func (ts *TokenIntrospectionTestSuite) TestClientCannotRevokeOtherTokens() {
+	client, secret := models.NewOAuthClient("Test Client", []string{"https://client.example.com/callback"}, []string{"openid"}, true)
+	require.NoError(ts.T(), ts.API.db.Create(client))
+
+	withClient := func(req *http.Request) {
+		req.SetBasicAuth(client.ID.String(), secret)
+	}
+
+	tokens := ts.login()
+
+	w := ts.tokenRequest("/token/introspect", tokens.Token, withClient)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	w = ts.tokenRequest("/token/revoke", tokens.RefreshToken, withClient)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	data := map[string]interface{}{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), "unauthorized_client", data["error"])
+
+	require.True(ts.T(), ts.introspect(tokens.RefreshToken).Active)
+}
@@ -24,6 +24,10 @@ type IdTokenGrantParams struct {
 	Provider    string `json:"provider"`
 	ClientID    string `json:"client_id"`
 	Issuer      string `json:"issuer"`
+
+	// TrustedDeviceToken is the device token of a trusted device, which
+	// signs the user in at aal2.
+	TrustedDeviceToken string `json:"trusted_device_token"`
 }
 
 func (p *IdTokenGrantParams) getProvider(ctx context.Context, config *conf.GlobalConfiguration, r *http.Request) (*oidc.Provider, *conf.OAuthProviderConfiguration, string, []string, error) {
@@ -206,6 +210,10 @@ func (a *API) IdTokenGrant(ctx context.Context, w http.ResponseWriter, r *http.R
 			return terr
 		}
 
+		grantParams.FillGrantParams(r)
+		if terr = a.trustedDeviceGrant(tx, user, params.TrustedDeviceToken, &grantParams); terr != nil {
+			return terr
+		}
 		token, terr = a.issueRefreshToken(ctx, tx, user, models.OAuth, grantParams)
 		if terr != nil {
 			return terr
@@ -2,14 +2,15 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	mathRand "math/rand"
 	"net/http"
 	"time"
 
 	"github.com/supabase/gotrue/internal/metering"
 	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/observability"
 	"github.com/supabase/gotrue/internal/storage"
+	"github.com/supabase/gotrue/internal/utilities"
 )
 
 const retryLoopDuration = 5.0
@@ -17,6 +18,11 @@ const retryLoopDuration = 5.0
 // RefreshTokenGrantParams are the parameters the RefreshTokenGrant method accepts
 type RefreshTokenGrantParams struct {
 	RefreshToken string `json:"refresh_token"`
+
+	// ClientID and ClientSecret authenticate confidential OAuth clients
+	// refreshing the tokens issued to them.
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
 }
 
 // RefreshTokenGrant implements the refresh_token grant type flow
@@ -25,14 +31,8 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 	config := a.config
 
 	params := &RefreshTokenGrantParams{}
-
-	body, err := getBodyBytes(r)
-	if err != nil {
-		return badRequestError("Could not read body").WithInternalError(err)
-	}
-
-	if err := json.Unmarshal(body, params); err != nil {
-		return badRequestError("Could not read refresh token grant params: %v", err)
+	if err := readOAuthRequestParams(r, params); err != nil {
+		return err
 	}
 
 	if params.RefreshToken == "" {
@@ -50,7 +50,7 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 	for retry && time.Since(retryStart).Seconds() < retryLoopDuration {
 		retry = false
 
-		user, _, session, err := models.FindUserWithRefreshToken(db, params.RefreshToken, false)
+		user, _, session, err := models.FindUserWithRefreshToken(db, config.Security.RefreshTokenHashKey, params.RefreshToken, false)
 		if err != nil {
 			if models.IsNotFoundError(err) {
 				return oauthError("invalid_grant", "Invalid Refresh Token: Refresh Token Not Found")
@@ -72,6 +72,12 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 			if !notAfter.IsZero() && time.Now().UTC().After(notAfter) {
 				return oauthError("invalid_grant", "Invalid Refresh Token: Session Expired")
 			}
+
+			if session.OAuthClientID != nil {
+				if err := a.authenticateRefreshTokenClient(db, r, session, params); err != nil {
+					return err
+				}
+			}
 		}
 
 		// Basic checks above passed, now we need to serialize access
@@ -84,9 +90,10 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 		var tokenString string
 		var expiresAt int64
 		var newTokenResponse *AccessTokenResponse
+		var reusedToken *models.RefreshToken
 
 		err = db.Transaction(func(tx *storage.Connection) error {
-			user, token, session, terr := models.FindUserWithRefreshToken(tx, params.RefreshToken, true /* forUpdate */)
+			user, token, session, terr := models.FindUserWithRefreshToken(tx, config.Security.RefreshTokenHashKey, params.RefreshToken, true /* forUpdate */)
 			if terr != nil {
 				if models.IsNotFoundError(terr) {
 					// because forUpdate was set, and the
@@ -114,16 +121,22 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 					return internalServerError(terr.Error())
 				}
 
-				if activeRefreshToken != nil && activeRefreshToken.Parent.String() == token.Token {
+				if activeRefreshToken != nil && activeRefreshToken.Parent.String() == token.TokenHash {
 					// Token was revoked, but it's the
 					// parent of the currently active one.
 					// This indicates that the client was
 					// not able to store the result when it
 					// refreshed token. This case is
-					// allowed, provided we return back the
-					// active refresh token instead of
-					// creating a new one.
-					issuedToken = activeRefreshToken
+					// allowed, but as only the hash of the
+					// active refresh token is stored it
+					// cannot be returned back, so it is
+					// rotated instead.
+					newToken, terr := models.GrantRefreshTokenSwap(r, tx, config.Security.RefreshTokenHashKey, user, activeRefreshToken)
+					if terr != nil {
+						return terr
+					}
+
+					issuedToken = newToken
 				} else {
 					// For a revoked refresh token to be reused, it
 					// has to fall within the reuse interval.
@@ -132,16 +145,20 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 
 					if time.Now().After(reuseUntil) {
 						a.clearCookieTokens(config, w)
-						// not OK to reuse this token
+						// not OK to reuse this token, the
+						// refresh token has likely been
+						// stolen
 
-						if config.Security.RefreshTokenRotationEnabled {
-							// Revoke all tokens in token family
-							if err := models.RevokeTokenFamily(tx, token); err != nil {
-								return internalServerError(err.Error())
-							}
+						if terr := a.revokeReusedRefreshToken(r, tx, user, token); terr != nil {
+							return terr
 						}
 
-						return oauthError("invalid_grant", "Invalid Refresh Token: Already Used").WithInternalMessage("Possible abuse attempt: %v", token.ID)
+						// the transaction needs to be
+						// committed for the revocation to
+						// take effect, the error is returned
+						// after it
+						reusedToken = token
+						return nil
 					}
 				}
 			}
@@ -151,7 +168,7 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 			}
 
 			if issuedToken == nil {
-				newToken, terr := models.GrantRefreshTokenSwap(r, tx, user, token)
+				newToken, terr := models.GrantRefreshTokenSwap(r, tx, config.Security.RefreshTokenHashKey, user, token)
 				if terr != nil {
 					return terr
 				}
@@ -159,11 +176,25 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 				issuedToken = newToken
 			}
 
-			tokenString, expiresAt, terr = generateAccessToken(tx, user, issuedToken.SessionId, &config.JWT)
+			if session != nil {
+				if terr = session.UpdateOnRefresh(tx, r.Header.Get("User-Agent"), utilities.GetIPAddress(r)); terr != nil {
+					return internalServerError("Database error updating session").WithInternalError(terr)
+				}
+			}
+
+			tokenString, expiresAt, terr = a.generateAccessToken(tx, user, issuedToken.SessionId)
 			if terr != nil {
 				return internalServerError("error generating jwt token").WithInternalError(terr)
 			}
 
+			var idToken string
+			if config.OIDC.Enabled {
+				idToken, terr = generateIDToken(tx, user, issuedToken.SessionId, "", &config.JWT)
+				if terr != nil {
+					return internalServerError("error generating id token").WithInternalError(terr)
+				}
+			}
+
 			newTokenResponse = &AccessTokenResponse{
 				Token:        tokenString,
 				TokenType:    "bearer",
@@ -171,6 +202,7 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 				ExpiresAt:    expiresAt,
 				RefreshToken: issuedToken.Token,
 				User:         user,
+				IDToken:      idToken,
 			}
 			if terr = a.setCookieTokens(config, newTokenResponse, false, w); terr != nil {
 				return internalServerError("Failed to set JWT cookie. %s", terr)
@@ -178,6 +210,11 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 
 			return nil
 		})
+		if err == nil && reusedToken != nil {
+			a.notifyRefreshTokenReuse(ctx, r, db, user)
+			return oauthError("invalid_grant", "Invalid Refresh Token: Already Used").WithInternalMessage("Possible abuse attempt: %v", reusedToken.ID)
+		}
+
 		if err == nil {
 			// success
 			metering.RecordLogin("token", user.ID)
@@ -199,3 +236,83 @@ func (a *API) RefreshTokenGrant(ctx context.Context, w http.ResponseWriter, r *h
 
 	return conflictError("Too many concurrent token refresh requests on the same session or refresh token")
 }
+
+// revokeReusedRefreshToken responds to the reuse of a revoked refresh token
+// outside of the reuse interval, which indicates that the token has been
+// stolen.
+func (a *API) revokeReusedRefreshToken(r *http.Request, tx *storage.Connection, user *models.User, token *models.RefreshToken) error {
+	config := a.config
+
+	if terr := models.NewAuditLogEntry(r, tx, user, models.RefreshTokenReuseDetectedAction, "", map[string]interface{}{
+		"refresh_token_id": token.ID,
+		"session_id":       token.SessionId,
+	}); terr != nil {
+		return terr
+	}
+
+	if config.Security.RefreshTokenRotationEnabled {
+		// Revoke all tokens in token family
+		if err := models.RevokeTokenFamily(tx, token); err != nil {
+			return internalServerError(err.Error())
+		}
+	}
+
+	if config.Security.RefreshTokenReuseRevokeSessions {
+		if err := models.Logout(tx, user.ID); err != nil {
+			return internalServerError("Error revoking user's sessions").WithInternalError(err)
+		}
+
+		if err := models.LogoutAllRefreshTokens(tx, user.ID); err != nil {
+			return internalServerError("Error revoking user's refresh tokens").WithInternalError(err)
+		}
+	}
+
+	return nil
+}
+
+// notifyRefreshTokenReuse lets the webhook and, if enabled, the user know
+// that a refresh token has been reused. Failures are only logged, as the
+// tokens have already been revoked.
+func (a *API) notifyRefreshTokenReuse(ctx context.Context, r *http.Request, db *storage.Connection, user *models.User) {
+	config := a.config
+	log := observability.GetLogEntry(r)
+
+	if err := triggerEventHooks(ctx, db, RefreshTokenReuseEvent, user, config); err != nil {
+		log.WithError(err).Warn("Error triggering refresh token reuse webhook")
+	}
+
+	if config.Security.RefreshTokenReuseNotifyUser && user.GetEmail() != "" {
+		mailer := a.Mailer(ctx)
+		if err := mailer.RefreshTokenReuseMail(user); err != nil {
+			log.WithError(err).Warn("Error sending refresh token reuse email")
+		}
+	}
+}
+
+// authenticateRefreshTokenClient requires a confidential OAuth client to
+// authenticate when refreshing the tokens issued to it, as described in RFC
+// 6749 Section 6. Public clients have no credentials to authenticate with.
+func (a *API) authenticateRefreshTokenClient(db *storage.Connection, r *http.Request, session *models.Session, params *RefreshTokenGrantParams) error {
+	client, err := models.FindOAuthClientByID(db, *session.OAuthClientID)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return oauthError("invalid_grant", "Invalid Refresh Token: OAuth Client Not Found")
+		}
+		return internalServerError("Database error finding OAuth client").WithInternalError(err)
+	}
+
+	if !client.IsConfidential() {
+		return nil
+	}
+
+	authenticated, err := a.authenticateOAuthClient(db, r, params.ClientID, params.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if authenticated.ID != client.ID {
+		return oauthError("invalid_grant", "Invalid Refresh Token: Issued To Another Client")
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// TokenRevocationParams are the parameters the TokenRevoke method accepts.
+type TokenRevocationParams struct {
+	Token         string `json:"token"`
+	TokenTypeHint string `json:"token_type_hint"`
+	ClientID      string `json:"client_id"`
+	ClientSecret  string `json:"client_secret"`
+}
+
+// TokenRevoke revokes a single access or refresh token as defined in RFC
+// 7009, ending the session the token belongs to. Clients can only revoke
+// tokens that were issued to them. Unknown or already revoked tokens are not
+// an error.
+func (a *API) TokenRevoke(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	db := a.db.WithContext(ctx)
+
+	params := &TokenRevocationParams{}
+	if err := readOAuthRequestParams(r, params); err != nil {
+		return err
+	}
+
+	client, err := a.authenticateTokenRequest(w, r, db, params.ClientID, params.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if params.Token == "" {
+		return oauthError("invalid_request", "token is required")
+	}
+
+	var user *models.User
+	var refreshToken *models.RefreshToken
+	var session *models.Session
+
+	if claims, ok := a.parseTokenClaims(params.Token); ok {
+		session, user, err = findTokenSession(db, claims.SessionId)
+	} else {
+		user, refreshToken, session, err = models.FindUserWithRefreshToken(db, a.config.Security.RefreshTokenHashKey, params.Token, false)
+		if models.IsNotFoundError(err) {
+			err = nil
+		} else if err != nil {
+			err = internalServerError("Database error finding refresh token").WithInternalError(err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if user == nil || (refreshToken == nil && session == nil) {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	if client != nil && (session == nil || session.OAuthClientID == nil || *session.OAuthClientID != client.ID) {
+		return oauthError("unauthorized_client", "Token was not issued to this client")
+	}
+
+	err = db.Transaction(func(tx *storage.Connection) error {
+		var traits map[string]interface{}
+		if client != nil {
+			traits = map[string]interface{}{
+				"client_id": client.ID,
+			}
+		}
+
+		if terr := models.NewAuditLogEntry(r, tx, user, models.TokenRevokedAction, "", traits); terr != nil {
+			return terr
+		}
+
+		if refreshToken != nil {
+			if terr := models.RevokeTokenFamily(tx, refreshToken); terr != nil {
+				return terr
+			}
+		}
+
+		if session != nil {
+			return models.LogoutSession(tx, session.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return internalServerError("Database error revoking token").WithInternalError(err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 )
 
@@ -48,7 +50,7 @@ func (ts *TokenTestSuite) SetupTest() {
 	models.TruncateAll(ts.API.db)
 
 	// Create user & refresh token
-	u, err := models.NewUser("12345678", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "12345678", "test@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	t := time.Now()
 	u.EmailConfirmedAt = &t
@@ -56,7 +58,7 @@ func (ts *TokenTestSuite) SetupTest() {
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
 
 	ts.User = u
-	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, u, models.GrantParams{})
+	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
 	require.NoError(ts.T(), err, "Error creating refresh token")
 }
 
@@ -153,6 +155,88 @@ func (ts *TokenTestSuite) TestTokenPasswordGrantSuccess() {
 	assert.Equal(ts.T(), http.StatusOK, w.Code)
 }
 
+func (ts *TokenTestSuite) TestTokenPasswordGrantInvalidForwardedIP() {
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", "foo")
+
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	// the forwarded IP address isn't valid and isn't stored
+	sessions, err := models.FindAllSessionsByUserID(ts.API.db, ts.User.ID)
+	require.NoError(ts.T(), err)
+	require.Len(ts.T(), sessions, 2)
+	require.Nil(ts.T(), sessions[0].IP)
+}
+
+func (ts *TokenTestSuite) TestTokenPasswordGrantRehashesPassword() {
+	passwordHash := ts.Config.PasswordHash
+	ts.Config.PasswordHash.Algorithm = crypto.Argon2idAlgorithm
+	defer func() {
+		ts.Config.PasswordHash = passwordHash
+	}()
+
+	require.True(ts.T(), strings.HasPrefix(ts.User.EncryptedPassword, "$2a$"))
+
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	u, err := models.FindUserByID(ts.API.db, ts.User.ID)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), strings.HasPrefix(u.EncryptedPassword, "$argon2id$"))
+	require.True(ts.T(), u.Authenticate("password"))
+
+	// the session the user was signed in with before is not affected
+	_, err = models.FindSessionByID(ts.API.db, *ts.RefreshToken.SessionId, false)
+	require.NoError(ts.T(), err)
+}
+
+func (ts *TokenTestSuite) TestTokenPasswordGrantFlagsLeakedPassword() {
+	passwordPolicy := ts.Config.PasswordPolicy
+	ts.Config.PasswordPolicy.LeakedPasswordsPath = writeLeakedPasswords(ts.T())
+	ts.Config.PasswordPolicy.FlagLeakedPasswordsOnLogin = true
+	defer func() {
+		ts.Config.PasswordPolicy = passwordPolicy
+	}()
+
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=password", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	require.NotEmpty(ts.T(), data.Token)
+	require.NotNil(ts.T(), data.WeakPassword)
+	require.Equal(ts.T(), []string{WeakPasswordLeaked}, data.WeakPassword.Reasons)
+}
+
 func (ts *TokenTestSuite) TestTokenRefreshTokenGrantSuccess() {
 	var buffer bytes.Buffer
 	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
@@ -251,15 +335,15 @@ func (ts *TokenTestSuite) TestTokenRefreshTokenGrantFailure() {
 }
 
 func (ts *TokenTestSuite) TestTokenRefreshTokenRotation() {
-	u, err := models.NewUser("", "foo@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "foo@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	t := time.Now()
 	u.EmailConfirmedAt = &t
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving foo user")
 
-	first, err := models.GrantAuthenticatedUser(ts.API.db, u, models.GrantParams{})
+	first, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
 	require.NoError(ts.T(), err)
-	second, err := models.GrantRefreshTokenSwap(&http.Request{}, ts.API.db, u, first)
+	second, err := models.GrantRefreshTokenSwap(&http.Request{}, ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, first)
 	require.NoError(ts.T(), err)
 
 	cases := []struct {
@@ -331,8 +415,67 @@ func (ts *TokenTestSuite) TestTokenRefreshTokenRotation() {
 	}
 }
 
+func (ts *TokenTestSuite) TestTokenRefreshTokenReuseDetection() {
+	events := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := struct {
+			Event string `json:"event"`
+		}{}
+		require.NoError(ts.T(), json.NewDecoder(r.Body).Decode(&payload))
+		events <- payload.Event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ts.Config.Webhook = conf.WebhookConfig{
+		URL:    server.URL,
+		Events: []string{RefreshTokenReuseEvent},
+		Secret: "webhook-secret",
+	}
+	ts.Config.Security.RefreshTokenRotationEnabled = true
+	ts.Config.Security.RefreshTokenReuseInterval = 0
+	ts.Config.Security.RefreshTokenReuseRevokeSessions = true
+	defer func() {
+		ts.Config.Webhook = conf.WebhookConfig{}
+		ts.Config.Security.RefreshTokenReuseRevokeSessions = false
+	}()
+
+	first := ts.RefreshToken
+	_, err := models.GrantRefreshTokenSwap(&http.Request{}, ts.API.db, ts.Config.Security.RefreshTokenHashKey, ts.User, first)
+	require.NoError(ts.T(), err)
+
+	other, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, ts.User, models.GrantParams{})
+	require.NoError(ts.T(), err)
+
+	var buffer bytes.Buffer
+	require.NoError(ts.T(), json.NewEncoder(&buffer).Encode(map[string]interface{}{
+		"refresh_token": first.Token,
+	}))
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/token?grant_type=refresh_token", &buffer)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ts.API.handler.ServeHTTP(w, req)
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	data := make(map[string]interface{})
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(&data))
+	require.Equal(ts.T(), "invalid_grant", data["error"])
+	require.Equal(ts.T(), "Invalid Refresh Token: Already Used", data["error_description"])
+
+	require.Equal(ts.T(), RefreshTokenReuseEvent, <-events)
+
+	count, err := ts.API.db.Q().Where("payload->>'action' = ?", string(models.RefreshTokenReuseDetectedAction)).Count(&models.AuditLogEntry{})
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 1, count)
+
+	// all sessions of the user are revoked, not only the one of the reused
+	// refresh token
+	_, err = models.FindSessionByID(ts.API.db, *other.SessionId, false)
+	require.True(ts.T(), models.IsNotFoundError(err))
+}
+
 func (ts *TokenTestSuite) createBannedUser() *models.User {
-	u, err := models.NewUser("", "banned@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "banned@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	t := time.Now()
 	u.EmailConfirmedAt = &t
@@ -340,7 +483,7 @@ func (ts *TokenTestSuite) createBannedUser() *models.User {
 	u.BannedUntil = &t
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test banned user")
 
-	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, u, models.GrantParams{})
+	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
 	require.NoError(ts.T(), err, "Error creating refresh token")
 
 	return u
@@ -351,7 +494,7 @@ func (ts *TokenTestSuite) TestTokenRefreshWithExpiredSession() {
 
 	now := time.Now().UTC().Add(-1 * time.Second)
 
-	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, ts.User, models.GrantParams{
+	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, ts.User, models.GrantParams{
 		SessionNotAfter: &now,
 	})
 	require.NoError(ts.T(), err, "Error creating refresh token")
@@ -374,7 +517,7 @@ func (ts *TokenTestSuite) TestTokenRefreshWithUnexpiredSession() {
 
 	now := time.Now().UTC().Add(1 * time.Second)
 
-	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, ts.User, models.GrantParams{
+	ts.RefreshToken, err = models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, ts.User, models.GrantParams{
 		SessionNotAfter: &now,
 	})
 	require.NoError(ts.T(), err, "Error creating refresh token")
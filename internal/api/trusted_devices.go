@@ -0,0 +1,131 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/supabase/gotrue/internal/models"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// TrustedDevicesResponse lists the trusted devices of the user.
+type TrustedDevicesResponse struct {
+	Devices []*models.TrustedDevice `json:"devices"`
+}
+
+// trustDevice trusts the device the request was made from after the factor
+// was verified on it, and returns its device token.
+func (a *API) trustDevice(r *http.Request, tx *storage.Connection, user *models.User, factor *models.Factor) (string, error) {
+	config := a.config
+
+	client := models.GrantParams{}
+	client.FillGrantParams(r)
+
+	device, token := models.NewTrustedDevice(user, factor, time.Now().Add(config.MFA.TrustedDevices.Duration), client.UserAgent, client.IP)
+	if err := tx.Create(device); err != nil {
+		return "", internalServerError("Database error trusting device").WithInternalError(err)
+	}
+
+	if err := models.NewAuditLogEntry(r, tx, user, models.TrustDeviceAction, r.RemoteAddr, map[string]interface{}{
+		"factor_id":         factor.ID,
+		"trusted_device_id": device.ID,
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// trustedDeviceGrant issues the session at aal2 if the token is the device
+// token of a trusted device of the user. Unknown and expired tokens are
+// ignored, so that the user signs in at aal1 and is challenged as usual.
+func (a *API) trustedDeviceGrant(tx *storage.Connection, user *models.User, token string, grantParams *models.GrantParams) error {
+	if token == "" || !a.config.MFA.TrustedDevices.Enabled {
+		return nil
+	}
+
+	device, err := models.FindTrustedDeviceByToken(tx, user, token)
+	if err != nil {
+		if models.IsNotFoundError(err) {
+			return nil
+		}
+		return internalServerError("Database error finding trusted device").WithInternalError(err)
+	}
+
+	if err := device.UpdateLastUsedAt(tx); err != nil {
+		return internalServerError("Database error updating trusted device").WithInternalError(err)
+	}
+
+	grantParams.FactorID = &device.FactorID
+	grantParams.AAL = models.AAL2.String()
+	grantParams.TrustedDeviceID = &device.ID
+	return nil
+}
+
+// ListTrustedDevices returns the trusted devices of the user.
+func (a *API) ListTrustedDevices(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+
+	devices, err := models.FindTrustedDevicesByUser(a.db, user)
+	if err != nil {
+		return internalServerError("Database error finding trusted devices").WithInternalError(err)
+	}
+
+	return sendJSON(w, http.StatusOK, &TrustedDevicesResponse{
+		Devices: devices,
+	})
+}
+
+// DeleteTrustedDevice revokes a trusted device of the user. Sessions that
+// were signed in with it are not affected.
+func (a *API) DeleteTrustedDevice(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+
+	deviceID, err := uuid.FromString(chi.URLParam(r, "device_id"))
+	if err != nil {
+		return badRequestError("device_id must be an UUID")
+	}
+
+	err = a.db.Transaction(func(tx *storage.Connection) error {
+		if terr := models.DeleteTrustedDevice(tx, user, deviceID); terr != nil {
+			if models.IsNotFoundError(terr) {
+				return notFoundError(terr.Error())
+			}
+			return internalServerError("Database error deleting trusted device").WithInternalError(terr)
+		}
+
+		return models.NewAuditLogEntry(r, tx, user, models.RevokeTrustedDeviceAction, r.RemoteAddr, map[string]interface{}{
+			"trusted_device_id": deviceID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// DeleteTrustedDevices revokes all trusted devices of the user.
+func (a *API) DeleteTrustedDevices(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	user := getUser(ctx)
+
+	err := a.db.Transaction(func(tx *storage.Connection) error {
+		if terr := models.DeleteTrustedDevices(tx, user); terr != nil {
+			return internalServerError("Database error deleting trusted devices").WithInternalError(terr)
+		}
+
+		return models.NewAuditLogEntry(r, tx, user, models.RevokeTrustedDeviceAction, r.RemoteAddr, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	jwt "github.com/golang-jwt/jwt"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/gotrue/internal/api/provider"
+	"github.com/supabase/gotrue/internal/models"
+)
+
+func passwordSignInWithTrustedDevice(ts *MFATestSuite, email, password, deviceToken string) *GoTrueClaims {
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=password", "", map[string]interface{}{
+		"email":                email,
+		"password":             password,
+		"trusted_device_token": deviceToken,
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+	return parseAccessTokenClaims(ts, data.Token)
+}
+
+func parseAccessTokenClaims(ts *MFATestSuite, token string) *GoTrueClaims {
+	ctx, err := ts.API.parseJWTClaims(token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	return getClaims(ctx)
+}
+
+// trustDevice enrolls and verifies a TOTP factor in the session of the
+// token, trusting the device.
+func trustDevice(ts *MFATestSuite, token string) (*EnrollFactorResponse, *AccessTokenResponse) {
+	factor := enrollTOTPFactor(ts, token)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/challenge", factor.ID), token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+	challenge := &ChallengeFactorResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(challenge))
+
+	code, err := totp.GenerateCode(factor.TOTP.Secret, time.Now().UTC())
+	require.NoError(ts.T(), err)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factor.ID), token, map[string]interface{}{
+		"challenge_id": challenge.ID,
+		"code":         code,
+		"trust_device": true,
+	})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+	verifyResp := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(verifyResp))
+	require.NotEmpty(ts.T(), verifyResp.TrustedDeviceToken)
+	return factor, verifyResp
+}
+
+func (ts *MFATestSuite) TestTrustedDevices() {
+	trustedDevices := ts.Config.MFA.TrustedDevices
+	ts.Config.MFA.TrustedDevices.Enabled = true
+	ts.Config.MFA.TrustedDevices.Duration = time.Hour
+	defer func() {
+		ts.Config.MFA.TrustedDevices = trustedDevices
+	}()
+
+	email := "test1@example.com"
+	password := "test123"
+	token := signUp(ts, email, password).Token
+	factor, verifyResp := trustDevice(ts, token)
+
+	// signing in on the trusted device skips the challenge
+	claims := passwordSignInWithTrustedDevice(ts, email, password, verifyResp.TrustedDeviceToken)
+	require.Equal(ts.T(), models.AAL2.String(), claims.AuthenticatorAssuranceLevel)
+	methods := []string{}
+	for _, entry := range claims.AuthenticationMethodReference {
+		methods = append(methods, entry.Method)
+	}
+	require.ElementsMatch(ts.T(), []string{models.PasswordGrant.String(), models.TrustedDeviceSignIn.String()}, methods)
+
+	claims = passwordSignInWithTrustedDevice(ts, email, password, "unknown")
+	require.Equal(ts.T(), models.AAL1.String(), claims.AuthenticatorAssuranceLevel)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodGet, "/factors/trusted_devices", verifyResp.Token, nil)
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+	devices := &TrustedDevicesResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(devices))
+	require.Len(ts.T(), devices.Devices, 1)
+	require.Equal(ts.T(), factor.ID, devices.Devices[0].FactorID)
+	require.NotNil(ts.T(), devices.Devices[0].LastUsedAt)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodDelete, fmt.Sprintf("/factors/trusted_devices/%s", devices.Devices[0].ID), verifyResp.Token, nil)
+	require.Equal(ts.T(), http.StatusNoContent, w.Code)
+
+	// revoked devices are no longer trusted
+	claims = passwordSignInWithTrustedDevice(ts, email, password, verifyResp.TrustedDeviceToken)
+	require.Equal(ts.T(), models.AAL1.String(), claims.AuthenticatorAssuranceLevel)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodDelete, fmt.Sprintf("/factors/trusted_devices/%s", devices.Devices[0].ID), verifyResp.Token, nil)
+	require.Equal(ts.T(), http.StatusNotFound, w.Code)
+}
+
+func (ts *MFATestSuite) TestTrustedDevicesDisabled() {
+	token := signUp(ts, "test1@example.com", "test123").Token
+	factor := enrollTOTPFactor(ts, token)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, fmt.Sprintf("/factors/%s/verify", factor.ID), token, map[string]interface{}{
+		"challenge_id": factor.ID,
+		"code":         "123456",
+		"trust_device": true,
+	})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+}
+
+func (ts *MFATestSuite) TestTrustedDevicesIdTokenGrant() {
+	trustedDevices := ts.Config.MFA.TrustedDevices
+	keycloak := ts.Config.External.Keycloak
+	ts.Config.MFA.TrustedDevices.Enabled = true
+	ts.Config.MFA.TrustedDevices.Duration = time.Hour
+	defer func() {
+		ts.Config.MFA.TrustedDevices = trustedDevices
+		ts.Config.External.Keycloak = keycloak
+	}()
+
+	var issuer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Header().Add("Content-Type", "application/json")
+			require.NoError(ts.T(), json.NewEncoder(w).Encode(map[string]interface{}{
+				"issuer":                 issuer,
+				"authorization_endpoint": issuer + "/authorize",
+				"token_endpoint":         issuer + "/token",
+				"jwks_uri":               issuer + "/certs",
+			}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	issuer = server.URL
+
+	ts.Config.External.Keycloak.Enabled = true
+	ts.Config.External.Keycloak.URL = issuer
+	ts.Config.External.Keycloak.ClientID = []string{"testclientid"}
+
+	provider.OverrideVerifiers[issuer+"/authorize"] = func(ctx context.Context, config *oidc.Config) *oidc.IDTokenVerifier {
+		pk := idTokenPrivateKey()
+		return oidc.NewVerifier(issuer, &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{&pk.PublicKey}}, config)
+	}
+	defer delete(provider.OverrideVerifiers, issuer+"/authorize")
+
+	idToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            issuer,
+		"sub":            "keycloaktestid",
+		"aud":            "testclientid",
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"email":          "test1@example.com",
+		"email_verified": true,
+	}).SignedString(idTokenPrivateKey())
+	require.NoError(ts.T(), err)
+
+	idTokenGrant := func(deviceToken string) *AccessTokenResponse {
+		w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/token?grant_type=id_token", "", map[string]interface{}{
+			"id_token":             idToken,
+			"provider":             "keycloak",
+			"trusted_device_token": deviceToken,
+		})
+		require.Equal(ts.T(), http.StatusOK, w.Code)
+
+		data := &AccessTokenResponse{}
+		require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+		return data
+	}
+
+	_, verifyResp := trustDevice(ts, idTokenGrant("").Token)
+
+	// signing in on the trusted device skips the challenge
+	claims := parseAccessTokenClaims(ts, idTokenGrant(verifyResp.TrustedDeviceToken).Token)
+	require.Equal(ts.T(), models.AAL2.String(), claims.AuthenticatorAssuranceLevel)
+	methods := []string{}
+	for _, entry := range claims.AuthenticationMethodReference {
+		methods = append(methods, entry.Method)
+	}
+	require.ElementsMatch(ts.T(), []string{models.OAuth.String(), models.TrustedDeviceSignIn.String()}, methods)
+
+	claims = parseAccessTokenClaims(ts, idTokenGrant("unknown").Token)
+	require.Equal(ts.T(), models.AAL1.String(), claims.AuthenticatorAssuranceLevel)
+}
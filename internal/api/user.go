@@ -11,6 +11,7 @@ import (
 	"github.com/supabase/gotrue/internal/api/provider"
 	"github.com/supabase/gotrue/internal/api/sms_provider"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/models"
 	"github.com/supabase/gotrue/internal/observability"
 	"github.com/supabase/gotrue/internal/storage"
@@ -73,6 +74,12 @@ func (p *UserUpdateParams) Validate(tx *storage.Connection, user *models.User, a
 			return invalidPasswordLengthError(config.PasswordMinLength)
 		}
 
+		identifiers := passwordUserIdentifiers(user.GetEmail(), user.GetPhone(), user.UserMetaData)
+		identifiers = append(identifiers, passwordUserIdentifiers(p.Email, p.Phone, p.Data)...)
+		if err := validatePasswordPolicy(&config.PasswordPolicy, password, identifiers); err != nil {
+			return err
+		}
+
 		if user.EncryptedPassword != "" && user.Authenticate(password) {
 			return unprocessableEntityError("New password should be different from the old password.")
 		}
@@ -131,15 +138,21 @@ func (a *API) UserUpdate(w http.ResponseWriter, r *http.Request) error {
 
 	err = db.Transaction(func(tx *storage.Connection) error {
 		var terr error
+		reauthenticated := false
+		if params.Password != nil || (params.Email != "" && params.Email != user.GetEmail()) {
+			if reauthenticated, terr = a.requireRecentAuthentication(r, tx, user, session, params.Nonce); terr != nil {
+				return terr
+			}
+		}
+
 		if params.Password != nil {
-			if config.Security.UpdatePasswordRequireReauthentication {
-				now := time.Now()
+			if config.Security.UpdatePasswordRequireReauthentication && !reauthenticated {
 				// we require reauthentication if the user hasn't signed in recently in the current session
-				if session == nil || now.After(session.CreatedAt.Add(24*time.Hour)) {
+				if session == nil || !session.IsRecentlyAuthenticated(time.Now(), 24*time.Hour) {
 					if len(params.Nonce) == 0 {
 						return badRequestError("Password update requires reauthentication")
 					}
-					if terr = a.verifyReauthentication(params.Nonce, tx, config, user); terr != nil {
+					if terr = a.verifyReauthentication(r, params.Nonce, tx, config, user, session); terr != nil {
 						return terr
 					}
 				}
@@ -150,7 +163,7 @@ func (a *API) UserUpdate(w http.ResponseWriter, r *http.Request) error {
 				sessionID = &session.ID
 			}
 
-			if terr = user.UpdatePassword(tx, *params.Password, sessionID); terr != nil {
+			if terr = user.UpdatePassword(tx, crypto.NewPasswordHashParams(&config.PasswordHash), *params.Password, sessionID); terr != nil {
 				return internalServerError("Error during password storage").WithInternalError(terr)
 			}
 			if terr := models.NewAuditLogEntry(r, tx, user, models.UserUpdatePasswordAction, "", nil); terr != nil {
@@ -40,7 +40,7 @@ func (ts *UserTestSuite) SetupTest() {
 	models.TruncateAll(ts.API.db)
 
 	// Create user
-	u, err := models.NewUser("123456789", "test@example.com", "password", ts.Config.JWT.Aud, nil)
+	u, err := models.NewUser(crypto.DefaultPasswordHashParams, "123456789", "test@example.com", "password", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err, "Error creating test user model")
 	require.NoError(ts.T(), ts.API.db.Create(u), "Error saving new test user")
 }
@@ -49,7 +49,7 @@ func (ts *UserTestSuite) TestUserGet() {
 	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
 	require.NoError(ts.T(), err, "Error finding user")
 	var token string
-	token, _, err = generateAccessToken(ts.API.db, u, nil, &ts.Config.JWT)
+	token, _, err = ts.API.generateAccessToken(ts.API.db, u, nil)
 
 	require.NoError(ts.T(), err, "Error generating access token")
 
@@ -108,14 +108,14 @@ func (ts *UserTestSuite) TestUserUpdateEmail() {
 
 	for _, c := range cases {
 		ts.Run(c.desc, func() {
-			u, err := models.NewUser("", "", "", ts.Config.JWT.Aud, nil)
+			u, err := models.NewUser(crypto.DefaultPasswordHashParams, "", "", "", ts.Config.JWT.Aud, nil)
 			require.NoError(ts.T(), err, "Error creating test user model")
 			require.NoError(ts.T(), u.SetEmail(ts.API.db, c.userData["email"]), "Error setting user email")
 			require.NoError(ts.T(), u.SetPhone(ts.API.db, c.userData["phone"]), "Error setting user phone")
 			require.NoError(ts.T(), ts.API.db.Create(u), "Error saving test user")
 
 			var token string
-			token, _, err = generateAccessToken(ts.API.db, u, nil, &ts.Config.JWT)
+			token, _, err = ts.API.generateAccessToken(ts.API.db, u, nil)
 
 			require.NoError(ts.T(), err, "Error generating access token")
 
@@ -142,7 +142,7 @@ func (ts *UserTestSuite) TestUserUpdatePhoneAutoconfirmEnabled() {
 	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
 	require.NoError(ts.T(), err)
 
-	existingUser, err := models.NewUser("22222222", "", "", ts.Config.JWT.Aud, nil)
+	existingUser, err := models.NewUser(crypto.DefaultPasswordHashParams, "22222222", "", "", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.API.db.Create(existingUser))
 
@@ -179,7 +179,7 @@ func (ts *UserTestSuite) TestUserUpdatePhoneAutoconfirmEnabled() {
 	for _, c := range cases {
 		ts.Run(c.desc, func() {
 			var token string
-			token, _, err = generateAccessToken(ts.API.db, u, nil, &ts.Config.JWT)
+			token, _, err = ts.API.generateAccessToken(ts.API.db, u, nil)
 			require.NoError(ts.T(), err, "Error generating access token")
 
 			var buffer bytes.Buffer
@@ -202,10 +202,10 @@ func (ts *UserTestSuite) TestUserUpdatePassword() {
 	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
 	require.NoError(ts.T(), err)
 
-	r, err := models.GrantAuthenticatedUser(ts.API.db, u, models.GrantParams{})
+	r, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
 	require.NoError(ts.T(), err)
 
-	r2, err := models.GrantAuthenticatedUser(ts.API.db, u, models.GrantParams{})
+	r2, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
 	require.NoError(ts.T(), err)
 
 	// create a session and modify it's created_at time to simulate a session that is not recently logged in
@@ -293,7 +293,7 @@ func (ts *UserTestSuite) TestUserUpdatePassword() {
 
 			var token string
 
-			token, _, err = generateAccessToken(ts.API.db, u, c.sessionId, &ts.Config.JWT)
+			token, _, err = ts.API.generateAccessToken(ts.API.db, u, c.sessionId)
 			require.NoError(ts.T(), err)
 			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
@@ -323,7 +323,7 @@ func (ts *UserTestSuite) TestUserUpdatePasswordReauthentication() {
 	require.NoError(ts.T(), ts.API.db.Update(u), "Error updating new test user")
 
 	var token string
-	token, _, err = generateAccessToken(ts.API.db, u, nil, &ts.Config.JWT)
+	token, _, err = ts.API.generateAccessToken(ts.API.db, u, nil)
 	require.NoError(ts.T(), err)
 
 	// request for reauthentication nonce
@@ -368,6 +368,167 @@ func (ts *UserTestSuite) TestUserUpdatePasswordReauthentication() {
 	require.NotEmpty(ts.T(), u.ReauthenticationSentAt)
 }
 
+func (ts *UserTestSuite) TestUserUpdateReauthenticationMaxAge() {
+	ts.Config.Security.ReauthenticationMaxAge = time.Hour
+	defer func() {
+		ts.Config.Security.ReauthenticationMaxAge = 0
+	}()
+
+	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+
+	r, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
+	require.NoError(ts.T(), err)
+
+	// the user last authenticated in the session outside of the max age
+	authTime := time.Now().Add(-2 * time.Hour)
+	require.NoError(ts.T(), ts.API.db.RawQuery(
+		"update "+(models.Session{}).TableName()+" set auth_time = ? where id = ?",
+		authTime,
+		*r.SessionId).Exec(),
+	)
+
+	token, _, err := ts.API.generateAccessToken(ts.API.db, u, r.SessionId)
+	require.NoError(ts.T(), err)
+
+	ctx, err := ts.API.parseJWTClaims(token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), authTime.Unix(), getClaims(ctx).AuthTime)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", token, map[string]interface{}{"password": "newpassword"})
+	require.Equal(ts.T(), http.StatusForbidden, w.Code)
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", token, map[string]interface{}{"email": "new@example.com"})
+	require.Equal(ts.T(), http.StatusForbidden, w.Code)
+
+	// other changes don't require reauthentication
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", token, map[string]interface{}{"data": map[string]interface{}{"updated": true}})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/reauthenticate", token, map[string]interface{}{"password": "wrongpassword"})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPost, "/reauthenticate", token, map[string]interface{}{"password": "password"})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+	data := &AccessTokenResponse{}
+	require.NoError(ts.T(), json.NewDecoder(w.Body).Decode(data))
+
+	ctx, err = ts.API.parseJWTClaims(data.Token, httptest.NewRequest(http.MethodGet, "http://localhost/user", nil))
+	require.NoError(ts.T(), err)
+	require.Greater(ts.T(), getClaims(ctx).AuthTime, authTime.Unix())
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", data.Token, map[string]interface{}{"password": "newpassword"})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+
+	u, err = models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), u.Authenticate("newpassword"))
+}
+
+func (ts *UserTestSuite) TestUserUpdatePasswordRequireReauthenticationAuthTime() {
+	ts.Config.Security.UpdatePasswordRequireReauthentication = true
+	defer func() {
+		ts.Config.Security.UpdatePasswordRequireReauthentication = false
+	}()
+
+	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+
+	r, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
+	require.NoError(ts.T(), err)
+
+	// the session is old, but the user reauthenticated in it recently
+	require.NoError(ts.T(), ts.API.db.RawQuery(
+		"update "+(models.Session{}).TableName()+" set created_at = ?, auth_time = ? where id = ?",
+		time.Now().Add(-48*time.Hour),
+		time.Now(),
+		*r.SessionId).Exec(),
+	)
+
+	token, _, err := ts.API.generateAccessToken(ts.API.db, u, r.SessionId)
+	require.NoError(ts.T(), err)
+
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", token, map[string]interface{}{"password": "newpassword"})
+	require.Equal(ts.T(), http.StatusOK, w.Code)
+}
+
+func (ts *UserTestSuite) TestReauthenticateMaxAttempts() {
+	ts.Config.Security.ReauthenticationMaxAttempts = 2
+	defer func() {
+		ts.Config.Security.ReauthenticationMaxAttempts = 5
+	}()
+
+	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+
+	r, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
+	require.NoError(ts.T(), err)
+
+	token, _, err := ts.API.generateAccessToken(ts.API.db, u, r.SessionId)
+	require.NoError(ts.T(), err)
+
+	reauthenticate := func(password string) *httptest.ResponseRecorder {
+		return performJSONRequest(ts.T(), ts.API, http.MethodPost, "/reauthenticate", token, map[string]interface{}{"password": password})
+	}
+
+	// a correct password resets the count
+	require.Equal(ts.T(), http.StatusBadRequest, reauthenticate("wrongpassword").Code)
+	require.Equal(ts.T(), http.StatusOK, reauthenticate("password").Code)
+
+	session, err := models.FindSessionByID(ts.API.db, *r.SessionId, false)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 0, session.FailedReauthenticationAttempts)
+
+	// too many consecutive wrong passwords sign out the session
+	require.Equal(ts.T(), http.StatusBadRequest, reauthenticate("wrongpassword").Code)
+	require.Equal(ts.T(), http.StatusUnauthorized, reauthenticate("wrongpassword").Code)
+
+	_, err = models.FindSessionByID(ts.API.db, *r.SessionId, false)
+	require.True(ts.T(), models.IsNotFoundError(err))
+}
+
+func (ts *UserTestSuite) TestReauthenticateMaxAttemptsWithNonce() {
+	ts.Config.Security.ReauthenticationMaxAttempts = 2
+	ts.Config.Security.ReauthenticationMaxAge = time.Hour
+	defer func() {
+		ts.Config.Security.ReauthenticationMaxAttempts = 5
+		ts.Config.Security.ReauthenticationMaxAge = 0
+	}()
+
+	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+
+	r, err := models.GrantAuthenticatedUser(ts.API.db, ts.Config.Security.RefreshTokenHashKey, u, models.GrantParams{})
+	require.NoError(ts.T(), err)
+
+	// the user last authenticated in the session outside of the max age
+	require.NoError(ts.T(), ts.API.db.RawQuery(
+		"update "+(models.Session{}).TableName()+" set auth_time = ? where id = ?",
+		time.Now().Add(-2*time.Hour),
+		*r.SessionId).Exec(),
+	)
+
+	token, _, err := ts.API.generateAccessToken(ts.API.db, u, r.SessionId)
+	require.NoError(ts.T(), err)
+
+	// wrong nonces count both when reauthenticating and when updating the user
+	w := performJSONRequest(ts.T(), ts.API, http.MethodPost, "/reauthenticate", token, map[string]interface{}{"nonce": "123456"})
+	require.Equal(ts.T(), http.StatusBadRequest, w.Code)
+
+	session, err := models.FindSessionByID(ts.API.db, *r.SessionId, false)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 1, session.FailedReauthenticationAttempts)
+
+	w = performJSONRequest(ts.T(), ts.API, http.MethodPut, "/user", token, map[string]interface{}{"password": "newpassword", "nonce": "123456"})
+	require.Equal(ts.T(), http.StatusUnauthorized, w.Code)
+
+	_, err = models.FindSessionByID(ts.API.db, *r.SessionId, false)
+	require.True(ts.T(), models.IsNotFoundError(err))
+
+	u, err = models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), u.Authenticate("password"))
+}
+
 func (ts *UserTestSuite) TestUserUpdatePasswordLogoutOtherSessions() {
 	ts.Config.Security.UpdatePasswordRequireReauthentication = false
 	u, err := models.FindUserByEmailAndAudience(ts.API.db, "test@example.com", ts.Config.JWT.Aud)
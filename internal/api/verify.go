@@ -178,6 +178,7 @@ func (a *API) verifyGet(w http.ResponseWriter, r *http.Request, params *VerifyPa
 			return terr
 		}
 		if isImplicitFlow(flowType) {
+			grantParams.FillGrantParams(r)
 			token, terr = a.issueRefreshToken(ctx, tx, user, models.OTP, grantParams)
 
 			if terr != nil {
@@ -266,6 +267,7 @@ func (a *API) verifyPost(w http.ResponseWriter, r *http.Request, params *VerifyP
 		if terr != nil {
 			return terr
 		}
+		grantParams.FillGrantParams(r)
 		token, terr = a.issueRefreshToken(ctx, tx, user, models.OTP, grantParams)
 		if terr != nil {
 			return terr
@@ -301,7 +303,7 @@ func (a *API) signupVerify(r *http.Request, ctx context.Context, conn *storage.C
 				if err != nil {
 					internalServerError("error creating user").WithInternalError(err)
 				}
-				if terr = user.UpdatePassword(tx, password, nil); terr != nil {
+				if terr = user.UpdatePassword(tx, crypto.NewPasswordHashParams(&config.PasswordHash), password, nil); terr != nil {
 					return internalServerError("Error storing password").WithInternalError(terr)
 				}
 			}
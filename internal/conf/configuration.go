@@ -2,10 +2,18 @@ package conf
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
@@ -83,6 +91,148 @@ type JWTConfiguration struct {
 	DefaultGroupName string   `json:"default_group_name" split_words:"true"`
 	Issuer           string   `json:"issuer"`
 	KeyID            string   `json:"key_id" split_words:"true"`
+	Algorithm        string   `json:"algorithm" default:"HS256"`
+	PrivateKey       string   `json:"-" split_words:"true"`
+
+	// SigningKey is the parsed PrivateKey, populated by Validate when
+	// an asymmetric Algorithm is configured.
+	SigningKey crypto.Signer `json:"-"`
+
+	// RetiredKeys are previous signing keys that are still accepted on
+	// incoming access tokens until their grace period ends.
+	RetiredKeys JWTRetiredKeys `json:"-" split_words:"true"`
+}
+
+// Supported JWT signing algorithms.
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmES256 = "ES256"
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+// IsAsymmetric returns true when access tokens are signed with a private key
+// instead of the shared Secret.
+func (c *JWTConfiguration) IsAsymmetric() bool {
+	return c.Algorithm != "" && c.Algorithm != JWTAlgorithmHS256
+}
+
+// RetiredKey returns the retired key with the provided kid, or nil if there
+// is no such key or its grace period has ended.
+func (c *JWTConfiguration) RetiredKey(kid string, now time.Time) *JWTRetiredKey {
+	for i := range c.RetiredKeys {
+		key := &c.RetiredKeys[i]
+
+		if key.KeyID == kid && !key.IsExpired(now) {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func (c *JWTConfiguration) Validate() error {
+	if err := c.validateSigningKey(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+
+	for i := range c.RetiredKeys {
+		key := &c.RetiredKeys[i]
+
+		if err := key.Validate(); err != nil {
+			return err
+		}
+
+		if key.KeyID == c.KeyID || seen[key.KeyID] {
+			return fmt.Errorf("jwt: kid %q is used by more than one key", key.KeyID)
+		}
+
+		seen[key.KeyID] = true
+	}
+
+	return nil
+}
+
+func (c *JWTConfiguration) validateSigningKey() error {
+	switch c.Algorithm {
+	case "", JWTAlgorithmHS256:
+		return nil
+
+	case JWTAlgorithmRS256, JWTAlgorithmES256, JWTAlgorithmEdDSA:
+		// asymmetric algorithms, parse the private key below
+
+	default:
+		return fmt.Errorf("jwt: unsupported signing algorithm %q", c.Algorithm)
+	}
+
+	if c.PrivateKey == "" {
+		return fmt.Errorf("jwt: private key is required for the %s algorithm", c.Algorithm)
+	}
+
+	signingKey, err := parseJWTSigningKey(c.Algorithm, c.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	c.SigningKey = signingKey
+
+	return nil
+}
+
+// parseJWTSigningKey decodes a Base64 encoded PKCS#8 private key and checks
+// that it can be used with the provided algorithm.
+func parseJWTSigningKey(algorithm, encoded string) (crypto.Signer, error) {
+	bytes, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("jwt: private key not in standard Base64 format")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(bytes)
+	if err != nil {
+		return nil, errors.New("jwt: private key not in PKCS#8 format")
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if algorithm != JWTAlgorithmRS256 {
+			break
+		}
+
+		if err := key.Validate(); err != nil {
+			return nil, errors.New("jwt: RSA private key is not valid")
+		}
+
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("jwt: RSA private key must be at least RSA 2048")
+		}
+
+		return key, nil
+
+	case *ecdsa.PrivateKey:
+		if algorithm != JWTAlgorithmES256 {
+			break
+		}
+
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("jwt: ES256 requires a private key on the P-256 curve")
+		}
+
+		return key, nil
+
+	case ed25519.PrivateKey:
+		if algorithm != JWTAlgorithmEdDSA {
+			break
+		}
+
+		return key, nil
+
+	default:
+		return nil, fmt.Errorf("jwt: unsupported private key type %T", parsed)
+	}
+
+	return nil, fmt.Errorf("jwt: private key of type %T cannot be used with the %s algorithm", parsed, algorithm)
 }
 
 // MFAConfiguration holds all the MFA related Configuration
@@ -92,6 +242,273 @@ type MFAConfiguration struct {
 	RateLimitChallengeAndVerify float64 `split_words:"true" default:"15"`
 	MaxEnrolledFactors          float64 `split_words:"true" default:"10"`
 	MaxVerifiedFactors          int     `split_words:"true" default:"10"`
+
+	// MaxVerificationAttempts is the number of consecutive failed attempts
+	// to verify a factor after which it is locked for
+	// VerificationLockoutDuration. 0 disables the lockout.
+	MaxVerificationAttempts     int           `split_words:"true" default:"5"`
+	VerificationLockoutDuration time.Duration `split_words:"true" default:"15m"`
+
+	// SecretEncryptionKeys are the base64 encoded 256-bit keys factor
+	// secrets are encrypted with, by key ID. Retired keys need to be kept
+	// until the secrets have been migrated to the active key.
+	SecretEncryptionKeys map[string]string `json:"-" split_words:"true"`
+
+	// SecretEncryptionKeyID is the ID of the key new factor secrets are
+	// encrypted with. Secrets are stored unencrypted when it is empty.
+	SecretEncryptionKeyID string `json:"-" split_words:"true"`
+
+	WebAuthn WebAuthnConfiguration    `json:"webauthn" envconfig:"WEBAUTHN"`
+	Phone    PhoneFactorConfiguration `json:"phone"`
+
+	Enforcement    MFAEnforcementConfiguration `json:"enforcement"`
+	TrustedDevices TrustedDevicesConfiguration `json:"trusted_devices" split_words:"true"`
+}
+
+var secretEncryptionKeyIDPattern = regexp.MustCompile("^[a-zA-Z0-9.-]+$")
+
+// GetSecretEncryptionKeys returns the decoded factor secret encryption keys.
+func (c *MFAConfiguration) GetSecretEncryptionKeys() (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(c.SecretEncryptionKeys))
+	for id, encoded := range c.SecretEncryptionKeys {
+		if !secretEncryptionKeyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("mfa: secret encryption key ID %q may only contain letters, digits, dots and dashes", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("mfa: secret encryption key %q is not valid base64: %w", id, err)
+		}
+
+		if len(key) != 32 {
+			return nil, fmt.Errorf("mfa: secret encryption key %q must be 32 bytes long", id)
+		}
+
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+func (c *MFAConfiguration) Validate() error {
+	if c.MaxVerificationAttempts < 0 {
+		return errors.New("mfa: max verification attempts must not be negative")
+	}
+
+	if c.Enforcement.GracePeriod < 0 {
+		return errors.New("mfa: enforcement grace period must not be negative")
+	}
+
+	if c.TrustedDevices.Enabled && c.TrustedDevices.Duration <= 0 {
+		return errors.New("mfa: trusted device duration must be positive")
+	}
+
+	keys, err := c.GetSecretEncryptionKeys()
+	if err != nil {
+		return err
+	}
+
+	if c.SecretEncryptionKeyID == "" {
+		if len(keys) > 0 {
+			return errors.New("mfa: secret encryption key ID is required when secret encryption keys are set")
+		}
+		return nil
+	}
+
+	if _, ok := keys[c.SecretEncryptionKeyID]; !ok {
+		return fmt.Errorf("mfa: secret encryption key %q is not configured", c.SecretEncryptionKeyID)
+	}
+
+	return nil
+}
+
+// MFAEnforcementConfiguration holds the policy that requires users to use
+// MFA, in addition to the users an admin requires to.
+type MFAEnforcementConfiguration struct {
+	// Roles and Audiences select the users that are required to use MFA.
+	Roles     []string `json:"roles"`
+	Audiences []string `json:"audiences"`
+
+	// GracePeriod is how long users have to enroll a factor after they
+	// became required to use MFA, before their sessions are restricted.
+	GracePeriod time.Duration `json:"grace_period" split_words:"true"`
+}
+
+// Applies returns whether the policy requires users with the role and
+// audience to use MFA.
+func (c *MFAEnforcementConfiguration) Applies(role, aud string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	for _, a := range c.Audiences {
+		if a == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustedDevicesConfiguration holds the configuration of trusted devices,
+// which skip the factor challenge when the user signs in on them.
+type TrustedDevicesConfiguration struct {
+	Enabled bool `json:"enabled"`
+
+	// Duration is how long a device stays trusted after the factor was
+	// verified on it.
+	Duration time.Duration `json:"duration" default:"720h"`
+}
+
+// PhoneFactorConfiguration holds the configuration of the phone factor,
+// whose codes are sent with the configured SMS provider.
+type PhoneFactorConfiguration struct {
+	Enabled bool `json:"enabled"`
+}
+
+// WebAuthnConfiguration holds the configuration of the webauthn factor and
+// of passkey login.
+type WebAuthnConfiguration struct {
+	Enabled bool `json:"enabled"`
+
+	// PasskeyLoginEnabled allows users to sign in without a password
+	// using a webauthn factor that is a passkey.
+	PasskeyLoginEnabled bool `json:"passkey_login_enabled" split_words:"true"`
+
+	// RPID is the domain credentials are scoped to. Defaults to the host
+	// of SITE_URL.
+	RPID string `json:"rp_id" envconfig:"RP_ID"`
+
+	// RPDisplayName is shown to users when they register a credential.
+	// Defaults to the RP ID.
+	RPDisplayName string `json:"rp_display_name" envconfig:"RP_DISPLAY_NAME"`
+
+	// RPOrigins are the origins allowed to use the credentials. Defaults
+	// to the origin of SITE_URL.
+	RPOrigins []string `json:"rp_origins" envconfig:"RP_ORIGINS"`
+}
+
+func (c *WebAuthnConfiguration) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.RPID == "" {
+		return errors.New("mfa: webauthn RP ID is required")
+	}
+
+	for _, origin := range c.RPOrigins {
+		if _, err := url.ParseRequestURI(origin); err != nil {
+			return fmt.Errorf("mfa: webauthn RP origin %q is not valid: %w", origin, err)
+		}
+	}
+
+	return nil
+}
+
+// OIDCConfiguration holds the configuration for acting as an OpenID Connect
+// provider.
+type OIDCConfiguration struct {
+	Enabled bool `json:"enabled"`
+}
+
+// OAuthServerConfiguration holds the configuration for acting as an OAuth 2.0
+// authorization server for third-party clients.
+type OAuthServerConfiguration struct {
+	Enabled bool `json:"enabled"`
+
+	// ConsentURL is the page on the site that asks the user to approve
+	// an authorization request. Defaults to SITE_URL/oauth/consent.
+	ConsentURL string `json:"consent_url" split_words:"true"`
+}
+
+func (c *OAuthServerConfiguration) Validate() error {
+	if c.Enabled && c.ConsentURL != "" {
+		if _, err := url.ParseRequestURI(c.ConsentURL); err != nil {
+			return fmt.Errorf("oauth_server: consent URL is not valid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ServiceAccountsConfiguration holds the configuration for service accounts,
+// which obtain access tokens with the client_credentials grant.
+type ServiceAccountsConfiguration struct {
+	Enabled bool `json:"enabled"`
+
+	// TokenExp is the lifetime of access tokens issued to service
+	// accounts, in seconds. Defaults to 300.
+	TokenExp int `json:"token_exp" split_words:"true"`
+}
+
+// DeviceAuthorizationConfiguration holds the configuration for the OAuth 2.0
+// Device Authorization Grant (RFC 8628), used to log in on devices such as
+// CLIs and TVs.
+type DeviceAuthorizationConfiguration struct {
+	Enabled bool `json:"enabled"`
+
+	// VerificationURL is the page on the site where users enter the
+	// user code shown on the device. Defaults to SITE_URL/device.
+	VerificationURL string `json:"verification_url" split_words:"true"`
+
+	// ExpiryDuration is how long the device has to be approved. Defaults
+	// to 10 minutes.
+	ExpiryDuration time.Duration `json:"expiry_duration" split_words:"true"`
+
+	// PollingInterval is the minimum time the device has to wait between
+	// token requests. Defaults to 5 seconds.
+	PollingInterval time.Duration `json:"polling_interval" split_words:"true"`
+}
+
+func (c *DeviceAuthorizationConfiguration) Validate() error {
+	if c.Enabled && c.VerificationURL != "" {
+		if _, err := url.ParseRequestURI(c.VerificationURL); err != nil {
+			return fmt.Errorf("device_authorization: verification URL is not valid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// HookConfiguration holds the configuration of HTTP hooks that are called
+// while GoTrue is handling a request and can change its outcome.
+type HookConfiguration struct {
+	CustomAccessToken CustomAccessTokenHookConfiguration `json:"custom_access_token" split_words:"true"`
+}
+
+func (c *HookConfiguration) Validate() error {
+	return c.CustomAccessToken.Validate()
+}
+
+// CustomAccessTokenHookConfiguration configures the hook that is called
+// before every access token is signed and can add or override claims.
+type CustomAccessTokenHookConfiguration struct {
+	URL        string `json:"url"`
+	Secret     string `json:"secret"`
+	Retries    int    `json:"retries"`
+	TimeoutSec int    `json:"timeout_sec" split_words:"true"`
+}
+
+// IsEnabled returns true if the hook is configured.
+func (c *CustomAccessTokenHookConfiguration) IsEnabled() bool {
+	return c.URL != ""
+}
+
+func (c *CustomAccessTokenHookConfiguration) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	u, err := url.ParseRequestURI(c.URL)
+	if err != nil {
+		return fmt.Errorf("hook: custom access token URL is not valid: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("hook: custom access token URL must use http or https")
+	}
+
+	return nil
 }
 
 type APIConfiguration struct {
@@ -147,6 +564,15 @@ type GlobalConfiguration struct {
 	} `json:"cookies"`
 	SAML SAMLConfiguration `json:"saml"`
 	CORS CORSConfiguration `json:"cors"`
+	OIDC OIDCConfiguration `json:"oidc"`
+
+	OAuthServer OAuthServerConfiguration `json:"oauth_server" envconfig:"OAUTH_SERVER"`
+	Hook        HookConfiguration        `json:"hook"`
+
+	ServiceAccounts     ServiceAccountsConfiguration     `json:"service_accounts" split_words:"true"`
+	DeviceAuthorization DeviceAuthorizationConfiguration `json:"device_authorization" split_words:"true"`
+	PasswordHash        PasswordHashConfiguration        `json:"password_hash" split_words:"true"`
+	PasswordPolicy      PasswordPolicyConfiguration      `json:"password_policy" split_words:"true"`
 }
 
 type CORSConfiguration struct {
@@ -181,6 +607,8 @@ type EmailContentConfiguration struct {
 	EmailChange      string `json:"email_change" split_words:"true"`
 	MagicLink        string `json:"magic_link" split_words:"true"`
 	Reauthentication string `json:"reauthentication"`
+
+	RefreshTokenReuse string `json:"refresh_token_reuse" split_words:"true"`
 }
 
 type ProviderConfiguration struct {
@@ -326,9 +754,148 @@ type SecurityConfiguration struct {
 	RefreshTokenRotationEnabled           bool                 `json:"refresh_token_rotation_enabled" split_words:"true" default:"true"`
 	RefreshTokenReuseInterval             int                  `json:"refresh_token_reuse_interval" split_words:"true"`
 	UpdatePasswordRequireReauthentication bool                 `json:"update_password_require_reauthentication" split_words:"true"`
+
+	// RefreshTokenReuseNotifyUser and RefreshTokenReuseRevokeSessions
+	// control the response to the reuse of a revoked refresh token.
+	RefreshTokenReuseNotifyUser     bool `json:"refresh_token_reuse_notify_user" split_words:"true"`
+	RefreshTokenReuseRevokeSessions bool `json:"refresh_token_reuse_revoke_sessions" split_words:"true"`
+
+	// RefreshTokenHashKey is the key refresh tokens are hashed with before
+	// they are stored. It must not be the JWT secret. When it is empty,
+	// refresh tokens are stored in plaintext. Changing or removing it
+	// invalidates all hashed refresh tokens.
+	RefreshTokenHashKey string `json:"refresh_token_hash_key" split_words:"true"`
+
+	// ReauthenticationMaxAge is how long ago the user may have last
+	// actively authenticated in the session to change their password or
+	// email without reauthenticating. Zero disables the check.
+	ReauthenticationMaxAge time.Duration `json:"reauthentication_max_age" split_words:"true"`
+
+	// ReauthenticationMaxAttempts is the number of consecutive wrong
+	// passwords after which reauthenticating signs out the session. 0
+	// disables the limit.
+	ReauthenticationMaxAttempts int `json:"reauthentication_max_attempts" split_words:"true" default:"5"`
+}
+
+// PasswordHashConfiguration holds the algorithm and parameters new password
+// hashes are generated with. Passwords hashed otherwise are hashed again
+// when the user signs in with them.
+type PasswordHashConfiguration struct {
+	Algorithm  string `json:"algorithm" default:"bcrypt"`
+	BcryptCost int    `json:"bcrypt_cost" split_words:"true" default:"10"`
+
+	// Argon2Memory is the memory used by Argon2id in KiB.
+	Argon2Memory  uint32 `json:"argon2_memory" split_words:"true" default:"19456"`
+	Argon2Time    uint32 `json:"argon2_time" split_words:"true" default:"2"`
+	Argon2Threads uint8  `json:"argon2_threads" split_words:"true" default:"1"`
+
+	// ScryptN is the CPU/memory cost of scrypt, a power of two.
+	ScryptN int `json:"scrypt_n" split_words:"true" default:"32768"`
+	ScryptR int `json:"scrypt_r" split_words:"true" default:"8"`
+	ScryptP int `json:"scrypt_p" split_words:"true" default:"1"`
+}
+
+func (c *PasswordHashConfiguration) Validate() error {
+	switch c.Algorithm {
+	case "bcrypt":
+		if c.BcryptCost < 4 || c.BcryptCost > 31 {
+			return errors.New("bcrypt cost must be between 4 and 31")
+		}
+
+	case "argon2id":
+		if c.Argon2Time < 1 || c.Argon2Threads < 1 {
+			return errors.New("argon2 time and threads must be at least 1")
+		}
+		if c.Argon2Memory < 8*uint32(c.Argon2Threads) {
+			return errors.New("argon2 memory must be at least 8 KiB per thread")
+		}
+
+	case "scrypt":
+		if c.ScryptN < 2 || c.ScryptN&(c.ScryptN-1) != 0 {
+			return errors.New("scrypt N must be a power of two greater than 1")
+		}
+		if c.ScryptR < 1 || c.ScryptP < 1 || c.ScryptR*c.ScryptP >= 1<<30 {
+			return errors.New("scrypt r and p must be at least 1 and r * p must be less than 2^30")
+		}
+
+	default:
+		return fmt.Errorf("unsupported password hash algorithm %q, must be one of bcrypt, argon2id or scrypt", c.Algorithm)
+	}
+
+	return nil
+}
+
+// PasswordPolicyConfiguration holds the requirements passwords chosen by
+// users have to satisfy in addition to PasswordMinLength.
+type PasswordPolicyConfiguration struct {
+	// RequiredCharacters lists the character classes a password has to
+	// contain at least one character of: lowercase, uppercase, digits and
+	// symbols.
+	RequiredCharacters []string `json:"required_characters" split_words:"true"`
+
+	// RejectUserIdentifiers rejects passwords that contain the email
+	// address, phone number or username of the user.
+	RejectUserIdentifiers bool `json:"reject_user_identifiers" split_words:"true"`
+
+	// MinEntropy is the estimated entropy in bits a password needs to
+	// have. Zero disables the check.
+	MinEntropy float64 `json:"min_entropy" split_words:"true"`
+
+	// LeakedPasswordsPath is a directory with the SHA-1 hashes of leaked
+	// passwords in the Have I Been Pwned range format: a <prefix>.txt file
+	// per first 5 hexadecimal characters of the hashes, with a
+	// <suffix>:<count> line per hash. Passwords found in it are rejected.
+	LeakedPasswordsPath string `json:"leaked_passwords_path" split_words:"true"`
+
+	// FlagLeakedPasswordsOnLogin marks the token response of users signing
+	// in with a leaked password, so that clients can ask them to change it.
+	FlagLeakedPasswordsOnLogin bool `json:"flag_leaked_passwords_on_login" split_words:"true"`
+}
+
+// PasswordCharacterClasses are the character classes that can be required
+// by the password policy.
+var PasswordCharacterClasses = []string{"lowercase", "uppercase", "digits", "symbols"}
+
+func (c *PasswordPolicyConfiguration) Validate() error {
+	for _, class := range c.RequiredCharacters {
+		found := false
+		for _, supported := range PasswordCharacterClasses {
+			if class == supported {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unsupported password character class %q, must be one of %s", class, strings.Join(PasswordCharacterClasses, ", "))
+		}
+	}
+
+	if c.MinEntropy < 0 {
+		return errors.New("password min entropy must not be negative")
+	}
+
+	if c.LeakedPasswordsPath != "" {
+		info, err := os.Stat(c.LeakedPasswordsPath)
+		if err != nil {
+			return fmt.Errorf("leaked passwords path: %w", err)
+		}
+		if !info.IsDir() {
+			return errors.New("leaked passwords path must be a directory")
+		}
+	} else if c.FlagLeakedPasswordsOnLogin {
+		return errors.New("flagging leaked passwords on login requires a leaked passwords path")
+	}
+
+	return nil
 }
 
 func (c *SecurityConfiguration) Validate() error {
+	if c.ReauthenticationMaxAge < 0 {
+		return errors.New("reauthentication max age must not be negative")
+	}
+	if c.ReauthenticationMaxAttempts < 0 {
+		return errors.New("reauthentication max attempts must not be negative")
+	}
 	return c.Captcha.Validate()
 }
 
@@ -417,6 +984,44 @@ func (config *GlobalConfiguration) ApplyDefaults() error {
 		config.JWT.Exp = 3600
 	}
 
+	if config.ServiceAccounts.TokenExp == 0 {
+		config.ServiceAccounts.TokenExp = 300
+	}
+
+	if config.DeviceAuthorization.VerificationURL == "" {
+		config.DeviceAuthorization.VerificationURL = strings.TrimSuffix(config.SiteURL, "/") + "/device"
+	}
+
+	if config.DeviceAuthorization.ExpiryDuration == 0 {
+		config.DeviceAuthorization.ExpiryDuration = 10 * time.Minute
+	}
+
+	if config.DeviceAuthorization.PollingInterval == 0 {
+		config.DeviceAuthorization.PollingInterval = 5 * time.Second
+	}
+
+	if siteURL, err := url.Parse(config.SiteURL); err == nil && siteURL.Host != "" {
+		if config.MFA.WebAuthn.RPID == "" {
+			config.MFA.WebAuthn.RPID = siteURL.Hostname()
+		}
+
+		if len(config.MFA.WebAuthn.RPOrigins) == 0 {
+			config.MFA.WebAuthn.RPOrigins = []string{siteURL.Scheme + "://" + siteURL.Host}
+		}
+	}
+
+	if config.MFA.WebAuthn.RPDisplayName == "" {
+		config.MFA.WebAuthn.RPDisplayName = config.MFA.WebAuthn.RPID
+	}
+
+	if config.OAuthServer.ConsentURL == "" {
+		config.OAuthServer.ConsentURL = strings.TrimSuffix(config.SiteURL, "/") + "/oauth/consent"
+	}
+
+	if config.OIDC.Enabled && config.JWT.Issuer == "" {
+		config.JWT.Issuer = config.API.ExternalURL
+	}
+
 	if config.Mailer.URLPaths.Invite == "" {
 		config.Mailer.URLPaths.Invite = "/verify"
 	}
@@ -511,11 +1116,19 @@ func (c *GlobalConfiguration) Validate() error {
 	}{
 		&c.API,
 		&c.DB,
+		&c.JWT,
 		&c.Tracing,
 		&c.Metrics,
 		&c.SMTP,
 		&c.SAML,
 		&c.Security,
+		&c.PasswordHash,
+		&c.PasswordPolicy,
+		&c.OAuthServer,
+		&c.Hook,
+		&c.DeviceAuthorization,
+		&c.MFA,
+		&c.MFA.WebAuthn,
 	}
 
 	for _, validatable := range validatables {
@@ -524,6 +1137,14 @@ func (c *GlobalConfiguration) Validate() error {
 		}
 	}
 
+	if c.Security.RefreshTokenHashKey != "" && c.Security.RefreshTokenHashKey == c.JWT.Secret {
+		return errors.New("refresh token hash key must not be the JWT secret")
+	}
+
+	if c.OIDC.Enabled && !c.JWT.IsAsymmetric() {
+		return errors.New("oidc: an asymmetric JWT algorithm is required to issue ID tokens")
+	}
+
 	return nil
 }
 
@@ -20,9 +20,33 @@ func TestGlobal(t *testing.T) {
 	os.Setenv("GOTRUE_OPERATOR_TOKEN", "token")
 	os.Setenv("GOTRUE_API_REQUEST_ID_HEADER", "X-Request-ID")
 	os.Setenv("GOTRUE_JWT_SECRET", "secret")
+	os.Setenv("GOTRUE_SECURITY_REFRESH_TOKEN_HASH_KEY", "refreshtokenhashkey")
 	os.Setenv("API_EXTERNAL_URL", "http://localhost:9999")
 	gc, err := LoadGlobal("")
 	require.NoError(t, err)
 	require.NotNil(t, gc)
 	assert.Equal(t, "X-Request-ID", gc.API.RequestIDHeader)
 }
+
+func TestRefreshTokenHashKey(t *testing.T) {
+	os.Setenv("GOTRUE_SITE_URL", "http://localhost:8080")
+	os.Setenv("GOTRUE_DB_DRIVER", "postgres")
+	os.Setenv("GOTRUE_DB_DATABASE_URL", "fake")
+	os.Setenv("GOTRUE_JWT_SECRET", "secret")
+	os.Setenv("API_EXTERNAL_URL", "http://localhost:9999")
+	defer os.Unsetenv("GOTRUE_SECURITY_REFRESH_TOKEN_HASH_KEY")
+
+	// refresh tokens are stored in plaintext until a key is configured
+	os.Unsetenv("GOTRUE_SECURITY_REFRESH_TOKEN_HASH_KEY")
+	_, err := LoadGlobal("")
+	require.NoError(t, err)
+
+	// the JWT secret is not a dedicated key
+	os.Setenv("GOTRUE_SECURITY_REFRESH_TOKEN_HASH_KEY", "secret")
+	_, err = LoadGlobal("")
+	require.Error(t, err)
+
+	os.Setenv("GOTRUE_SECURITY_REFRESH_TOKEN_HASH_KEY", "refreshtokenhashkey")
+	_, err = LoadGlobal("")
+	require.NoError(t, err)
+}
@@ -0,0 +1,157 @@
+package conf
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JWTRetiredKey is a key that is no longer used to sign access tokens but is
+// still accepted when verifying them, until ExpiresAt. This allows the
+// signing key to be rotated without invalidating every issued token at once.
+type JWTRetiredKey struct {
+	KeyID     string    `json:"kid"`
+	Algorithm string    `json:"alg"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Secret is the shared secret of a retired HS256 key.
+	Secret string `json:"secret,omitempty"`
+
+	// Key is the Base64 encoded PKIX public key, or PKCS#8 private key,
+	// of a retired asymmetric key.
+	Key string `json:"key,omitempty"`
+
+	// PublicKey is the parsed Key, populated by Validate.
+	PublicKey crypto.PublicKey `json:"-"`
+}
+
+// IsExpired returns true when the grace period of the key has ended.
+func (k *JWTRetiredKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// VerificationKey returns the key used to verify tokens signed by this key.
+func (k *JWTRetiredKey) VerificationKey() interface{} {
+	if k.Algorithm == JWTAlgorithmHS256 {
+		return []byte(k.Secret)
+	}
+
+	return k.PublicKey
+}
+
+func (k *JWTRetiredKey) Validate() error {
+	if k.KeyID == "" {
+		return errors.New("jwt: retired keys must have a kid")
+	}
+
+	if k.ExpiresAt.IsZero() {
+		return fmt.Errorf("jwt: retired key %q must have an expires_at", k.KeyID)
+	}
+
+	switch k.Algorithm {
+	case JWTAlgorithmHS256:
+		if k.Secret == "" {
+			return fmt.Errorf("jwt: retired key %q requires a secret", k.KeyID)
+		}
+
+		return nil
+
+	case JWTAlgorithmRS256, JWTAlgorithmES256, JWTAlgorithmEdDSA:
+		if k.Key == "" {
+			return fmt.Errorf("jwt: retired key %q requires a key", k.KeyID)
+		}
+
+	default:
+		return fmt.Errorf("jwt: retired key %q has unsupported signing algorithm %q", k.KeyID, k.Algorithm)
+	}
+
+	publicKey, err := parseJWTVerificationKey(k.Algorithm, k.Key)
+	if err != nil {
+		return fmt.Errorf("jwt: retired key %q: %w", k.KeyID, err)
+	}
+
+	k.PublicKey = publicKey
+
+	return nil
+}
+
+// JWTRetiredKeys is decoded from a JSON array of retired keys, e.g.
+// GOTRUE_JWT_RETIRED_KEYS='[{"kid":"2023-01","alg":"HS256","secret":"...","expires_at":"2023-02-01T00:00:00Z"}]'
+type JWTRetiredKeys []JWTRetiredKey
+
+// Decode implements envconfig.Decoder.
+func (k *JWTRetiredKeys) Decode(value string) error {
+	if value == "" {
+		*k = nil
+		return nil
+	}
+
+	var keys []JWTRetiredKey
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return fmt.Errorf("jwt: retired keys are not a valid JSON array: %w", err)
+	}
+
+	*k = keys
+
+	return nil
+}
+
+// parseJWTVerificationKey decodes a Base64 encoded PKIX public key, or a
+// PKCS#8 private key, and checks that it can be used with the provided
+// algorithm.
+func parseJWTVerificationKey(algorithm, encoded string) (crypto.PublicKey, error) {
+	bytes, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("key not in standard Base64 format")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(bytes)
+	if err != nil {
+		// fall back to a private key, as that's what was configured
+		// as JWT_PRIVATE_KEY before the rotation
+		signer, perr := parseJWTSigningKey(algorithm, encoded)
+		if perr != nil {
+			return nil, errors.New("key not in PKIX or PKCS#8 format")
+		}
+
+		return signer.Public(), nil
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		if algorithm == JWTAlgorithmRS256 {
+			if key.N.BitLen() < 2048 {
+				return nil, errors.New("RSA public key must be at least RSA 2048")
+			}
+
+			return key, nil
+		}
+
+	case *ecdsa.PublicKey:
+		if algorithm == JWTAlgorithmES256 {
+			if key.Curve != elliptic.P256() {
+				return nil, errors.New("ES256 requires a public key on the P-256 curve")
+			}
+
+			return key, nil
+		}
+
+	case ed25519.PublicKey:
+		if algorithm == JWTAlgorithmEdDSA {
+			return key, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", parsed)
+	}
+
+	return nil, fmt.Errorf("public key of type %T cannot be used with the %s algorithm", parsed, algorithm)
+}
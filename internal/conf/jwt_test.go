@@ -0,0 +1,171 @@
+package conf
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	tst "testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encodePKCS8(t *tst.T, key interface{}) string {
+	bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(bytes)
+}
+
+func TestJWTConfigurationValidate(t *tst.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	invalidExamples := []*JWTConfiguration{
+		{
+			Algorithm: "HS512",
+		},
+		{
+			Algorithm: JWTAlgorithmRS256,
+		},
+		{
+			Algorithm:  JWTAlgorithmRS256,
+			PrivateKey: "InvalidBase64!",
+		},
+		{
+			Algorithm:  JWTAlgorithmRS256,
+			PrivateKey: base64.StdEncoding.EncodeToString([]byte("not PKCS#8")),
+		},
+		{
+			Algorithm:  JWTAlgorithmRS256,
+			PrivateKey: encodePKCS8(t, ecKey),
+		},
+		{
+			Algorithm:  JWTAlgorithmES256,
+			PrivateKey: encodePKCS8(t, p384Key),
+		},
+		{
+			Algorithm:  JWTAlgorithmEdDSA,
+			PrivateKey: encodePKCS8(t, rsaKey),
+		},
+	}
+
+	for i, example := range invalidExamples {
+		err := example.Validate()
+		require.Error(t, err, "Invalid example %d was regarded as valid", i)
+	}
+
+	validExamples := []*JWTConfiguration{
+		{
+			Secret: "secret",
+		},
+		{
+			Algorithm: JWTAlgorithmHS256,
+			Secret:    "secret",
+		},
+		{
+			Algorithm:  JWTAlgorithmRS256,
+			PrivateKey: encodePKCS8(t, rsaKey),
+		},
+		{
+			Algorithm:  JWTAlgorithmES256,
+			PrivateKey: encodePKCS8(t, ecKey),
+		},
+		{
+			Algorithm:  JWTAlgorithmEdDSA,
+			PrivateKey: encodePKCS8(t, edKey),
+		},
+	}
+
+	for i, example := range validExamples {
+		err := example.Validate()
+		require.NoError(t, err, "Valid example %d was regarded as invalid", i)
+
+		if example.IsAsymmetric() {
+			require.NotNil(t, example.SigningKey, "Valid example %d has no signing key", i)
+		}
+	}
+}
+
+func TestJWTRetiredKeysValidate(t *tst.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	publicKey, err := x509.MarshalPKIXPublicKey(ecKey.Public())
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	invalidExamples := []JWTRetiredKeys{
+		{
+			{Algorithm: JWTAlgorithmHS256, Secret: "secret", ExpiresAt: expiresAt},
+		},
+		{
+			{KeyID: "old", Algorithm: JWTAlgorithmHS256, Secret: "secret"},
+		},
+		{
+			{KeyID: "old", Algorithm: JWTAlgorithmHS256, ExpiresAt: expiresAt},
+		},
+		{
+			{KeyID: "old", Algorithm: JWTAlgorithmRS256, Key: base64.StdEncoding.EncodeToString(publicKey), ExpiresAt: expiresAt},
+		},
+		{
+			{KeyID: "current", Algorithm: JWTAlgorithmHS256, Secret: "secret", ExpiresAt: expiresAt},
+		},
+		{
+			{KeyID: "old", Algorithm: JWTAlgorithmHS256, Secret: "secret", ExpiresAt: expiresAt},
+			{KeyID: "old", Algorithm: JWTAlgorithmHS256, Secret: "other", ExpiresAt: expiresAt},
+		},
+	}
+
+	for i, example := range invalidExamples {
+		config := &JWTConfiguration{Secret: "secret", KeyID: "current", RetiredKeys: example}
+		require.Error(t, config.Validate(), "Invalid example %d was regarded as valid", i)
+	}
+
+	config := &JWTConfiguration{
+		Secret: "secret",
+		KeyID:  "current",
+		RetiredKeys: JWTRetiredKeys{
+			{KeyID: "hs256", Algorithm: JWTAlgorithmHS256, Secret: "old", ExpiresAt: expiresAt},
+			{KeyID: "es256-public", Algorithm: JWTAlgorithmES256, Key: base64.StdEncoding.EncodeToString(publicKey), ExpiresAt: expiresAt},
+			{KeyID: "es256-private", Algorithm: JWTAlgorithmES256, Key: encodePKCS8(t, ecKey), ExpiresAt: expiresAt},
+			{KeyID: "expired", Algorithm: JWTAlgorithmHS256, Secret: "older", ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}
+	require.NoError(t, config.Validate())
+
+	require.NotNil(t, config.RetiredKey("hs256", time.Now()))
+	require.Equal(t, ecKey.Public(), config.RetiredKey("es256-public", time.Now()).PublicKey)
+	require.Equal(t, ecKey.Public(), config.RetiredKey("es256-private", time.Now()).PublicKey)
+	require.Nil(t, config.RetiredKey("expired", time.Now()))
+	require.Nil(t, config.RetiredKey("unknown", time.Now()))
+}
+
+func TestJWTRetiredKeysDecode(t *tst.T) {
+	var keys JWTRetiredKeys
+
+	require.NoError(t, keys.Decode(`[{"kid":"old","alg":"HS256","secret":"secret","expires_at":"2030-01-01T00:00:00Z"}]`))
+	require.Len(t, keys, 1)
+	require.Equal(t, "old", keys[0].KeyID)
+	require.Equal(t, "secret", keys[0].Secret)
+	require.Equal(t, 2030, keys[0].ExpiresAt.Year())
+
+	require.NoError(t, keys.Decode(""))
+	require.Empty(t, keys)
+
+	require.Error(t, keys.Decode("not json"))
+}
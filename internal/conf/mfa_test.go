@@ -0,0 +1,98 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/base64"
+	tst "testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMFASecretEncryptionValidate(t *tst.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+
+	invalidExamples := []*MFAConfiguration{
+		{
+			SecretEncryptionKeys: map[string]string{"key-1": key},
+		},
+		{
+			SecretEncryptionKeyID: "key-1",
+		},
+		{
+			SecretEncryptionKeys:  map[string]string{"key-1": key},
+			SecretEncryptionKeyID: "key-2",
+		},
+		{
+			SecretEncryptionKeys:  map[string]string{"key-1": "InvalidBase64!"},
+			SecretEncryptionKeyID: "key-1",
+		},
+		{
+			SecretEncryptionKeys:  map[string]string{"key-1": base64.StdEncoding.EncodeToString([]byte("short"))},
+			SecretEncryptionKeyID: "key-1",
+		},
+		{
+			SecretEncryptionKeys:  map[string]string{"key_1": key},
+			SecretEncryptionKeyID: "key_1",
+		},
+		{
+			MaxVerificationAttempts: -1,
+		},
+		{
+			Enforcement: MFAEnforcementConfiguration{
+				GracePeriod: -time.Hour,
+			},
+		},
+		{
+			TrustedDevices: TrustedDevicesConfiguration{
+				Enabled: true,
+			},
+		},
+	}
+
+	for i, example := range invalidExamples {
+		require.Error(t, example.Validate(), "Invalid example %d was regarded as valid", i)
+	}
+
+	validExamples := []*MFAConfiguration{
+		{},
+		{
+			SecretEncryptionKeys:  map[string]string{"key-1": key},
+			SecretEncryptionKeyID: "key-1",
+		},
+		{
+			SecretEncryptionKeys: map[string]string{
+				"key-1":      key,
+				"2023-10.v2": base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)),
+			},
+			SecretEncryptionKeyID: "2023-10.v2",
+		},
+		{
+			TrustedDevices: TrustedDevicesConfiguration{
+				Enabled:  true,
+				Duration: 30 * 24 * time.Hour,
+			},
+		},
+	}
+
+	for i, example := range validExamples {
+		require.NoError(t, example.Validate(), "Valid example %d was regarded as invalid", i)
+	}
+
+	keys, err := validExamples[1].GetSecretEncryptionKeys()
+	require.NoError(t, err)
+	require.Equal(t, bytes.Repeat([]byte{1}, 32), keys["key-1"])
+}
+
+func TestMFAEnforcementApplies(t *tst.T) {
+	c := &MFAEnforcementConfiguration{
+		Roles:     []string{"admin"},
+		Audiences: []string{"internal"},
+	}
+
+	require.True(t, c.Applies("admin", "authenticated"))
+	require.True(t, c.Applies("authenticated", "internal"))
+	require.False(t, c.Applies("authenticated", "authenticated"))
+
+	require.False(t, (&MFAEnforcementConfiguration{}).Applies("", ""))
+}
@@ -0,0 +1,110 @@
+package conf
+
+import (
+	tst "testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordHashValidate(t *tst.T) {
+	invalidExamples := []*PasswordHashConfiguration{
+		{},
+		{
+			Algorithm: "md5",
+		},
+		{
+			Algorithm:  "bcrypt",
+			BcryptCost: 3,
+		},
+		{
+			Algorithm:     "argon2id",
+			Argon2Memory:  19456,
+			Argon2Threads: 1,
+		},
+		{
+			Algorithm:     "argon2id",
+			Argon2Memory:  8,
+			Argon2Time:    2,
+			Argon2Threads: 4,
+		},
+		{
+			Algorithm: "scrypt",
+			ScryptN:   1000,
+			ScryptR:   8,
+			ScryptP:   1,
+		},
+		{
+			Algorithm: "scrypt",
+			ScryptN:   32768,
+			ScryptR:   8,
+		},
+	}
+
+	for i, example := range invalidExamples {
+		require.Error(t, example.Validate(), "Invalid example %d was regarded as valid", i)
+	}
+
+	validExamples := []*PasswordHashConfiguration{
+		{
+			Algorithm:  "bcrypt",
+			BcryptCost: 10,
+		},
+		{
+			Algorithm:     "argon2id",
+			Argon2Memory:  19456,
+			Argon2Time:    2,
+			Argon2Threads: 1,
+		},
+		{
+			Algorithm: "scrypt",
+			ScryptN:   32768,
+			ScryptR:   8,
+			ScryptP:   1,
+		},
+	}
+
+	for i, example := range validExamples {
+		require.NoError(t, example.Validate(), "Valid example %d was regarded as invalid", i)
+	}
+}
+
+func TestPasswordPolicyValidate(t *tst.T) {
+	invalidExamples := []*PasswordPolicyConfiguration{
+		{
+			RequiredCharacters: []string{"lowercase", "emoji"},
+		},
+		{
+			MinEntropy: -1,
+		},
+		{
+			LeakedPasswordsPath: "./missing",
+		},
+		{
+			LeakedPasswordsPath: "./password_test.go",
+		},
+		{
+			FlagLeakedPasswordsOnLogin: true,
+		},
+	}
+
+	for i, example := range invalidExamples {
+		require.Error(t, example.Validate(), "Invalid example %d was regarded as valid", i)
+	}
+
+	validExamples := []*PasswordPolicyConfiguration{
+		{},
+		{
+			RequiredCharacters:    []string{"lowercase", "uppercase", "digits", "symbols"},
+			RejectUserIdentifiers: true,
+			MinEntropy:            40,
+		},
+		{
+			LeakedPasswordsPath:        ".",
+			FlagLeakedPasswordsOnLogin: true,
+		},
+	}
+
+	for i, example := range validExamples {
+		require.NoError(t, example.Validate(), "Valid example %d was regarded as invalid", i)
+	}
+}
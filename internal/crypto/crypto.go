@@ -38,6 +38,26 @@ func GenerateOtp(digits int) (string, error) {
 	otp := fmt.Sprintf(expr, val.String())
 	return otp, nil
 }
+
+// userCodeCharset excludes vowels and easily confused characters, see RFC
+// 8628 Section 6.1.
+const userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+// GenerateUserCode generates a random code of n characters that is easy for
+// users to type in.
+func GenerateUserCode(n int) (string, error) {
+	code := make([]byte, n)
+	max := big.NewInt(int64(len(userCodeCharset)))
+	for i := range code {
+		val, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.WithMessage(err, "Error generating user code")
+		}
+		code[i] = userCodeCharset[val.Int64()]
+	}
+	return string(code), nil
+}
+
 func GenerateTokenHash(emailOrPhone, otp string) string {
 	return fmt.Sprintf("%x", sha256.Sum224([]byte(emailOrPhone+otp)))
 }
@@ -0,0 +1,198 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// envelopePrefix marks values encrypted with EnvelopeKeys. It is followed by
+// the key ID, the wrapped data key and the ciphertext, separated by colons.
+const envelopePrefix = "envelope:v1:"
+
+// EnvelopeKeys encrypts values with envelope encryption. Each value is
+// encrypted with its own random data key, which in turn is encrypted with
+// the active key. The ID of that key is stored with the value, so that
+// older keys can still decrypt it and its data key can be moved to a new
+// key without touching the ciphertext.
+type EnvelopeKeys struct {
+	ActiveKeyID string
+	Keys        map[string][]byte
+}
+
+// NewEnvelopeKeys returns the keys values are encrypted with, or nil when
+// activeKeyID is empty and values are stored unencrypted.
+func NewEnvelopeKeys(activeKeyID string, keys map[string][]byte) *EnvelopeKeys {
+	if activeKeyID == "" {
+		return nil
+	}
+
+	return &EnvelopeKeys{
+		ActiveKeyID: activeKeyID,
+		Keys:        keys,
+	}
+}
+
+// IsEnvelope returns whether the value was encrypted with EnvelopeKeys.
+func IsEnvelope(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// EnvelopePrefix returns the prefix of values whose data key is encrypted
+// with the key.
+func EnvelopePrefix(keyID string) string {
+	return envelopePrefix + keyID + ":"
+}
+
+// Encrypt encrypts the plaintext with a new data key. The additional data
+// is authenticated but not stored, it must be passed to Decrypt too.
+func (k *EnvelopeKeys) Encrypt(plaintext, additionalData []byte) (string, error) {
+	key, ok := k.Keys[k.ActiveKeyID]
+	if !ok {
+		return "", errors.Errorf("encryption key %q not found", k.ActiveKeyID)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", errors.WithMessage(err, "Error generating data key")
+	}
+
+	ciphertext, err := seal(dataKey, plaintext, additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(key, dataKey, []byte(k.ActiveKeyID))
+	if err != nil {
+		return "", err
+	}
+
+	return formatEnvelope(k.ActiveKeyID, wrappedKey, ciphertext), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt with any of the keys.
+func (k *EnvelopeKeys) Decrypt(value string, additionalData []byte) ([]byte, error) {
+	keyID, wrappedKey, ciphertext, err := parseEnvelope(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.unwrap(keyID, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(dataKey, ciphertext, additionalData)
+}
+
+// Rewrap encrypts the data key of a value returned by Encrypt with the
+// active key.
+func (k *EnvelopeKeys) Rewrap(value string) (string, error) {
+	keyID, wrappedKey, ciphertext, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+
+	if keyID == k.ActiveKeyID {
+		return value, nil
+	}
+
+	dataKey, err := k.unwrap(keyID, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := k.Keys[k.ActiveKeyID]
+	if !ok {
+		return "", errors.Errorf("encryption key %q not found", k.ActiveKeyID)
+	}
+
+	if wrappedKey, err = seal(key, dataKey, []byte(k.ActiveKeyID)); err != nil {
+		return "", err
+	}
+
+	return formatEnvelope(k.ActiveKeyID, wrappedKey, ciphertext), nil
+}
+
+func (k *EnvelopeKeys) unwrap(keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := k.Keys[keyID]
+	if !ok {
+		return nil, errors.Errorf("encryption key %q not found", keyID)
+	}
+
+	return open(key, wrappedKey, []byte(keyID))
+}
+
+func formatEnvelope(keyID string, wrappedKey, ciphertext []byte) string {
+	return EnvelopePrefix(keyID) + base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" + base64.RawStdEncoding.EncodeToString(ciphertext)
+}
+
+func parseEnvelope(value string) (string, []byte, []byte, error) {
+	if !IsEnvelope(value) {
+		return "", nil, nil, errors.New("value is not encrypted")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, errors.New("malformed encrypted value")
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, errors.WithMessage(err, "malformed encrypted value")
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, errors.WithMessage(err, "malformed encrypted value")
+	}
+
+	return parts[0], wrappedKey, ciphertext, nil
+}
+
+// seal encrypts with AES-GCM and prepends the random nonce.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.WithMessage(err, "Error generating nonce")
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("malformed encrypted value")
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error decrypting value")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Invalid encryption key")
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeEncryption(t *testing.T) {
+	keys := &EnvelopeKeys{
+		ActiveKeyID: "key-1",
+		Keys: map[string][]byte{
+			"key-1": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	value, err := keys.Encrypt([]byte("secret"), []byte("factor-id"))
+	require.NoError(t, err)
+	require.True(t, IsEnvelope(value))
+	require.True(t, strings.HasPrefix(value, EnvelopePrefix("key-1")))
+	require.NotContains(t, value, "secret")
+
+	plaintext, err := keys.Decrypt(value, []byte("factor-id"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plaintext)
+
+	// the value is bound to the additional data
+	_, err = keys.Decrypt(value, []byte("other-factor-id"))
+	require.Error(t, err)
+
+	// rotating to a new key keeps the old key for decryption
+	keys.Keys["key-2"] = bytes.Repeat([]byte{2}, 32)
+	keys.ActiveKeyID = "key-2"
+
+	plaintext, err = keys.Decrypt(value, []byte("factor-id"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plaintext)
+
+	rewrapped, err := keys.Rewrap(value)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(rewrapped, EnvelopePrefix("key-2")))
+
+	delete(keys.Keys, "key-1")
+	_, err = keys.Decrypt(value, []byte("factor-id"))
+	require.Error(t, err)
+
+	plaintext, err = keys.Decrypt(rewrapped, []byte("factor-id"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plaintext)
+}
+
+func TestEnvelopeDecryptErrors(t *testing.T) {
+	keys := &EnvelopeKeys{
+		ActiveKeyID: "key-1",
+		Keys: map[string][]byte{
+			"key-1": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	_, err := keys.Decrypt("plaintext", nil)
+	require.Error(t, err)
+
+	_, err = keys.Decrypt(EnvelopePrefix("key-1")+"not:base64", nil)
+	require.Error(t, err)
+
+	_, err = keys.Decrypt(EnvelopePrefix("key-1")+"AAAA", nil)
+	require.Error(t, err)
+}
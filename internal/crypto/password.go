@@ -2,12 +2,22 @@ package crypto
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
 
+	"github.com/supabase/gotrue/internal/conf"
 	"github.com/supabase/gotrue/internal/observability"
 	"go.opentelemetry.io/otel/attribute"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
 )
 
 type HashCost = int
@@ -28,6 +38,84 @@ const (
 // GenerateHashFromPassword.
 var PasswordHashCost = DefaultHashCost
 
+// Password hashing algorithms supported by GenerateFromPassword.
+const (
+	BcryptAlgorithm   = "bcrypt"
+	Argon2idAlgorithm = "argon2id"
+	ScryptAlgorithm   = "scrypt"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+	scryptPrefix   = "$scrypt$"
+
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+)
+
+// ErrMismatchedHashAndPassword is returned by CompareHashAndPassword when
+// the password doesn't match the hash.
+var ErrMismatchedHashAndPassword = errors.New("crypto: hash is not the hash of the given password")
+
+// PasswordHashParams are the algorithm and parameters new password hashes
+// are generated with.
+type PasswordHashParams struct {
+	Algorithm string
+
+	BcryptCost int
+
+	// Argon2Memory is the memory used by Argon2id in KiB.
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+
+	// ScryptN is the CPU/memory cost of scrypt, a power of two.
+	ScryptN int
+	ScryptR int
+	ScryptP int
+}
+
+// DefaultPasswordHashParams hashes passwords with bcrypt. The Argon2id and
+// scrypt parameters follow the OWASP recommendations.
+var DefaultPasswordHashParams = PasswordHashParams{
+	Algorithm:     BcryptAlgorithm,
+	BcryptCost:    bcrypt.DefaultCost,
+	Argon2Memory:  19 * 1024,
+	Argon2Time:    2,
+	Argon2Threads: 1,
+	ScryptN:       1 << 15,
+	ScryptR:       8,
+	ScryptP:       1,
+}
+
+// NewPasswordHashParams returns the algorithm and parameters new password
+// hashes are generated with according to the configuration.
+func NewPasswordHashParams(config *conf.PasswordHashConfiguration) PasswordHashParams {
+	return PasswordHashParams{
+		Algorithm:     config.Algorithm,
+		BcryptCost:    config.BcryptCost,
+		Argon2Memory:  config.Argon2Memory,
+		Argon2Time:    config.Argon2Time,
+		Argon2Threads: config.Argon2Threads,
+		ScryptN:       config.ScryptN,
+		ScryptR:       config.ScryptR,
+		ScryptP:       config.ScryptP,
+	}
+}
+
+// withHashCost returns the parameters new hashes are generated with, taking
+// PasswordHashCost into account.
+func (params PasswordHashParams) withHashCost() PasswordHashParams {
+	switch PasswordHashCost {
+	case QuickHashCost:
+		params.BcryptCost = bcrypt.MinCost
+		params.Argon2Memory, params.Argon2Time, params.Argon2Threads = 64, 1, 1
+		params.ScryptN, params.ScryptR, params.ScryptP = 16, 8, 1
+	}
+
+	return params
+}
+
 var (
 	generateFromPasswordSubmittedCounter = observability.ObtainMetricCounter("gotrue_generate_from_password_submitted", "Number of submitted GenerateFromPassword hashing attempts")
 	generateFromPasswordCompletedCounter = observability.ObtainMetricCounter("gotrue_generate_from_password_completed", "Number of completed GenerateFromPassword hashing attempts")
@@ -38,61 +126,335 @@ var (
 	compareHashAndPasswordCompletedCounter = observability.ObtainMetricCounter("gotrue_compare_hash_and_password_completed", "Number of completed CompareHashAndPassword hashing attempts")
 )
 
-// CompareHashAndPassword compares the hash and
-// password, returns nil if equal otherwise an error. Context can be used to
-// cancel the hashing if the algorithm supports it.
-func CompareHashAndPassword(ctx context.Context, hash, password string) error {
-	hashCost, err := bcrypt.Cost([]byte(hash))
+// argon2Hash is a parsed Argon2id or Argon2i hash in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+type argon2Hash struct {
+	variant string
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2Hash(hash string) (*argon2Hash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || (parts[1] != Argon2idAlgorithm && parts[1] != Argon2iAlgorithm) {
+		return nil, errors.New("crypto: invalid argon2 hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, errors.New("crypto: unsupported argon2 version")
+	}
+
+	h := &argon2Hash{variant: parts[1]}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.memory, &h.time, &h.threads); err != nil || h.time < 1 || h.threads < 1 {
+		return nil, errors.New("crypto: invalid argon2 parameters")
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, errors.New("crypto: invalid argon2 salt")
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return nil, errors.New("crypto: invalid argon2 key")
+	}
+
+	return h, nil
+}
+
+func (h *argon2Hash) String() string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", h.variant, argon2.Version, h.memory, h.time, h.threads, base64.RawStdEncoding.EncodeToString(h.salt), base64.RawStdEncoding.EncodeToString(h.key))
+}
+
+func (h *argon2Hash) compare(password string) error {
+	var key []byte
+	if h.variant == Argon2iAlgorithm {
+		key = argon2.Key([]byte(password), h.salt, h.time, h.memory, h.threads, uint32(len(h.key)))
+	} else {
+		key = argon2.IDKey([]byte(password), h.salt, h.time, h.memory, h.threads, uint32(len(h.key)))
+	}
+
+	if subtle.ConstantTimeCompare(key, h.key) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// scryptHash is a parsed scrypt hash in the PHC string format, with the
+// base 2 logarithm of N as ln:
+// $scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<key>
+type scryptHash struct {
+	ln   int
+	r    int
+	p    int
+	salt []byte
+	key  []byte
+}
+
+func parseScryptHash(hash string) (*scryptHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 {
+		return nil, errors.New("crypto: invalid scrypt hash")
+	}
+
+	h := &scryptHash{}
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &h.ln, &h.r, &h.p); err != nil || h.ln < 1 || h.ln > 31 {
+		return nil, errors.New("crypto: invalid scrypt parameters")
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil {
+		return nil, errors.New("crypto: invalid scrypt salt")
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil || len(h.key) == 0 {
+		return nil, errors.New("crypto: invalid scrypt key")
+	}
+
+	return h, nil
+}
+
+func (h *scryptHash) String() string {
+	return fmt.Sprintf("%sln=%d,r=%d,p=%d$%s$%s", scryptPrefix, h.ln, h.r, h.p, base64.RawStdEncoding.EncodeToString(h.salt), base64.RawStdEncoding.EncodeToString(h.key))
+}
+
+func (h *scryptHash) compare(password string) error {
+	key, err := scrypt.Key([]byte(password), h.salt, 1<<h.ln, h.r, h.p, len(h.key))
 	if err != nil {
 		return err
 	}
 
-	attributes := []attribute.KeyValue{
-		attribute.String("alg", "bcrypt"),
-		attribute.Int("bcrypt_cost", hashCost),
+	if subtle.ConstantTimeCompare(key, h.key) != 1 {
+		return ErrMismatchedHashAndPassword
 	}
+	return nil
+}
+
+// passwordHashAlgorithm returns the algorithm of the hash from its prefix.
+// Hashes without a known prefix are bcrypt hashes.
+func passwordHashAlgorithm(hash string) string {
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		return Argon2idAlgorithm
+
+	case strings.HasPrefix(hash, argon2iPrefix):
+		return Argon2iAlgorithm
+
+	case strings.HasPrefix(hash, scryptPrefix):
+		return ScryptAlgorithm
+
+	case strings.HasPrefix(hash, firebaseScryptPrefix):
+		return FirebaseScryptAlgorithm
+
+	case strings.HasPrefix(hash, pbkdf2SHA256Prefix):
+		return PBKDF2SHA256Algorithm
+
+	default:
+		return BcryptAlgorithm
+	}
+}
+
+// CompareHashAndPassword compares the hash and
+// password, returns nil if equal otherwise an error. The algorithm is
+// chosen by the prefix of the hash. Context can be used to
+// cancel the hashing if the algorithm supports it.
+func CompareHashAndPassword(ctx context.Context, hash, password string) error {
+	var attributes []attribute.KeyValue
+	var compare func() error
+
+	switch alg := passwordHashAlgorithm(hash); alg {
+	case Argon2idAlgorithm, Argon2iAlgorithm:
+		h, err := parseArgon2Hash(hash)
+		if err != nil {
+			return err
+		}
+
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", alg),
+			attribute.Int("argon2_memory", int(h.memory)),
+			attribute.Int("argon2_time", int(h.time)),
+		}
+		compare = func() error {
+			return h.compare(password)
+		}
+
+	case ScryptAlgorithm:
+		h, err := parseScryptHash(hash)
+		if err != nil {
+			return err
+		}
+
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", ScryptAlgorithm),
+			attribute.Int("scrypt_ln", h.ln),
+		}
+		compare = func() error {
+			return h.compare(password)
+		}
+
+	case FirebaseScryptAlgorithm:
+		h, err := parseFirebaseScryptHash(hash)
+		if err != nil {
+			return err
+		}
+
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", FirebaseScryptAlgorithm),
+			attribute.Int("scrypt_ln", h.memCost),
+		}
+		compare = func() error {
+			return h.compare(password)
+		}
+
+	case PBKDF2SHA256Algorithm:
+		h, err := parsePBKDF2SHA256Hash(hash)
+		if err != nil {
+			return err
+		}
+
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", PBKDF2SHA256Algorithm),
+			attribute.Int("pbkdf2_iterations", h.iterations),
+		}
+		compare = func() error {
+			return h.compare(password)
+		}
+
+	default:
+		hashCost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return err
+		}
+
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", BcryptAlgorithm),
+			attribute.Int("bcrypt_cost", hashCost),
+		}
+		compare = func() error {
+			err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return ErrMismatchedHashAndPassword
+			}
+			return err
+		}
+	}
+
+	var err error
 
 	compareHashAndPasswordSubmittedCounter.Add(ctx, 1, attributes...)
 	defer func() {
 		attributes = append(attributes, attribute.Bool(
 			"match",
-			!errors.Is(err, bcrypt.ErrMismatchedHashAndPassword),
+			!errors.Is(err, ErrMismatchedHashAndPassword),
 		))
 
 		compareHashAndPasswordCompletedCounter.Add(ctx, 1, attributes...)
 	}()
 
-	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	err = compare()
 
 	return err
 }
 
+// NeedsRehash returns whether the hash was generated with another algorithm
+// or other parameters than params, in which case the password should be
+// hashed again the next time it is known.
+func NeedsRehash(params PasswordHashParams, hash string) bool {
+	params = params.withHashCost()
+
+	if passwordHashAlgorithm(hash) != params.Algorithm {
+		return true
+	}
+
+	switch params.Algorithm {
+	case Argon2idAlgorithm:
+		h, err := parseArgon2Hash(hash)
+		return err != nil || h.memory != params.Argon2Memory || h.time != params.Argon2Time || h.threads != params.Argon2Threads || len(h.key) != passwordKeyLength
+
+	case ScryptAlgorithm:
+		h, err := parseScryptHash(hash)
+		return err != nil || 1<<h.ln != params.ScryptN || h.r != params.ScryptR || h.p != params.ScryptP || len(h.key) != passwordKeyLength
+
+	default:
+		hashCost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || hashCost != params.BcryptCost
+	}
+}
+
 // GenerateFromPassword generates a password hash from a
-// password, using PasswordHashCost. Context can be used to cancel the hashing
+// password, using the algorithm and parameters in params and
+// PasswordHashCost. Context can be used to cancel the hashing
 // if the algorithm supports it.
-func GenerateFromPassword(ctx context.Context, password string) (string, error) {
-	var hashCost int
+func GenerateFromPassword(ctx context.Context, params PasswordHashParams, password string) (string, error) {
+	params = params.withHashCost()
 
-	switch PasswordHashCost {
-	case QuickHashCost:
-		hashCost = bcrypt.MinCost
+	var attributes []attribute.KeyValue
+	var generate func(salt []byte) (string, error)
+
+	switch params.Algorithm {
+	case Argon2idAlgorithm:
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", Argon2idAlgorithm),
+			attribute.Int("argon2_memory", int(params.Argon2Memory)),
+			attribute.Int("argon2_time", int(params.Argon2Time)),
+		}
+		generate = func(salt []byte) (string, error) {
+			h := &argon2Hash{
+				variant: Argon2idAlgorithm,
+				memory:  params.Argon2Memory,
+				time:    params.Argon2Time,
+				threads: params.Argon2Threads,
+				salt:    salt,
+				key:     argon2.IDKey([]byte(password), salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, passwordKeyLength),
+			}
+			return h.String(), nil
+		}
+
+	case ScryptAlgorithm:
+		ln := bits.Len(uint(params.ScryptN)) - 1
+
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", ScryptAlgorithm),
+			attribute.Int("scrypt_ln", ln),
+		}
+		generate = func(salt []byte) (string, error) {
+			key, err := scrypt.Key([]byte(password), salt, params.ScryptN, params.ScryptR, params.ScryptP, passwordKeyLength)
+			if err != nil {
+				return "", err
+			}
+
+			h := &scryptHash{
+				ln:   ln,
+				r:    params.ScryptR,
+				p:    params.ScryptP,
+				salt: salt,
+				key:  key,
+			}
+			return h.String(), nil
+		}
 
 	default:
-		hashCost = bcrypt.DefaultCost
+		attributes = []attribute.KeyValue{
+			attribute.String("alg", BcryptAlgorithm),
+			attribute.Int("bcrypt_cost", params.BcryptCost),
+		}
+		generate = func([]byte) (string, error) {
+			hash, err := bcrypt.GenerateFromPassword([]byte(password), params.BcryptCost)
+			if err != nil {
+				return "", err
+			}
+			return string(hash), nil
+		}
 	}
 
-	attributes := []attribute.KeyValue{
-		attribute.String("alg", "bcrypt"),
-		attribute.Int("bcrypt_cost", hashCost),
+	salt := make([]byte, passwordSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
 	}
 
 	generateFromPasswordSubmittedCounter.Add(ctx, 1, attributes...)
 	defer generateFromPasswordCompletedCounter.Add(ctx, 1, attributes...)
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), hashCost)
-	if err != nil {
-		return "", err
-	}
-
-	return string(hash), nil
+	return generate(salt)
 }
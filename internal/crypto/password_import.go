@@ -0,0 +1,241 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Algorithms of password hashes generated elsewhere that can be imported.
+// CompareHashAndPassword verifies them, but new hashes are never generated
+// with them, so NeedsRehash always reports them.
+const (
+	Argon2iAlgorithm        = "argon2i"
+	FirebaseScryptAlgorithm = "firebase_scrypt"
+	PBKDF2SHA256Algorithm   = "pbkdf2_sha256"
+)
+
+const (
+	argon2iPrefix        = "$argon2i$"
+	firebaseScryptPrefix = "$fbscrypt$"
+	pbkdf2SHA256Prefix   = "pbkdf2_sha256$"
+)
+
+// Limits of the parameters of imported hashes, so that verifying them on
+// sign in can't take unbounded memory or time. scrypt needs 128 * r * N
+// bytes, argon2 the configured memory in KiB.
+const (
+	maxImportedArgon2Memory     = 64 * 1024
+	maxImportedArgon2Time       = 16
+	maxImportedScryptLn         = 20
+	maxImportedScryptR          = 32
+	maxImportedScryptP          = 16
+	maxImportedScryptMemory     = 64 * 1024 * 1024
+	maxImportedPBKDF2Iterations = 10_000_000
+)
+
+// validateImportedScryptParams checks the parameters of an imported scrypt
+// or Firebase scrypt hash against the limits of imported hashes.
+func validateImportedScryptParams(ln, r, p int) error {
+	if ln > maxImportedScryptLn || r > maxImportedScryptR || p > maxImportedScryptP {
+		return errors.New("crypto: scrypt parameters exceed the supported limits")
+	}
+	if 128*r*(1<<ln) > maxImportedScryptMemory {
+		return errors.New("crypto: scrypt memory exceeds the supported limits")
+	}
+	return nil
+}
+
+// decodeBase64 decodes standard base64 with or without padding, as hashes
+// exported by other systems use both.
+func decodeBase64(value string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// firebaseScryptHash is a password hash exported by Firebase Authentication,
+// which uses a modified scrypt. The hash parameters of the project are
+// stored with the hash:
+// $fbscrypt$v=1,n=<mem_cost>,r=<rounds>,p=1,ss=<salt_separator>,sk=<signer_key>$<salt>$<hash>
+type firebaseScryptHash struct {
+	memCost       int
+	rounds        int
+	p             int
+	saltSeparator []byte
+	signerKey     []byte
+	salt          []byte
+	key           []byte
+}
+
+func parseFirebaseScryptHash(hash string) (*firebaseScryptHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 {
+		return nil, errors.New("crypto: invalid firebase scrypt hash")
+	}
+
+	h := &firebaseScryptHash{}
+	version := ""
+	for _, param := range strings.Split(parts[2], ",") {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return nil, errors.New("crypto: invalid firebase scrypt parameters")
+		}
+
+		var err error
+		switch name {
+		case "v":
+			version = value
+		case "n":
+			h.memCost, err = strconv.Atoi(value)
+		case "r":
+			h.rounds, err = strconv.Atoi(value)
+		case "p":
+			h.p, err = strconv.Atoi(value)
+		case "ss":
+			h.saltSeparator, err = decodeBase64(value)
+		case "sk":
+			h.signerKey, err = decodeBase64(value)
+		default:
+			err = errors.New("unknown parameter")
+		}
+		if err != nil {
+			return nil, errors.New("crypto: invalid firebase scrypt parameters")
+		}
+	}
+
+	if version != "1" || h.memCost < 1 || h.memCost > 31 || h.rounds < 1 || h.p < 1 || len(h.signerKey) == 0 {
+		return nil, errors.New("crypto: invalid firebase scrypt parameters")
+	}
+
+	var err error
+	if h.salt, err = decodeBase64(parts[3]); err != nil {
+		return nil, errors.New("crypto: invalid firebase scrypt salt")
+	}
+	if h.key, err = decodeBase64(parts[4]); err != nil || len(h.key) == 0 {
+		return nil, errors.New("crypto: invalid firebase scrypt hash")
+	}
+
+	return h, nil
+}
+
+// compare derives a key from the password with scrypt and uses it to
+// encrypt the signer key with AES-256-CTR, which gives the hash.
+func (h *firebaseScryptHash) compare(password string) error {
+	salt := make([]byte, 0, len(h.salt)+len(h.saltSeparator))
+	salt = append(salt, h.salt...)
+	salt = append(salt, h.saltSeparator...)
+
+	derivedKey, err := scrypt.Key([]byte(password), salt, 1<<h.memCost, h.rounds, h.p, 32)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, len(h.signerKey))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(key, h.signerKey)
+
+	if subtle.ConstantTimeCompare(key, h.key) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// pbkdf2SHA256Hash is a PBKDF2-SHA256 password hash in the format used by
+// Django: pbkdf2_sha256$<iterations>$<salt>$<hash>
+type pbkdf2SHA256Hash struct {
+	iterations int
+	salt       []byte
+	key        []byte
+}
+
+func parsePBKDF2SHA256Hash(hash string) (*pbkdf2SHA256Hash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[2] == "" {
+		return nil, errors.New("crypto: invalid pbkdf2 hash")
+	}
+
+	h := &pbkdf2SHA256Hash{
+		salt: []byte(parts[2]),
+	}
+
+	var err error
+	if h.iterations, err = strconv.Atoi(parts[1]); err != nil || h.iterations < 1 {
+		return nil, errors.New("crypto: invalid pbkdf2 iterations")
+	}
+	if h.key, err = decodeBase64(parts[3]); err != nil || len(h.key) == 0 {
+		return nil, errors.New("crypto: invalid pbkdf2 key")
+	}
+
+	return h, nil
+}
+
+func (h *pbkdf2SHA256Hash) compare(password string) error {
+	key := pbkdf2.Key([]byte(password), h.salt, h.iterations, len(h.key), sha256.New)
+
+	if subtle.ConstantTimeCompare(key, h.key) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// ValidatePasswordHash checks that a password hash generated elsewhere can
+// be imported: it has to be in a format CompareHashAndPassword supports,
+// with parameters within the limits of imported hashes.
+func ValidatePasswordHash(hash string) error {
+	switch passwordHashAlgorithm(hash) {
+	case Argon2idAlgorithm, Argon2iAlgorithm:
+		h, err := parseArgon2Hash(hash)
+		if err != nil {
+			return err
+		}
+		if h.memory > maxImportedArgon2Memory || h.time > maxImportedArgon2Time {
+			return errors.New("crypto: argon2 parameters exceed the supported limits")
+		}
+
+	case ScryptAlgorithm:
+		h, err := parseScryptHash(hash)
+		if err != nil {
+			return err
+		}
+		if err := validateImportedScryptParams(h.ln, h.r, h.p); err != nil {
+			return err
+		}
+
+	case FirebaseScryptAlgorithm:
+		h, err := parseFirebaseScryptHash(hash)
+		if err != nil {
+			return err
+		}
+		if err := validateImportedScryptParams(h.memCost, h.rounds, h.p); err != nil {
+			return err
+		}
+
+	case PBKDF2SHA256Algorithm:
+		h, err := parsePBKDF2SHA256Hash(hash)
+		if err != nil {
+			return err
+		}
+		if h.iterations > maxImportedPBKDF2Iterations {
+			return errors.New("crypto: pbkdf2 iterations exceed the supported limits")
+		}
+
+	default:
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
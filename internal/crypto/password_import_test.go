@@ -0,0 +1,73 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportedPasswordHashes(t *testing.T) {
+	examples := []struct {
+		hash     string
+		password string
+	}{
+		{
+			// Django
+			hash:     "pbkdf2_sha256$1000$saltsalt$E196ZhRPzw+wA84EjzHwJO1cv/MFJdO6C/sxmUeTYqY=",
+			password: "password",
+		},
+		{
+			// the example of the Firebase scrypt documentation
+			hash:     "$fbscrypt$v=1,n=14,r=8,p=1,ss=Bw==,sk=jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==$42xEC+ixf3L2lw==$lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==",
+			password: "user1password",
+		},
+		{
+			// the example of the Argon2 reference implementation
+			hash:     "$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$wWKIMhR9lyDFvRz9YTZweHKfbftvj+qf+YFY4NeBbtA",
+			password: "password",
+		},
+	}
+
+	for _, example := range examples {
+		require.NoError(t, ValidatePasswordHash(example.hash), example.hash)
+		require.NoError(t, CompareHashAndPassword(context.Background(), example.hash, example.password), example.hash)
+		require.ErrorIs(t, CompareHashAndPassword(context.Background(), example.hash, "other"), ErrMismatchedHashAndPassword, example.hash)
+
+		// imported hashes are always upgraded
+		require.True(t, NeedsRehash(DefaultPasswordHashParams, example.hash), example.hash)
+	}
+}
+
+func TestValidatePasswordHash(t *testing.T) {
+	invalidExamples := []string{
+		"",
+		"password",
+		"$argon2id$v=19$m=4194304,t=2,p=1$c29tZXNhbHQ$a2V5",
+		"$scrypt$ln=24,r=8,p=1$c29tZXNhbHQ$a2V5",
+		"$scrypt$ln=1,r=536870911,p=1$c29tZXNhbHQ$a2V5",
+		"$scrypt$ln=17,r=8,p=1$c29tZXNhbHQ$a2V5",
+		"$scrypt$ln=14,r=8,p=64$c29tZXNhbHQ$a2V5",
+		"$argon2id$v=19$m=131072,t=2,p=1$c29tZXNhbHQ$a2V5",
+		"$fbscrypt$v=1,n=14,r=536870911,p=1,ss=Bw==,sk=a2V5$c2FsdA==$a2V5",
+		"$fbscrypt$v=2,n=14,r=8,p=1,ss=Bw==,sk=a2V5$c2FsdA==$a2V5",
+		"$fbscrypt$v=1,n=14,r=8,p=1,ss=Bw==$c2FsdA==$a2V5",
+		"pbkdf2_sha256$0$salt$a2V5",
+		"pbkdf2_sha256$100000000$salt$a2V5",
+		"pbkdf2_sha1$1000$salt$a2V5",
+	}
+
+	for _, hash := range invalidExamples {
+		require.Error(t, ValidatePasswordHash(hash), hash)
+	}
+
+	validExamples := []string{
+		"$2a$10$7EqJtq98hPqEX7fNZaFWoOhi5BWX4Z5nTjjWUFNmmEzg5n5w.Ndi6",
+		"$argon2id$v=19$m=19456,t=2,p=1$c29tZXNhbHQ$a2V5",
+		"$scrypt$ln=15,r=8,p=1$c29tZXNhbHQ$a2V5",
+	}
+
+	for _, hash := range validExamples {
+		require.NoError(t, ValidatePasswordHash(hash), hash)
+	}
+}
@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordHashing(t *testing.T) {
+	PasswordHashCost = QuickHashCost
+	defer func() {
+		PasswordHashCost = DefaultHashCost
+	}()
+
+	examples := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{algorithm: BcryptAlgorithm, prefix: "$2a$"},
+		{algorithm: Argon2idAlgorithm, prefix: "$argon2id$v=19$m=64,t=1,p=1$"},
+		{algorithm: ScryptAlgorithm, prefix: "$scrypt$ln=4,r=8,p=1$"},
+	}
+
+	for _, example := range examples {
+		params := DefaultPasswordHashParams
+		params.Algorithm = example.algorithm
+
+		hash, err := GenerateFromPassword(context.Background(), params, "password")
+		require.NoError(t, err, example.algorithm)
+		require.True(t, strings.HasPrefix(hash, example.prefix), hash)
+
+		require.NoError(t, CompareHashAndPassword(context.Background(), hash, "password"), example.algorithm)
+		require.ErrorIs(t, CompareHashAndPassword(context.Background(), hash, "other"), ErrMismatchedHashAndPassword, example.algorithm)
+		require.False(t, NeedsRehash(params, hash), example.algorithm)
+
+		// hashes of the other algorithms can still be compared, but
+		// need to be rehashed
+		for _, other := range examples {
+			if other.algorithm == example.algorithm {
+				continue
+			}
+
+			otherParams := DefaultPasswordHashParams
+			otherParams.Algorithm = other.algorithm
+
+			require.NoError(t, CompareHashAndPassword(context.Background(), hash, "password"), example.algorithm)
+			require.True(t, NeedsRehash(otherParams, hash), example.algorithm)
+		}
+	}
+}
+
+func TestPasswordNeedsRehashWithOtherParameters(t *testing.T) {
+	params := DefaultPasswordHashParams
+	params.Algorithm = Argon2idAlgorithm
+	params.Argon2Memory, params.Argon2Time = 64, 1
+
+	hash, err := GenerateFromPassword(context.Background(), params, "password")
+	require.NoError(t, err)
+	require.False(t, NeedsRehash(params, hash))
+
+	params.Argon2Time = 2
+	require.True(t, NeedsRehash(params, hash))
+	require.NoError(t, CompareHashAndPassword(context.Background(), hash, "password"))
+}
+
+func TestCompareHashAndPasswordInvalidHash(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=0,r=8,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=4,r=8,p=1$!!!$a2V5",
+	} {
+		require.Error(t, CompareHashAndPassword(context.Background(), hash, "password"), hash)
+	}
+}
@@ -21,6 +21,7 @@ type Mailer interface {
 	MagicLinkMail(user *models.User, otp, referrerURL string, externalURL *url.URL) error
 	EmailChangeMail(user *models.User, otpNew, otpCurrent, referrerURL string, externalURL *url.URL) error
 	ReauthenticateMail(user *models.User, otp string) error
+	RefreshTokenReuseMail(user *models.User) error
 	ValidateEmail(email string) error
 	GetEmailActionLink(user *models.User, actionType, referrerURL string, externalURL *url.URL) (string, error)
 }
@@ -68,6 +68,11 @@ const defaultReauthenticateMail = `<h2>Confirm reauthentication</h2>
 
 <p>Enter the code: {{ .Token }}</p>`
 
+const defaultRefreshTokenReuseMail = `<h2>Suspicious sign in activity</h2>
+
+<p>A session of your account at {{ .SiteURL }} was used from an unexpected device, which may mean that it was stolen.</p>
+<p>If this was not you, we recommend that you change your password.</p>`
+
 // ValidateEmail returns nil if the email is valid,
 // otherwise an error indicating the reason it is invalid
 func (m TemplateMailer) ValidateEmail(email string) error {
@@ -153,6 +158,24 @@ func (m *TemplateMailer) ReauthenticateMail(user *models.User, otp string) error
 	)
 }
 
+// RefreshTokenReuseMail lets a user know that a refresh token of theirs
+// was reused, which indicates that it was stolen
+func (m *TemplateMailer) RefreshTokenReuseMail(user *models.User) error {
+	data := map[string]interface{}{
+		"SiteURL": m.Config.SiteURL,
+		"Email":   user.Email,
+		"Data":    user.UserMetaData,
+	}
+
+	return m.Mailer.Mail(
+		user.GetEmail(),
+		withDefault(m.Config.Mailer.Subjects.RefreshTokenReuse, "Suspicious sign in activity"),
+		m.Config.Mailer.Templates.RefreshTokenReuse,
+		defaultRefreshTokenReuseMail,
+		data,
+	)
+}
+
 // EmailChangeMail sends an email change confirmation mail to a user
 func (m *TemplateMailer) EmailChangeMail(user *models.User, otpNew, otpCurrent, referrerURL string, externalURL *url.URL) error {
 	type Email struct {
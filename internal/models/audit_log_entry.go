@@ -17,61 +17,92 @@ type AuditAction string
 type auditLogType string
 
 const (
-	LoginAction                     AuditAction = "login"
-	LogoutAction                    AuditAction = "logout"
-	InviteAcceptedAction            AuditAction = "invite_accepted"
-	UserSignedUpAction              AuditAction = "user_signedup"
-	UserInvitedAction               AuditAction = "user_invited"
-	UserDeletedAction               AuditAction = "user_deleted"
-	UserModifiedAction              AuditAction = "user_modified"
-	UserRecoveryRequestedAction     AuditAction = "user_recovery_requested"
-	UserReauthenticateAction        AuditAction = "user_reauthenticate_requested"
-	UserConfirmationRequestedAction AuditAction = "user_confirmation_requested"
-	UserRepeatedSignUpAction        AuditAction = "user_repeated_signup"
-	UserUpdatePasswordAction        AuditAction = "user_updated_password"
-	TokenRevokedAction              AuditAction = "token_revoked"
-	TokenRefreshedAction            AuditAction = "token_refreshed"
-	GenerateRecoveryCodesAction     AuditAction = "generate_recovery_codes"
-	EnrollFactorAction              AuditAction = "factor_in_progress"
-	UnenrollFactorAction            AuditAction = "factor_unenrolled"
-	CreateChallengeAction           AuditAction = "challenge_created"
-	VerifyFactorAction              AuditAction = "verification_attempted"
-	DeleteFactorAction              AuditAction = "factor_deleted"
-	DeleteRecoveryCodesAction       AuditAction = "recovery_codes_deleted"
-	UpdateFactorAction              AuditAction = "factor_updated"
-	MFACodeLoginAction              AuditAction = "mfa_code_login"
-
-	account       auditLogType = "account"
-	team          auditLogType = "team"
-	token         auditLogType = "token"
-	user          auditLogType = "user"
-	factor        auditLogType = "factor"
-	recoveryCodes auditLogType = "recovery_codes"
+	LoginAction                       AuditAction = "login"
+	LogoutAction                      AuditAction = "logout"
+	InviteAcceptedAction              AuditAction = "invite_accepted"
+	UserSignedUpAction                AuditAction = "user_signedup"
+	UserInvitedAction                 AuditAction = "user_invited"
+	UserDeletedAction                 AuditAction = "user_deleted"
+	UserModifiedAction                AuditAction = "user_modified"
+	UserRecoveryRequestedAction       AuditAction = "user_recovery_requested"
+	UserReauthenticateAction          AuditAction = "user_reauthenticate_requested"
+	UserReauthenticatedAction         AuditAction = "user_reauthenticated"
+	UserConfirmationRequestedAction   AuditAction = "user_confirmation_requested"
+	UserRepeatedSignUpAction          AuditAction = "user_repeated_signup"
+	UserUpdatePasswordAction          AuditAction = "user_updated_password"
+	TokenRevokedAction                AuditAction = "token_revoked"
+	TokenRefreshedAction              AuditAction = "token_refreshed"
+	RefreshTokenReuseDetectedAction   AuditAction = "refresh_token_reuse_detected"
+	GenerateRecoveryCodesAction       AuditAction = "generate_recovery_codes"
+	EnrollFactorAction                AuditAction = "factor_in_progress"
+	UnenrollFactorAction              AuditAction = "factor_unenrolled"
+	CreateChallengeAction             AuditAction = "challenge_created"
+	VerifyFactorAction                AuditAction = "verification_attempted"
+	DeleteFactorAction                AuditAction = "factor_deleted"
+	DeleteRecoveryCodesAction         AuditAction = "recovery_codes_deleted"
+	RecoveryCodeRedeemedAction        AuditAction = "recovery_code_redeemed"
+	RecoveryCodesLockedAction         AuditAction = "recovery_codes_locked"
+	UpdateFactorAction                AuditAction = "factor_updated"
+	FactorLockedAction                AuditAction = "factor_locked"
+	TrustDeviceAction                 AuditAction = "device_trusted"
+	RevokeTrustedDeviceAction         AuditAction = "trusted_device_revoked"
+	MFACodeLoginAction                AuditAction = "mfa_code_login"
+	OAuthConsentGrantedAction         AuditAction = "oauth_consent_granted"
+	OAuthConsentDeniedAction          AuditAction = "oauth_consent_denied"
+	ServiceAccountCreatedAction       AuditAction = "service_account_created"
+	ServiceAccountModifiedAction      AuditAction = "service_account_modified"
+	ServiceAccountDeletedAction       AuditAction = "service_account_deleted"
+	ServiceAccountTokenAction         AuditAction = "service_account_token_issued"
+	DeviceAuthorizationApprovedAction AuditAction = "device_authorization_approved"
+	DeviceAuthorizationDeniedAction   AuditAction = "device_authorization_denied"
+
+	account        auditLogType = "account"
+	team           auditLogType = "team"
+	token          auditLogType = "token"
+	user           auditLogType = "user"
+	factor         auditLogType = "factor"
+	recoveryCodes  auditLogType = "recovery_codes"
+	serviceAccount auditLogType = "service_account"
 )
 
 var ActionLogTypeMap = map[AuditAction]auditLogType{
-	LoginAction:                     account,
-	LogoutAction:                    account,
-	InviteAcceptedAction:            account,
-	UserSignedUpAction:              team,
-	UserInvitedAction:               team,
-	UserDeletedAction:               team,
-	TokenRevokedAction:              token,
-	TokenRefreshedAction:            token,
-	UserModifiedAction:              user,
-	UserRecoveryRequestedAction:     user,
-	UserConfirmationRequestedAction: user,
-	UserRepeatedSignUpAction:        user,
-	UserUpdatePasswordAction:        user,
-	GenerateRecoveryCodesAction:     user,
-	EnrollFactorAction:              factor,
-	UnenrollFactorAction:            factor,
-	CreateChallengeAction:           factor,
-	VerifyFactorAction:              factor,
-	DeleteFactorAction:              factor,
-	UpdateFactorAction:              factor,
-	MFACodeLoginAction:              factor,
-	DeleteRecoveryCodesAction:       recoveryCodes,
+	LoginAction:                       account,
+	LogoutAction:                      account,
+	InviteAcceptedAction:              account,
+	UserSignedUpAction:                team,
+	UserInvitedAction:                 team,
+	UserDeletedAction:                 team,
+	TokenRevokedAction:                token,
+	TokenRefreshedAction:              token,
+	RefreshTokenReuseDetectedAction:   token,
+	UserModifiedAction:                user,
+	UserRecoveryRequestedAction:       user,
+	UserConfirmationRequestedAction:   user,
+	UserReauthenticatedAction:         account,
+	UserRepeatedSignUpAction:          user,
+	UserUpdatePasswordAction:          user,
+	GenerateRecoveryCodesAction:       user,
+	EnrollFactorAction:                factor,
+	UnenrollFactorAction:              factor,
+	CreateChallengeAction:             factor,
+	VerifyFactorAction:                factor,
+	DeleteFactorAction:                factor,
+	UpdateFactorAction:                factor,
+	FactorLockedAction:                factor,
+	TrustDeviceAction:                 factor,
+	RevokeTrustedDeviceAction:         factor,
+	MFACodeLoginAction:                factor,
+	DeleteRecoveryCodesAction:         recoveryCodes,
+	RecoveryCodeRedeemedAction:        recoveryCodes,
+	RecoveryCodesLockedAction:         recoveryCodes,
+	OAuthConsentGrantedAction:         user,
+	OAuthConsentDeniedAction:          user,
+	ServiceAccountCreatedAction:       serviceAccount,
+	ServiceAccountModifiedAction:      serviceAccount,
+	ServiceAccountDeletedAction:       serviceAccount,
+	ServiceAccountTokenAction:         serviceAccount,
+	DeviceAuthorizationApprovedAction: account,
+	DeviceAuthorizationDeniedAction:   account,
 }
 
 // AuditLogEntry is the database model for audit log entries.
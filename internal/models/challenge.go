@@ -15,6 +15,13 @@ type Challenge struct {
 	VerifiedAt *time.Time `json:"verified_at,omitempty" db:"verified_at"`
 	IPAddress  string     `json:"ip_address" db:"ip_address"`
 	Factor     *Factor    `json:"factor,omitempty" belongs_to:"factor"`
+
+	// WebAuthnChallenge is the challenge the authenticator of a webauthn
+	// factor signs.
+	WebAuthnChallenge *string `json:"-" db:"web_authn_challenge"`
+
+	// OtpCode is the hash of the code sent to the phone of a phone factor.
+	OtpCode *string `json:"-" db:"otp_code"`
 }
 
 func (Challenge) TableName() string {
@@ -41,6 +48,18 @@ func FindChallengeByChallengeID(tx *storage.Connection, challengeID uuid.UUID) (
 	return challenge, nil
 }
 
+// FindLatestChallengeByFactorID returns the most recent challenge of a factor.
+func FindLatestChallengeByFactorID(tx *storage.Connection, factorID uuid.UUID) (*Challenge, error) {
+	obj := &Challenge{}
+	if err := tx.Q().Where("factor_id = ?", factorID).Order("created_at desc").First(obj); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, ChallengeNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding challenge")
+	}
+	return obj, nil
+}
+
 // Update the verification timestamp
 func (c *Challenge) Verify(tx *storage.Connection) error {
 	now := time.Now()
@@ -48,6 +48,13 @@ func TruncateAll(conn *storage.Connection) error {
 			(&pop.Model{Value: SAMLProvider{}}).TableName(),
 			(&pop.Model{Value: SAMLRelayState{}}).TableName(),
 			(&pop.Model{Value: FlowState{}}).TableName(),
+			(&pop.Model{Value: OAuthConsent{}}).TableName(),
+			(&pop.Model{Value: OAuthClient{}}).TableName(),
+			(&pop.Model{Value: ServiceAccount{}}).TableName(),
+			(&pop.Model{Value: DeviceAuthorization{}}).TableName(),
+			(&pop.Model{Value: PasskeyChallenge{}}).TableName(),
+			(&pop.Model{Value: RecoveryCode{}}).TableName(),
+			(&pop.Model{Value: TrustedDevice{}}).TableName(),
 		}
 
 		for _, tableName := range tables {
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+)
+
+// userCodeLength is the number of characters in a user code. With 20
+// possible characters this gives about 34 bits of entropy.
+const userCodeLength = 8
+
+// DeviceAuthorization is a pending login of a device, such as a CLI or a TV,
+// that the user approves on another device with the user code.
+type DeviceAuthorization struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	DeviceCodeHash string     `json:"-" db:"device_code_hash"`
+	UserCode       string     `json:"-" db:"user_code"`
+	Status         string     `json:"status" db:"status"`
+	UserID         *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	LastPolledAt   *time.Time `json:"-" db:"last_polled_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+func (DeviceAuthorization) TableName() string {
+	tableName := "device_authorizations"
+	return tableName
+}
+
+// NewDeviceAuthorization creates a new pending device authorization and its
+// device code, which is returned only once and stored hashed.
+func NewDeviceAuthorization() (*DeviceAuthorization, string, error) {
+	userCode, err := crypto.GenerateUserCode(userCodeLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	deviceCode := crypto.SecureToken(32)
+
+	return &DeviceAuthorization{
+		ID:             uuid.Must(uuid.NewV4()),
+		DeviceCodeHash: hashClientSecret(deviceCode),
+		UserCode:       userCode,
+		Status:         DeviceAuthorizationPending,
+	}, deviceCode, nil
+}
+
+// FormattedUserCode returns the user code as it is shown to users, e.g.
+// BDFH-JKLM.
+func (d *DeviceAuthorization) FormattedUserCode() string {
+	half := len(d.UserCode) / 2
+	return d.UserCode[:half] + "-" + d.UserCode[half:]
+}
+
+// IsExpired returns true if the device authorization can no longer be
+// approved or exchanged for tokens.
+func (d *DeviceAuthorization) IsExpired(expiryDuration time.Duration) bool {
+	return time.Now().After(d.CreatedAt.Add(expiryDuration))
+}
+
+// IsPolledTooOften returns true if the device polled again before the
+// interval has passed since its last poll.
+func (d *DeviceAuthorization) IsPolledTooOften(interval time.Duration) bool {
+	return d.LastPolledAt != nil && time.Since(*d.LastPolledAt) < interval
+}
+
+// UpdateLastPolledAt records that the device polled for tokens.
+func (d *DeviceAuthorization) UpdateLastPolledAt(tx *storage.Connection) error {
+	now := time.Now()
+	d.LastPolledAt = &now
+	return tx.UpdateOnly(d, "last_polled_at", "updated_at")
+}
+
+// Approve records that the user approved the login of the device. A
+// DeviceAuthorizationNotFoundError is returned if the device authorization
+// is no longer pending, e.g. because a concurrent request approved or denied
+// it.
+func (d *DeviceAuthorization) Approve(tx *storage.Connection, userID uuid.UUID) error {
+	return d.resolve(tx, DeviceAuthorizationApproved, &userID)
+}
+
+// Deny records that the user denied the login of the device. Like Approve it
+// only changes a pending device authorization.
+func (d *DeviceAuthorization) Deny(tx *storage.Connection) error {
+	return d.resolve(tx, DeviceAuthorizationDenied, nil)
+}
+
+func (d *DeviceAuthorization) resolve(tx *storage.Connection, status string, userID *uuid.UUID) error {
+	now := time.Now()
+	count, err := tx.RawQuery("UPDATE "+(&pop.Model{Value: DeviceAuthorization{}}).TableName()+" SET status = ?, user_id = ?, updated_at = ? WHERE id = ? AND status = ?", status, userID, now, d.ID, DeviceAuthorizationPending).ExecWithCount()
+	if err != nil {
+		return errors.Wrap(err, "error updating device authorization")
+	}
+
+	if count == 0 {
+		return DeviceAuthorizationNotFoundError{}
+	}
+
+	d.Status = status
+	d.UserID = userID
+	d.UpdatedAt = now
+	return nil
+}
+
+// Consume deletes an approved device authorization once it is exchanged for
+// tokens, so that it can only be exchanged once.
+func (d *DeviceAuthorization) Consume(tx *storage.Connection) error {
+	count, err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: DeviceAuthorization{}}).TableName()+" WHERE id = ? AND status = ?", d.ID, DeviceAuthorizationApproved).ExecWithCount()
+	if err != nil {
+		return errors.Wrap(err, "error consuming device authorization")
+	}
+
+	if count == 0 {
+		return DeviceAuthorizationNotFoundError{}
+	}
+
+	return nil
+}
+
+// NormalizeUserCode removes the formatting users may type with a user code.
+func NormalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, userCode)
+}
+
+// FindDeviceAuthorizationByDeviceCode finds a device authorization by the
+// device code the device polls with.
+func FindDeviceAuthorizationByDeviceCode(tx *storage.Connection, deviceCode string) (*DeviceAuthorization, error) {
+	return findDeviceAuthorization(tx, "device_code_hash = ?", hashClientSecret(deviceCode))
+}
+
+// FindDeviceAuthorizationByUserCode finds a device authorization by the user
+// code shown on the device.
+func FindDeviceAuthorizationByUserCode(tx *storage.Connection, userCode string) (*DeviceAuthorization, error) {
+	return findDeviceAuthorization(tx, "user_code = ?", NormalizeUserCode(userCode))
+}
+
+func findDeviceAuthorization(tx *storage.Connection, query string, args ...interface{}) (*DeviceAuthorization, error) {
+	deviceAuthorization := &DeviceAuthorization{}
+	if err := tx.Q().Where(query, args...).First(deviceAuthorization); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, DeviceAuthorizationNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding device authorization")
+	}
+
+	return deviceAuthorization, nil
+}
@@ -23,6 +23,20 @@ func IsNotFoundError(err error) bool {
 		return true
 	case FlowStateNotFoundError, *FlowStateNotFoundError:
 		return true
+	case OAuthClientNotFoundError, *OAuthClientNotFoundError:
+		return true
+	case OAuthConsentNotFoundError, *OAuthConsentNotFoundError:
+		return true
+	case ServiceAccountNotFoundError, *ServiceAccountNotFoundError:
+		return true
+	case DeviceAuthorizationNotFoundError, *DeviceAuthorizationNotFoundError:
+		return true
+	case PasskeyChallengeNotFoundError, *PasskeyChallengeNotFoundError:
+		return true
+	case RecoveryCodeNotFoundError, *RecoveryCodeNotFoundError:
+		return true
+	case TrustedDeviceNotFoundError, *TrustedDeviceNotFoundError:
+		return true
 	}
 	return false
 }
@@ -105,3 +119,59 @@ type FlowStateNotFoundError struct{}
 func (e FlowStateNotFoundError) Error() string {
 	return "Flow State not found"
 }
+
+// OAuthClientNotFoundError represents an error when an OAuth client can't be
+// found.
+type OAuthClientNotFoundError struct{}
+
+func (e OAuthClientNotFoundError) Error() string {
+	return "OAuth client not found"
+}
+
+// OAuthConsentNotFoundError represents an error when a user has not
+// consented to an OAuth client.
+type OAuthConsentNotFoundError struct{}
+
+func (e OAuthConsentNotFoundError) Error() string {
+	return "OAuth consent not found"
+}
+
+// ServiceAccountNotFoundError represents an error when a service account
+// can't be found.
+type ServiceAccountNotFoundError struct{}
+
+func (e ServiceAccountNotFoundError) Error() string {
+	return "Service account not found"
+}
+
+// DeviceAuthorizationNotFoundError represents an error when a device
+// authorization can't be found.
+type DeviceAuthorizationNotFoundError struct{}
+
+func (e DeviceAuthorizationNotFoundError) Error() string {
+	return "Device authorization not found"
+}
+
+// PasskeyChallengeNotFoundError represents an error when a passkey challenge
+// can't be found.
+type PasskeyChallengeNotFoundError struct{}
+
+func (e PasskeyChallengeNotFoundError) Error() string {
+	return "Passkey challenge not found"
+}
+
+// RecoveryCodeNotFoundError represents an error when an unused recovery code
+// can't be found.
+type RecoveryCodeNotFoundError struct{}
+
+func (e RecoveryCodeNotFoundError) Error() string {
+	return "Recovery code not found"
+}
+
+// TrustedDeviceNotFoundError represents an error when a trusted device can't
+// be found.
+type TrustedDeviceNotFoundError struct{}
+
+func (e TrustedDeviceNotFoundError) Error() string {
+	return "Trusted device not found"
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"bytes"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -9,7 +12,9 @@ import (
 	"github.com/gobuffalo/pop/v6"
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/storage"
+	"github.com/supabase/gotrue/internal/webauthn"
 )
 
 type FactorState int
@@ -29,7 +34,11 @@ func (factorState FactorState) String() string {
 	return ""
 }
 
-const TOTP = "totp"
+const (
+	TOTP     = "totp"
+	WebAuthn = "webauthn"
+	Phone    = "phone"
+)
 
 type AuthenticationMethod int
 
@@ -44,6 +53,12 @@ const (
 	MagicLink
 	EmailSignup
 	EmailChange
+	OAuthAuthorizationCode
+	DeviceCode
+	WebAuthnSignIn
+	PhoneSignIn
+	RecoveryCodeSignIn
+	TrustedDeviceSignIn
 )
 
 func (authMethod AuthenticationMethod) String() string {
@@ -68,6 +83,18 @@ func (authMethod AuthenticationMethod) String() string {
 		return "email/signup"
 	case EmailChange:
 		return "email_change"
+	case OAuthAuthorizationCode:
+		return "oauth_provider/authorization_code"
+	case DeviceCode:
+		return "device_code"
+	case WebAuthnSignIn:
+		return "webauthn"
+	case PhoneSignIn:
+		return "phone"
+	case RecoveryCodeSignIn:
+		return "recovery_code"
+	case TrustedDeviceSignIn:
+		return "trusted_device"
 	}
 	return ""
 }
@@ -97,6 +124,18 @@ func ParseAuthenticationMethod(authMethod string) (AuthenticationMethod, error)
 		return EmailSignup, nil
 	case "email_change":
 		return EmailChange, nil
+	case "oauth_provider/authorization_code":
+		return OAuthAuthorizationCode, nil
+	case "device_code":
+		return DeviceCode, nil
+	case "webauthn":
+		return WebAuthnSignIn, nil
+	case "phone":
+		return PhoneSignIn, nil
+	case "recovery_code":
+		return RecoveryCodeSignIn, nil
+	case "trusted_device":
+		return TrustedDeviceSignIn, nil
 	}
 	return 0, fmt.Errorf("unsupported authentication method %q", authMethod)
 }
@@ -112,6 +151,53 @@ type Factor struct {
 	Secret       string      `json:"-" db:"secret"`
 	FactorType   string      `json:"factor_type" db:"factor_type"`
 	Challenge    []Challenge `json:"-" has_many:"challenges"`
+
+	// WebAuthnCredential is the public key credential of a verified
+	// webauthn factor.
+	WebAuthnCredential *WebAuthnCredential `json:"-" db:"web_authn_credential"`
+
+	// Phone is the number codes of a phone factor are sent to.
+	Phone storage.NullString `json:"phone,omitempty" db:"phone"`
+
+	// LastTOTPCounter is the time step of the last accepted TOTP code.
+	LastTOTPCounter *int64 `json:"-" db:"last_totp_counter"`
+
+	// FailedVerificationAttempts counts the consecutive failed attempts to
+	// verify the factor. The factor is locked until LockedUntil when there
+	// are too many.
+	FailedVerificationAttempts int        `json:"-" db:"failed_verification_attempts"`
+	LockedUntil                *time.Time `json:"-" db:"locked_until"`
+}
+
+// WebAuthnCredential stores a webauthn.Credential as JSON.
+type WebAuthnCredential struct {
+	webauthn.Credential
+}
+
+func (c *WebAuthnCredential) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return driver.Value(string(data)), nil
+}
+
+func (c *WebAuthnCredential) Scan(src interface{}) error {
+	var source []byte
+	switch v := src.(type) {
+	case string:
+		source = []byte(v)
+	case []byte:
+		source = v
+	default:
+		return errors.New("invalid data type for WebAuthnCredential")
+	}
+
+	return json.Unmarshal(source, c)
 }
 
 func (Factor) TableName() string {
@@ -119,20 +205,92 @@ func (Factor) TableName() string {
 	return tableName
 }
 
-func NewFactor(user *User, friendlyName string, factorType string, state FactorState, secret string) (*Factor, error) {
+// encryptFactorSecret encrypts the secret of a factor with secretKeys, unless
+// they are nil. The ciphertext is bound to the factor, so that it can't be
+// copied to another factor.
+func encryptFactorSecret(secretKeys *crypto.EnvelopeKeys, factorID uuid.UUID, secret string) (string, error) {
+	if secret == "" || secretKeys == nil {
+		return secret, nil
+	}
+
+	encrypted, err := secretKeys.Encrypt([]byte(secret), factorID.Bytes())
+	if err != nil {
+		return "", errors.Wrap(err, "error encrypting factor secret")
+	}
+	return encrypted, nil
+}
+
+// NewFactor initializes a new factor. Its secret is encrypted with
+// secretKeys, or stored unencrypted when they are nil.
+func NewFactor(secretKeys *crypto.EnvelopeKeys, user *User, friendlyName string, factorType string, state FactorState, secret string) (*Factor, error) {
 	id := uuid.Must(uuid.NewV4())
 
+	encryptedSecret, err := encryptFactorSecret(secretKeys, id, secret)
+	if err != nil {
+		return nil, err
+	}
+
 	factor := &Factor{
 		UserID:       user.ID,
 		ID:           id,
 		Status:       state.String(),
 		FriendlyName: friendlyName,
-		Secret:       secret,
+		Secret:       encryptedSecret,
 		FactorType:   factorType,
 	}
 	return factor, nil
 }
 
+// GetSecret returns the secret of the factor decrypted with secretKeys.
+// Secrets stored before encryption was configured are returned as they are.
+func (f *Factor) GetSecret(secretKeys *crypto.EnvelopeKeys) (string, error) {
+	if !crypto.IsEnvelope(f.Secret) {
+		return f.Secret, nil
+	}
+
+	if secretKeys == nil {
+		return "", errors.New("factor secret is encrypted but no encryption keys are configured")
+	}
+
+	secret, err := secretKeys.Decrypt(f.Secret, f.ID.Bytes())
+	if err != nil {
+		return "", errors.Wrap(err, "error decrypting factor secret")
+	}
+	return string(secret), nil
+}
+
+// EncryptFactorSecrets encrypts the secrets of factors that are stored
+// unencrypted with secretKeys, and moves secrets encrypted with another key
+// to the active key. It returns the number of updated factors.
+func EncryptFactorSecrets(tx *storage.Connection, secretKeys *crypto.EnvelopeKeys) (int, error) {
+	if secretKeys == nil {
+		return 0, nil
+	}
+
+	factors := []*Factor{}
+	if err := tx.Q().Where("secret is not null and secret <> '' and secret not like ?", crypto.EnvelopePrefix(secretKeys.ActiveKeyID)+"%").All(&factors); err != nil {
+		return 0, errors.Wrap(err, "error finding factors to encrypt")
+	}
+
+	for _, factor := range factors {
+		var err error
+		if crypto.IsEnvelope(factor.Secret) {
+			factor.Secret, err = secretKeys.Rewrap(factor.Secret)
+		} else {
+			factor.Secret, err = encryptFactorSecret(secretKeys, factor.ID, factor.Secret)
+		}
+		if err != nil {
+			return 0, errors.Wrapf(err, "error encrypting secret of factor %s", factor.ID)
+		}
+
+		if err := tx.UpdateOnly(factor, "secret", "updated_at"); err != nil {
+			return 0, errors.Wrap(err, "error updating factor secret")
+		}
+	}
+
+	return len(factors), nil
+}
+
 // FindFactorsByUser returns all factors belonging to a user ordered by timestamp
 func FindFactorsByUser(tx *storage.Connection, user *User) ([]*Factor, error) {
 	factors := []*Factor{}
@@ -165,6 +323,39 @@ func findFactor(tx *storage.Connection, query string, args ...interface{}) (*Fac
 	return obj, nil
 }
 
+// FindVerifiedWebAuthnCredentials returns the credentials of the verified
+// webauthn factors of a user.
+func FindVerifiedWebAuthnCredentials(tx *storage.Connection, user *User) ([]*webauthn.Credential, error) {
+	factors, err := FindFactorsByUser(tx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := []*webauthn.Credential{}
+	for _, factor := range factors {
+		if factor.FactorType == WebAuthn && factor.IsVerified() && factor.WebAuthnCredential != nil {
+			credentials = append(credentials, &factor.WebAuthnCredential.Credential)
+		}
+	}
+	return credentials, nil
+}
+
+// FindVerifiedWebAuthnFactorByCredentialID returns the verified webauthn
+// factor of a user with the credential.
+func FindVerifiedWebAuthnFactorByCredentialID(tx *storage.Connection, user *User, credentialID []byte) (*Factor, error) {
+	factors, err := FindFactorsByUser(tx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, factor := range factors {
+		if factor.FactorType == WebAuthn && factor.IsVerified() && factor.WebAuthnCredential != nil && bytes.Equal(factor.WebAuthnCredential.ID, credentialID) {
+			return factor, nil
+		}
+	}
+	return nil, FactorNotFoundError{}
+}
+
 func DeleteUnverifiedFactors(tx *storage.Connection, user *User) error {
 	if err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: Factor{}}).TableName()+" WHERE user_id = ? and status = ?", user.ID, FactorStateUnverified.String()).Exec(); err != nil {
 		return err
@@ -185,23 +376,115 @@ func (f *Factor) UpdateStatus(tx *storage.Connection, state FactorState) error {
 	return tx.UpdateOnly(f, "status", "updated_at")
 }
 
+// UpdateWebAuthnCredential stores the credential of a webauthn factor, e.g.
+// when its signature counter changed
+func (f *Factor) UpdateWebAuthnCredential(tx *storage.Connection, credential *webauthn.Credential) error {
+	f.WebAuthnCredential = &WebAuthnCredential{Credential: *credential}
+	return tx.UpdateOnly(f, "web_authn_credential", "updated_at")
+}
+
+// UpdateLastTOTPCounter records the time step of an accepted TOTP code. It
+// returns false when a code of the same or a later time step has been
+// accepted before, so that a code can't be replayed.
+func (f *Factor) UpdateLastTOTPCounter(tx *storage.Connection, counter int64) (bool, error) {
+	count, err := tx.RawQuery("UPDATE "+(&pop.Model{Value: Factor{}}).TableName()+" SET last_totp_counter = ?, updated_at = ? WHERE id = ? AND (last_totp_counter IS NULL OR last_totp_counter < ?)", counter, time.Now(), f.ID, counter).ExecWithCount()
+	if err != nil {
+		return false, errors.Wrap(err, "error updating last TOTP counter")
+	}
+
+	if count == 0 {
+		return false, nil
+	}
+
+	f.LastTOTPCounter = &counter
+	return true, nil
+}
+
+// IsLocked returns whether the factor is locked after too many failed
+// verification attempts.
+func (f *Factor) IsLocked(now time.Time) bool {
+	return f.LockedUntil != nil && now.Before(*f.LockedUntil)
+}
+
+// RecordFailedVerification counts a failed attempt to verify the factor. It
+// locks the factor for lockoutDuration when maxAttempts consecutive
+// attempts have failed, and returns whether it did.
+func (f *Factor) RecordFailedVerification(tx *storage.Connection, maxAttempts int, lockoutDuration time.Duration) (bool, error) {
+	// the attempts are counted by the database, as concurrent attempts
+	// would otherwise overwrite each other's count
+	if err := tx.RawQuery("UPDATE "+(&pop.Model{Value: Factor{}}).TableName()+" SET failed_verification_attempts = failed_verification_attempts + 1, updated_at = ? WHERE id = ?", time.Now(), f.ID).Exec(); err != nil {
+		return false, errors.Wrap(err, "error counting failed verification attempts")
+	}
+
+	if err := tx.Reload(f); err != nil {
+		return false, errors.Wrap(err, "error reloading factor")
+	}
+
+	if maxAttempts <= 0 || f.FailedVerificationAttempts < maxAttempts {
+		return false, nil
+	}
+
+	lockedUntil := time.Now().Add(lockoutDuration)
+	f.LockedUntil = &lockedUntil
+	f.FailedVerificationAttempts = 0
+	if err := tx.UpdateOnly(f, "failed_verification_attempts", "locked_until", "updated_at"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResetFailedVerifications clears the failed verification attempts after
+// the factor has been verified.
+func (f *Factor) ResetFailedVerifications(tx *storage.Connection) error {
+	f.FailedVerificationAttempts = 0
+	f.LockedUntil = nil
+	return tx.UpdateOnly(f, "failed_verification_attempts", "locked_until", "updated_at")
+}
+
 // UpdateFactorType modifies the factor type
 func (f *Factor) UpdateFactorType(tx *storage.Connection, factorType string) error {
 	f.FactorType = factorType
 	return tx.UpdateOnly(f, "factor_type", "updated_at")
 }
 
+// DowngradeSessionsToAAL1 downgrades the sessions verified with the factor
+// when it is removed. When it was the last verified factor of the user, the
+// sessions verified with a recovery code are downgraded too, and the
+// recovery codes are deleted.
 func (f *Factor) DowngradeSessionsToAAL1(tx *storage.Connection) error {
+	amrTable := (&pop.Model{Value: AMRClaim{}}).TableName()
+
 	sessions, err := FindSessionsByFactorID(tx, f.ID)
 	if err != nil {
 		return err
 	}
 	for _, session := range sessions {
-		if err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: AMRClaim{}}).TableName()+" WHERE session_id = ? AND authentication_method = ?", session.ID, f.FactorType).Exec(); err != nil {
+		if err := tx.RawQuery("DELETE FROM "+amrTable+" WHERE session_id = ? AND authentication_method IN (?, ?)", session.ID, f.FactorType, TrustedDeviceSignIn.String()).Exec(); err != nil {
 			return err
 		}
 	}
-	return updateFactorAssociatedSessions(tx, f.UserID, f.ID, AAL1.String())
+	if err := updateFactorAssociatedSessions(tx, f.UserID, f.ID, AAL1.String()); err != nil {
+		return err
+	}
+
+	verifiedFactors, err := tx.Q().Where("user_id = ? and id <> ? and status = ?", f.UserID, f.ID, FactorStateVerified.String()).Count(&Factor{})
+	if err != nil {
+		return err
+	}
+	if verifiedFactors > 0 {
+		return nil
+	}
+
+	// sessions verified with a recovery code are not associated with a
+	// factor
+	sessionTable := (&pop.Model{Value: Session{}}).TableName()
+	if err := tx.RawQuery("DELETE FROM "+amrTable+" WHERE authentication_method IN (?, ?) AND session_id IN (SELECT id FROM "+sessionTable+" WHERE user_id = ?)", RecoveryCodeSignIn.String(), TrustedDeviceSignIn.String(), f.UserID).Exec(); err != nil {
+		return err
+	}
+	if err := tx.RawQuery("UPDATE "+sessionTable+" SET aal = ? WHERE user_id = ? AND aal = ?", AAL1.String(), f.UserID, AAL2.String()).Exec(); err != nil {
+		return err
+	}
+	return deleteRecoveryCodesByUserID(tx, f.UserID)
 }
 
 func (f *Factor) IsOwnedBy(user *User) bool {
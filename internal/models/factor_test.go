@@ -1,14 +1,17 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/storage"
 	"github.com/supabase/gotrue/internal/storage/test"
 )
@@ -44,13 +47,13 @@ func (ts *FactorTestSuite) TestFindFactorByFactorID() {
 }
 
 func (ts *FactorTestSuite) createFactor() *Factor {
-	user, err := NewUser("", "agenericemail@gmail.com", "secret", "test", nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "", "agenericemail@gmail.com", "secret", "test", nil)
 	require.NoError(ts.T(), err)
 
 	err = ts.db.Create(user)
 	require.NoError(ts.T(), err)
 
-	factor, err := NewFactor(user, "asimplename", TOTP, FactorStateUnverified, "topsecret")
+	factor, err := NewFactor(nil, user, "asimplename", TOTP, FactorStateUnverified, "topsecret")
 	require.NoError(ts.T(), err)
 
 	err = ts.db.Create(factor)
@@ -60,10 +63,10 @@ func (ts *FactorTestSuite) createFactor() *Factor {
 }
 func (ts *FactorTestSuite) TestUpdateStatus() {
 	newFactorStatus := FactorStateVerified
-	u, err := NewUser("", "", "", "", nil)
+	u, err := NewUser(crypto.DefaultPasswordHashParams, "", "", "", "", nil)
 	require.NoError(ts.T(), err)
 
-	f, err := NewFactor(u, "", TOTP, FactorStateUnverified, "some-secret")
+	f, err := NewFactor(nil, u, "", TOTP, FactorStateUnverified, "some-secret")
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), f.UpdateStatus(ts.db, newFactorStatus))
 	require.Equal(ts.T(), newFactorStatus.String(), f.Status)
@@ -71,20 +74,20 @@ func (ts *FactorTestSuite) TestUpdateStatus() {
 
 func (ts *FactorTestSuite) TestUpdateFriendlyName() {
 	newSimpleName := "newFactorName"
-	u, err := NewUser("", "", "", "", nil)
+	u, err := NewUser(crypto.DefaultPasswordHashParams, "", "", "", "", nil)
 	require.NoError(ts.T(), err)
 
-	f, err := NewFactor(u, "A1B2C3", TOTP, FactorStateUnverified, "some-secret")
+	f, err := NewFactor(nil, u, "A1B2C3", TOTP, FactorStateUnverified, "some-secret")
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), f.UpdateFriendlyName(ts.db, newSimpleName))
 	require.Equal(ts.T(), newSimpleName, f.FriendlyName)
 }
 
 func (ts *FactorTestSuite) TestEncodedFactorDoesNotLeakSecret() {
-	u, err := NewUser("", "", "", "", nil)
+	u, err := NewUser(crypto.DefaultPasswordHashParams, "", "", "", "", nil)
 	require.NoError(ts.T(), err)
 
-	f, err := NewFactor(u, "A1B2C3", TOTP, FactorStateUnverified, "some-secret")
+	f, err := NewFactor(nil, u, "A1B2C3", TOTP, FactorStateUnverified, "some-secret")
 	require.NoError(ts.T(), err)
 	encodedFactor, err := json.Marshal(f)
 	require.NoError(ts.T(), err)
@@ -92,3 +95,109 @@ func (ts *FactorTestSuite) TestEncodedFactorDoesNotLeakSecret() {
 	json.Unmarshal(encodedFactor, &decodedFactor)
 	require.Equal(ts.T(), decodedFactor.Secret, "")
 }
+
+func (ts *FactorTestSuite) TestFactorSecretEncryption() {
+	legacy := ts.createFactor()
+
+	secretKeys := crypto.NewEnvelopeKeys("key-1", map[string][]byte{
+		"key-1": bytes.Repeat([]byte{1}, 32),
+	})
+
+	user, err := FindUserByID(ts.db, legacy.UserID)
+	require.NoError(ts.T(), err)
+
+	f, err := NewFactor(secretKeys, user, "encrypted", TOTP, FactorStateUnverified, "topsecret")
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), ts.db.Create(f))
+	require.NotContains(ts.T(), f.Secret, "topsecret")
+
+	f, err = FindFactorByFactorID(ts.db, f.ID)
+	require.NoError(ts.T(), err)
+	secret, err := f.GetSecret(secretKeys)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), "topsecret", secret)
+
+	// secrets stored before encryption was configured still work and are
+	// encrypted by EncryptFactorSecrets
+	legacy, err = FindFactorByFactorID(ts.db, legacy.ID)
+	require.NoError(ts.T(), err)
+	secret, err = legacy.GetSecret(secretKeys)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), "topsecret", secret)
+
+	count, err := EncryptFactorSecrets(ts.db, secretKeys)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 1, count)
+
+	legacy, err = FindFactorByFactorID(ts.db, legacy.ID)
+	require.NoError(ts.T(), err)
+	require.NotContains(ts.T(), legacy.Secret, "topsecret")
+
+	// rotating the key moves all secrets to the new key
+	secretKeys = crypto.NewEnvelopeKeys("key-2", map[string][]byte{
+		"key-1": bytes.Repeat([]byte{1}, 32),
+		"key-2": bytes.Repeat([]byte{2}, 32),
+	})
+
+	count, err = EncryptFactorSecrets(ts.db, secretKeys)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 2, count)
+
+	secretKeys = crypto.NewEnvelopeKeys("key-2", map[string][]byte{
+		"key-2": bytes.Repeat([]byte{2}, 32),
+	})
+
+	for _, id := range []uuid.UUID{f.ID, legacy.ID} {
+		factor, err := FindFactorByFactorID(ts.db, id)
+		require.NoError(ts.T(), err)
+		secret, err := factor.GetSecret(secretKeys)
+		require.NoError(ts.T(), err)
+		require.Equal(ts.T(), "topsecret", secret)
+	}
+
+	// a secret can't be moved to another factor
+	legacy, err = FindFactorByFactorID(ts.db, legacy.ID)
+	require.NoError(ts.T(), err)
+	f.Secret = legacy.Secret
+	_, err = f.GetSecret(secretKeys)
+	require.Error(ts.T(), err)
+}
+
+func (ts *FactorTestSuite) TestUpdateLastTOTPCounter() {
+	f := ts.createFactor()
+
+	accepted, err := f.UpdateLastTOTPCounter(ts.db, 100)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), accepted)
+
+	for _, counter := range []int64{100, 99} {
+		accepted, err = f.UpdateLastTOTPCounter(ts.db, counter)
+		require.NoError(ts.T(), err)
+		require.False(ts.T(), accepted, "Counter %d was accepted after 100", counter)
+	}
+
+	accepted, err = f.UpdateLastTOTPCounter(ts.db, 101)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), accepted)
+}
+
+func (ts *FactorTestSuite) TestRecordFailedVerification() {
+	f := ts.createFactor()
+
+	for i := 0; i < 2; i++ {
+		locked, err := f.RecordFailedVerification(ts.db, 3, time.Minute)
+		require.NoError(ts.T(), err)
+		require.False(ts.T(), locked)
+	}
+	require.False(ts.T(), f.IsLocked(time.Now()))
+
+	locked, err := f.RecordFailedVerification(ts.db, 3, time.Minute)
+	require.NoError(ts.T(), err)
+	require.True(ts.T(), locked)
+	require.True(ts.T(), f.IsLocked(time.Now()))
+	require.False(ts.T(), f.IsLocked(time.Now().Add(2*time.Minute)))
+
+	require.NoError(ts.T(), f.ResetFailedVerifications(ts.db))
+	require.False(ts.T(), f.IsLocked(time.Now()))
+	require.Equal(ts.T(), 0, f.FailedVerificationAttempts)
+}
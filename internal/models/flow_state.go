@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gobuffalo/pop/v6"
 	"github.com/pkg/errors"
 	"github.com/supabase/gotrue/internal/storage"
 
@@ -30,6 +31,18 @@ type FlowState struct {
 	ProviderRefreshToken string     `json:"provider_refresh_token" db:"provider_refresh_token"`
 	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Set when the flow state is an authorization request from a
+	// third-party OAuth client.
+	OAuthClientID *uuid.UUID         `json:"oauth_client_id,omitempty" db:"oauth_client_id"`
+	RedirectURI   storage.NullString `json:"redirect_uri,omitempty" db:"redirect_uri"`
+	Scopes        storage.NullString `json:"scopes,omitempty" db:"scopes"`
+	OAuthState    storage.NullString `json:"oauth_state,omitempty" db:"oauth_state"`
+	Nonce         storage.NullString `json:"nonce,omitempty" db:"nonce"`
+
+	// RedirectURIProvided is false when the authorization request omitted
+	// the redirect URI and the only one registered for the client is used.
+	RedirectURIProvided bool `json:"-" db:"redirect_uri_provided"`
 }
 
 type CodeChallengeMethod int
@@ -110,6 +123,48 @@ func NewFlowStateWithUserID(tx *storage.Connection, providerType, codeChallenge
 	return tx.Create(flowState)
 }
 
+// NewOAuthAuthorizationFlowState creates a flow state for an authorization
+// request from a third-party OAuth client. The user is set once they consent
+// to the request.
+func NewOAuthAuthorizationFlowState(clientID uuid.UUID, redirectURI string, redirectURIProvided bool, scopes, state, nonce, codeChallenge string, codeChallengeMethod CodeChallengeMethod) *FlowState {
+	return &FlowState{
+		ID:                   uuid.Must(uuid.NewV4()),
+		ProviderType:         OAuthAuthorizationCode.String(),
+		CodeChallenge:        codeChallenge,
+		CodeChallengeMethod:  codeChallengeMethod.String(),
+		AuthCode:             uuid.Must(uuid.NewV4()).String(),
+		AuthenticationMethod: OAuthAuthorizationCode.String(),
+		OAuthClientID:        &clientID,
+		RedirectURI:          storage.NullString(redirectURI),
+		RedirectURIProvided:  redirectURIProvided,
+		Scopes:               storage.NullString(scopes),
+		OAuthState:           storage.NullString(state),
+		Nonce:                storage.NullString(nonce),
+	}
+}
+
+// IsOAuthAuthorization returns true if the flow state is an authorization
+// request from a third-party OAuth client.
+func (f *FlowState) IsOAuthAuthorization() bool {
+	return f.OAuthClientID != nil
+}
+
+// Consume deletes the flow state so that its authorization code can only be
+// exchanged once. A FlowStateNotFoundError is returned if a concurrent
+// request already consumed it.
+func (f *FlowState) Consume(tx *storage.Connection) error {
+	count, err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: FlowState{}}).TableName()+" WHERE id = ?", f.ID).ExecWithCount()
+	if err != nil {
+		return errors.Wrap(err, "error consuming flow state")
+	}
+
+	if count == 0 {
+		return FlowStateNotFoundError{}
+	}
+
+	return nil
+}
+
 func FindFlowStateByAuthCode(tx *storage.Connection, authCode string) (*FlowState, error) {
 	obj := &FlowState{}
 	if err := tx.Eager().Q().Where("auth_code = ?", authCode).First(obj); err != nil {
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/storage"
 	"github.com/supabase/gotrue/internal/storage/test"
 )
@@ -50,7 +51,7 @@ func (ts *AccountLinkingTestSuite) TestCreateAccountDecisionNoAccounts() {
 }
 
 func (ts *AccountLinkingTestSuite) TestCreateAccountDecisionWithAccounts() {
-	userA, err := NewUser("", "test@example.com", "", "authenticated", nil)
+	userA, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userA))
 	identityA, err := NewIdentity(userA, "provider", map[string]interface{}{
@@ -60,7 +61,7 @@ func (ts *AccountLinkingTestSuite) TestCreateAccountDecisionWithAccounts() {
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(identityA))
 
-	userB, err := NewUser("", "test@samltest.id", "", "authenticated", nil)
+	userB, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@samltest.id", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userB))
 
@@ -97,7 +98,7 @@ func (ts *AccountLinkingTestSuite) TestCreateAccountDecisionWithAccounts() {
 }
 
 func (ts *AccountLinkingTestSuite) TestAccountExists() {
-	userA, err := NewUser("", "test@example.com", "", "authenticated", nil)
+	userA, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userA))
 	identityA, err := NewIdentity(userA, "provider", map[string]interface{}{
@@ -115,7 +116,7 @@ func (ts *AccountLinkingTestSuite) TestAccountExists() {
 }
 
 func (ts *AccountLinkingTestSuite) TestLinkAccountExists() {
-	userA, err := NewUser("", "test@example.com", "", "authenticated", nil)
+	userA, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userA))
 	identityA, err := NewIdentity(userA, "provider", map[string]interface{}{
@@ -125,7 +126,7 @@ func (ts *AccountLinkingTestSuite) TestLinkAccountExists() {
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(identityA))
 
-	userB, err := NewUser("", "test@samltest.id", "", "authenticated", nil)
+	userB, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@samltest.id", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userB))
 
@@ -179,7 +180,7 @@ func (ts *AccountLinkingTestSuite) TestLinkAccountExists() {
 }
 
 func (ts *AccountLinkingTestSuite) TestMultipleAccounts() {
-	userA, err := NewUser("", "test@example.com", "", "authenticated", nil)
+	userA, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userA))
 	identityA, err := NewIdentity(userA, "provider", map[string]interface{}{
@@ -189,7 +190,7 @@ func (ts *AccountLinkingTestSuite) TestMultipleAccounts() {
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(identityA))
 
-	userB, err := NewUser("", "test-b@example.com", "", "authenticated", nil)
+	userB, err := NewUser(crypto.DefaultPasswordHashParams, "", "test-b@example.com", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(userB))
 	identityB, err := NewIdentity(userB, "provider", map[string]interface{}{
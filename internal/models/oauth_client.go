@@ -0,0 +1,250 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// OAuthClient is a third-party application that can obtain tokens on behalf
+// of users through the authorization_code grant.
+type OAuthClient struct {
+	ID               uuid.UUID          `json:"client_id" db:"id"`
+	Name             string             `json:"name" db:"name"`
+	ClientSecretHash storage.NullString `json:"-" db:"client_secret_hash"`
+	RedirectURIs     StringList         `json:"redirect_uris" db:"redirect_uris"`
+	Scopes           StringList         `json:"scopes" db:"scopes"`
+	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+func (OAuthClient) TableName() string {
+	tableName := "oauth_clients"
+	return tableName
+}
+
+// NewOAuthClient creates a new client. Confidential clients are issued a
+// secret, which is returned only once and stored hashed.
+func NewOAuthClient(name string, redirectURIs, scopes []string, confidential bool) (*OAuthClient, string) {
+	client := &OAuthClient{
+		ID:           uuid.Must(uuid.NewV4()),
+		Name:         name,
+		RedirectURIs: redirectURIs,
+		Scopes:       scopes,
+	}
+
+	secret := ""
+	if confidential {
+		secret = client.GenerateSecret()
+	}
+
+	return client, secret
+}
+
+// IsConfidential returns true if the client authenticates with a secret.
+func (c *OAuthClient) IsConfidential() bool {
+	return c.ClientSecretHash != ""
+}
+
+// GenerateSecret sets a new random secret on the client and returns it.
+func (c *OAuthClient) GenerateSecret() string {
+	secret := crypto.SecureToken(32)
+	c.ClientSecretHash = storage.NullString(hashClientSecret(secret))
+	return secret
+}
+
+// VerifySecret checks the provided secret against the stored hash.
+func (c *OAuthClient) VerifySecret(secret string) bool {
+	if !c.IsConfidential() || secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(c.ClientSecretHash), []byte(hashClientSecret(secret))) == 1
+}
+
+// HasRedirectURI returns true if the redirect URI exactly matches one of the
+// registered redirect URIs.
+func (c *OAuthClient) HasRedirectURI(redirectURI string) bool {
+	for _, uri := range c.RedirectURIs {
+		if uri == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsScopes returns true if all of the provided scopes are allowed for
+// the client.
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	return c.Scopes.ContainsAll(scopes)
+}
+
+// hashClientSecret hashes client secrets. They are high-entropy random
+// values so a fast hash is sufficient.
+func hashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// FindOAuthClientByID finds a client by its client_id.
+func FindOAuthClientByID(tx *storage.Connection, id uuid.UUID) (*OAuthClient, error) {
+	client := &OAuthClient{}
+	if err := tx.Q().Where("id = ?", id).First(client); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, OAuthClientNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding oauth client")
+	}
+
+	return client, nil
+}
+
+// FindAllOAuthClients returns all registered clients.
+func FindAllOAuthClients(tx *storage.Connection) ([]OAuthClient, error) {
+	var clients []OAuthClient
+
+	if err := tx.Q().Order("created_at asc").All(&clients); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "error finding oauth clients")
+	}
+
+	return clients, nil
+}
+
+// OAuthConsent records the scopes a user has agreed to share with a client.
+type OAuthConsent struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	ClientID  uuid.UUID `json:"client_id" db:"client_id"`
+	Scopes    string    `json:"scopes" db:"scopes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (OAuthConsent) TableName() string {
+	tableName := "oauth_consents"
+	return tableName
+}
+
+// FindOAuthConsent finds the consent a user has given to a client.
+func FindOAuthConsent(tx *storage.Connection, userID, clientID uuid.UUID) (*OAuthConsent, error) {
+	consent := &OAuthConsent{}
+	if err := tx.Q().Where("user_id = ? and client_id = ?", userID, clientID).First(consent); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, OAuthConsentNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding oauth consent")
+	}
+
+	return consent, nil
+}
+
+// Covers returns true if the consent includes all of the provided scopes.
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	return StringList(ParseScopes(c.Scopes)).ContainsAll(scopes)
+}
+
+// GrantOAuthConsent records that the user consents to sharing the scopes with
+// the client, in addition to any previously consented scopes.
+func GrantOAuthConsent(tx *storage.Connection, userID, clientID uuid.UUID, scopes []string) error {
+	consent, err := FindOAuthConsent(tx, userID, clientID)
+	if err != nil && !IsNotFoundError(err) {
+		return err
+	}
+
+	if consent == nil {
+		return tx.Create(&OAuthConsent{
+			ID:       uuid.Must(uuid.NewV4()),
+			UserID:   userID,
+			ClientID: clientID,
+			Scopes:   FormatScopes(scopes),
+		})
+	}
+
+	merged := ParseScopes(consent.Scopes)
+	for _, scope := range scopes {
+		if !StringList(merged).ContainsAll([]string{scope}) {
+			merged = append(merged, scope)
+		}
+	}
+
+	consent.Scopes = FormatScopes(merged)
+
+	return tx.UpdateOnly(consent, "scopes", "updated_at")
+}
+
+// ParseScopes splits a space-delimited OAuth scope string.
+func ParseScopes(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// FormatScopes joins scopes into a space-delimited OAuth scope string.
+func FormatScopes(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// StringList is a list of strings stored as a JSON array.
+type StringList []string
+
+// ContainsAll returns true if every value is in the list.
+func (l StringList) ContainsAll(values []string) bool {
+	for _, value := range values {
+		found := false
+		for _, item := range l {
+			if item == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = StringList{}
+	}
+
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return driver.Value(""), err
+	}
+	return driver.Value(string(data)), nil
+}
+
+func (l *StringList) Scan(src interface{}) error {
+	var source []byte
+	switch v := src.(type) {
+	case string:
+		source = []byte(v)
+	case []byte:
+		source = v
+	case nil:
+		source = []byte("")
+	default:
+		return errors.New("invalid data type for StringList")
+	}
+
+	if len(source) == 0 {
+		source = []byte("[]")
+	}
+	return json.Unmarshal(source, (*[]string)(l))
+}
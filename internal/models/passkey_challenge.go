@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// PasskeyChallenge is the challenge signed by a passkey to sign in. Unlike a
+// Challenge it does not belong to a factor, as the user is only known once
+// the passkey is used.
+type PasskeyChallenge struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Challenge string    `json:"-" db:"challenge"`
+	IPAddress string    `json:"-" db:"ip_address"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func (PasskeyChallenge) TableName() string {
+	tableName := "passkey_challenges"
+	return tableName
+}
+
+func NewPasskeyChallenge(ipAddress string) *PasskeyChallenge {
+	return &PasskeyChallenge{
+		ID:        uuid.Must(uuid.NewV4()),
+		Challenge: crypto.SecureToken(32),
+		IPAddress: ipAddress,
+	}
+}
+
+// FindPasskeyChallengeByID finds a passkey challenge. If forUpdate is set the
+// row is locked so that the challenge can only be used once.
+func FindPasskeyChallengeByID(tx *storage.Connection, id uuid.UUID, forUpdate bool) (*PasskeyChallenge, error) {
+	challenge := &PasskeyChallenge{}
+
+	var err error
+	if forUpdate {
+		err = tx.RawQuery("select * from "+challenge.TableName()+" where id = ? limit 1 for update", id).First(challenge)
+	} else {
+		err = tx.Q().Where("id = ?", id).First(challenge)
+	}
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, PasskeyChallengeNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding passkey challenge")
+	}
+
+	return challenge, nil
+}
+
+func (c *PasskeyChallenge) HasExpired(expiryDuration float64) bool {
+	return time.Now().After(c.GetExpiryTime(expiryDuration))
+}
+
+func (c *PasskeyChallenge) GetExpiryTime(expiryDuration float64) time.Time {
+	return c.CreatedAt.Add(time.Second * time.Duration(expiryDuration))
+}
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+const (
+	// RecoveryCodeCount is the number of recovery codes generated at once.
+	RecoveryCodeCount = 10
+
+	recoveryCodeLength    = 16
+	recoveryCodeGroupSize = 4
+)
+
+// RecoveryCode is a one-time code a user can redeem in place of a factor
+// challenge, e.g. after losing their authenticator. Only its hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"-" db:"user_id"`
+	CodeHash  string     `json:"-" db:"code_hash"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+}
+
+func (RecoveryCode) TableName() string {
+	tableName := "mfa_recovery_codes"
+	return tableName
+}
+
+// normalizeRecoveryCode removes the separators and whitespace users may
+// type in, so that codes are hashed the same way.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+func hashRecoveryCode(code string) string {
+	return hashClientSecret(normalizeRecoveryCode(code))
+}
+
+// formatRecoveryCode splits a code into groups, e.g. ABCD-EFGH-JKLM-NPQR.
+func formatRecoveryCode(code string) string {
+	groups := []string{}
+	for i := 0; i < len(code); i += recoveryCodeGroupSize {
+		end := i + recoveryCodeGroupSize
+		if end > len(code) {
+			end = len(code)
+		}
+		groups = append(groups, code[i:end])
+	}
+	return strings.Join(groups, "-")
+}
+
+// GenerateRecoveryCodes replaces the recovery codes of a user with a new set
+// of codes and returns them. The codes are returned only once.
+func GenerateRecoveryCodes(tx *storage.Connection, user *User) ([]string, error) {
+	if err := DeleteRecoveryCodes(tx, user); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		code, err := crypto.GenerateUserCode(recoveryCodeLength)
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCode := &RecoveryCode{
+			ID:       uuid.Must(uuid.NewV4()),
+			UserID:   user.ID,
+			CodeHash: hashRecoveryCode(code),
+		}
+		if err := tx.Create(recoveryCode); err != nil {
+			return nil, errors.Wrap(err, "error creating recovery code")
+		}
+
+		codes = append(codes, formatRecoveryCode(code))
+	}
+
+	return codes, nil
+}
+
+// DeleteRecoveryCodes revokes all recovery codes of a user.
+func DeleteRecoveryCodes(tx *storage.Connection, user *User) error {
+	return deleteRecoveryCodesByUserID(tx, user.ID)
+}
+
+func deleteRecoveryCodesByUserID(tx *storage.Connection, userID uuid.UUID) error {
+	if err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: RecoveryCode{}}).TableName()+" WHERE user_id = ?", userID).Exec(); err != nil {
+		return errors.Wrap(err, "error deleting recovery codes")
+	}
+	return nil
+}
+
+// CountUnusedRecoveryCodes returns the number of recovery codes a user can
+// still redeem.
+func CountUnusedRecoveryCodes(tx *storage.Connection, user *User) (int, error) {
+	count, err := tx.Q().Where("user_id = ? and used_at is null", user.ID).Count(&RecoveryCode{})
+	if err != nil {
+		return 0, errors.Wrap(err, "error counting recovery codes")
+	}
+	return count, nil
+}
+
+// RedeemRecoveryCode marks the unused recovery code of a user as used.
+func RedeemRecoveryCode(tx *storage.Connection, user *User, code string) (*RecoveryCode, error) {
+	recoveryCode := &RecoveryCode{}
+	if err := tx.RawQuery("select * from "+recoveryCode.TableName()+" where user_id = ? and code_hash = ? and used_at is null limit 1 for update", user.ID, hashRecoveryCode(code)).First(recoveryCode); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, RecoveryCodeNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding recovery code")
+	}
+
+	now := time.Now()
+	recoveryCode.UsedAt = &now
+	if err := tx.UpdateOnly(recoveryCode, "used_at"); err != nil {
+		return nil, errors.Wrap(err, "error updating recovery code")
+	}
+
+	return recoveryCode, nil
+}
+
+// IsRecoveryCodesLocked returns whether the user cannot redeem recovery
+// codes after too many invalid codes.
+func (u *User) IsRecoveryCodesLocked(now time.Time) bool {
+	return u.RecoveryCodesLockedUntil != nil && now.Before(*u.RecoveryCodesLockedUntil)
+}
+
+// RecordFailedRecoveryCode counts an invalid recovery code entered by the
+// user. Like Factor.RecordFailedVerification, it locks recovery codes for
+// lockoutDuration when maxAttempts consecutive codes were invalid, and
+// returns whether it did.
+func (u *User) RecordFailedRecoveryCode(tx *storage.Connection, maxAttempts int, lockoutDuration time.Duration) (bool, error) {
+	// the attempts are counted by the database, as concurrent attempts
+	// would otherwise overwrite each other's count
+	if err := tx.RawQuery("UPDATE "+(&pop.Model{Value: User{}}).TableName()+" SET failed_recovery_code_attempts = failed_recovery_code_attempts + 1 WHERE id = ?", u.ID).Exec(); err != nil {
+		return false, errors.Wrap(err, "error counting failed recovery code attempts")
+	}
+
+	if err := tx.Reload(u); err != nil {
+		return false, errors.Wrap(err, "error reloading user")
+	}
+
+	if maxAttempts <= 0 || u.FailedRecoveryCodeAttempts < maxAttempts {
+		return false, nil
+	}
+
+	lockedUntil := time.Now().Add(lockoutDuration)
+	u.RecoveryCodesLockedUntil = &lockedUntil
+	u.FailedRecoveryCodeAttempts = 0
+	if err := tx.UpdateOnly(u, "failed_recovery_code_attempts", "recovery_codes_locked_until"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResetFailedRecoveryCodes clears the failed recovery code attempts after
+// the user redeemed a valid code.
+func (u *User) ResetFailedRecoveryCodes(tx *storage.Connection) error {
+	u.FailedRecoveryCodeAttempts = 0
+	u.RecoveryCodesLockedUntil = nil
+	return tx.UpdateOnly(u, "failed_recovery_code_attempts", "recovery_codes_locked_until")
+}
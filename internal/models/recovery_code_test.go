@@ -0,0 +1,16 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryCodeFormat(t *testing.T) {
+	code := formatRecoveryCode("BCDFGHJKLMNPQRST")
+	require.Equal(t, "BCDF-GHJK-LMNP-QRST", code)
+
+	require.Equal(t, "BCDFGHJKLMNPQRST", normalizeRecoveryCode(code))
+	require.Equal(t, "BCDFGHJKLMNPQRST", normalizeRecoveryCode(" bcdf ghjk-lmnp-qrst"))
+	require.Equal(t, hashRecoveryCode(code), hashRecoveryCode("bcdfghjklmnpqrst"))
+}
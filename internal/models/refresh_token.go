@@ -1,7 +1,10 @@
 package models
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"net/http"
 	"time"
 
@@ -10,13 +13,22 @@ import (
 	"github.com/pkg/errors"
 	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/storage"
+	"github.com/supabase/gotrue/internal/utilities"
 )
 
 // RefreshToken is the database model for refresh tokens.
 type RefreshToken struct {
 	ID int64 `db:"id"`
 
-	Token string `db:"token"`
+	// Token is the refresh token handed out to the client. It is only set
+	// when the token is issued, as only its hash is stored.
+	Token string `db:"-"`
+
+	// TokenHash is the keyed hash of the refresh token. Refresh tokens are
+	// stored in plaintext when no hash key is configured, and refresh
+	// tokens issued before a key was configured remain in plaintext until
+	// they are next used.
+	TokenHash string `db:"token"`
 
 	UserID uuid.UUID `db:"user_id"`
 
@@ -35,21 +47,67 @@ func (RefreshToken) TableName() string {
 	return tableName
 }
 
+// refreshTokenHashLength is the length of a hashed refresh token. Refresh
+// tokens in plaintext are always shorter.
+const refreshTokenHashLength = sha256.Size * 2
+
+// hashRefreshToken returns the value refresh tokens are stored and looked up
+// by: their hash keyed with hashKey, or the token itself when no hash key is
+// configured.
+func hashRefreshToken(hashKey, token string) string {
+	if hashKey == "" {
+		return token
+	}
+
+	mac := hmac.New(sha256.New, []byte(hashKey))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // GrantParams is used to pass session-specific parameters when issuing a new
 // refresh token to authenticated users.
 type GrantParams struct {
 	FactorID *uuid.UUID
 
+	// AAL is the assurance level of the session, if it is higher than
+	// aal1 from the start, e.g. when signing in with a passkey.
+	AAL string
+
 	SessionNotAfter *time.Time
+
+	// TrustedDeviceID is set when the user signs in on a trusted device,
+	// which skips the factor challenge.
+	TrustedDeviceID *uuid.UUID
+
+	// OAuthClientID and Scopes are set when the session is issued to a
+	// third-party OAuth client.
+	OAuthClientID *uuid.UUID
+	Scopes        string
+
+	// Nonce is the nonce of the client's authorization request, which is
+	// echoed in the ID token. It is not stored with the session.
+	Nonce string
+
+	// UserAgent and IP describe the client the session is issued to.
+	UserAgent string
+	IP        string
+}
+
+// FillGrantParams records the user agent and IP address of the request the
+// session is issued for.
+func (g *GrantParams) FillGrantParams(r *http.Request) {
+	g.UserAgent = r.Header.Get("User-Agent")
+	g.IP = sessionIPAddress(utilities.GetIPAddress(r))
 }
 
-// GrantAuthenticatedUser creates a refresh token for the provided user.
-func GrantAuthenticatedUser(tx *storage.Connection, user *User, params GrantParams) (*RefreshToken, error) {
-	return createRefreshToken(tx, user, nil, &params)
+// GrantAuthenticatedUser creates a refresh token for the provided user. The
+// token is stored hashed with hashKey, see hashRefreshToken.
+func GrantAuthenticatedUser(tx *storage.Connection, hashKey string, user *User, params GrantParams) (*RefreshToken, error) {
+	return createRefreshToken(tx, hashKey, user, nil, &params)
 }
 
 // GrantRefreshTokenSwap swaps a refresh token for a new one, revoking the provided token.
-func GrantRefreshTokenSwap(r *http.Request, tx *storage.Connection, user *User, token *RefreshToken) (*RefreshToken, error) {
+func GrantRefreshTokenSwap(r *http.Request, tx *storage.Connection, hashKey string, user *User, token *RefreshToken) (*RefreshToken, error) {
 	var newToken *RefreshToken
 	err := tx.Transaction(func(rtx *storage.Connection) error {
 		var terr error
@@ -62,7 +120,7 @@ func GrantRefreshTokenSwap(r *http.Request, tx *storage.Connection, user *User,
 			return terr
 		}
 
-		newToken, terr = createRefreshToken(rtx, user, token, &GrantParams{})
+		newToken, terr = createRefreshToken(rtx, hashKey, user, token, &GrantParams{})
 		return terr
 	})
 	return newToken, err
@@ -81,7 +139,7 @@ func RevokeTokenFamily(tx *storage.Connection, token *RefreshToken) error {
 			union
 			select r.id, r.user_id, r.token, r.revoked, r.parent from `+tablename+` r inner join token_family t on t.token = r.parent
 		)
-		update `+tablename+` r set revoked = true from token_family where token_family.id = r.id;`, token.Token).Exec()
+		update `+tablename+` r set revoked = true from token_family where token_family.id = r.id;`, token.TokenHash).Exec()
 	}
 	if err != nil {
 		return err
@@ -101,14 +159,16 @@ func FindTokenBySessionID(tx *storage.Connection, sessionId *uuid.UUID) (*Refres
 	return refreshToken, nil
 }
 
-func createRefreshToken(tx *storage.Connection, user *User, oldToken *RefreshToken, params *GrantParams) (*RefreshToken, error) {
+func createRefreshToken(tx *storage.Connection, hashKey string, user *User, oldToken *RefreshToken, params *GrantParams) (*RefreshToken, error) {
+	plaintext := crypto.SecureToken()
 	token := &RefreshToken{
-		UserID: user.ID,
-		Token:  crypto.SecureToken(),
-		Parent: "",
+		UserID:    user.ID,
+		Token:     plaintext,
+		TokenHash: hashRefreshToken(hashKey, plaintext),
+		Parent:    "",
 	}
 	if oldToken != nil {
-		token.Parent = storage.NullString(oldToken.Token)
+		token.Parent = storage.NullString(oldToken.TokenHash)
 		token.SessionId = oldToken.SessionId
 
 	}
@@ -121,14 +181,34 @@ func createRefreshToken(tx *storage.Connection, user *User, oldToken *RefreshTok
 
 		session.UserID = user.ID
 
+		authTime := time.Now()
+		session.AuthTime = &authTime
+
 		if params.FactorID != nil {
 			session.FactorID = params.FactorID
 		}
 
+		if params.AAL != "" {
+			session.AAL = &params.AAL
+		}
+
 		if params.SessionNotAfter != nil {
 			session.NotAfter = params.SessionNotAfter
 		}
 
+		if params.OAuthClientID != nil {
+			session.OAuthClientID = params.OAuthClientID
+			session.Scopes = storage.NullString(params.Scopes)
+		}
+
+		if params.UserAgent != "" {
+			session.UserAgent = &params.UserAgent
+		}
+
+		if params.IP != "" {
+			session.IP = &params.IP
+		}
+
 		if err := tx.Create(session); err != nil {
 			return nil, errors.Wrap(err, "error creating new session")
 		}
@@ -146,6 +226,46 @@ func createRefreshToken(tx *storage.Connection, user *User, oldToken *RefreshTok
 	return token, nil
 }
 
+// upgradeTokenHash replaces a refresh token stored in plaintext with its hash,
+// together with the references to it from its child tokens.
+func (r *RefreshToken) upgradeTokenHash(tx *storage.Connection, hashKey string) error {
+	plaintext := r.TokenHash
+	tokenHash := hashRefreshToken(hashKey, plaintext)
+	tablename := (&pop.Model{Value: RefreshToken{}}).TableName()
+
+	return tx.Transaction(func(tx *storage.Connection) error {
+		if err := tx.RawQuery(`update `+tablename+` set token = ? where id = ?;`, tokenHash, r.ID).Exec(); err != nil {
+			return err
+		}
+
+		if err := tx.RawQuery(`update `+tablename+` set parent = ? where parent = ?;`, tokenHash, plaintext).Exec(); err != nil {
+			return err
+		}
+
+		r.TokenHash = tokenHash
+		return nil
+	})
+}
+
+// HashPlaintextRefreshTokens hashes up to limit refresh tokens that are still
+// stored in plaintext by earlier versions, so that a leak of the database does
+// not expose them. It returns the number of hashed tokens, which is less than
+// limit once no plaintext tokens are left.
+func HashPlaintextRefreshTokens(tx *storage.Connection, hashKey string, limit int) (int, error) {
+	tokens := []*RefreshToken{}
+	if err := tx.Q().Where("length(token) <> ?", refreshTokenHashLength).Order("id asc").Limit(limit).All(&tokens); err != nil {
+		return 0, errors.Wrap(err, "error finding plaintext refresh tokens")
+	}
+
+	for _, token := range tokens {
+		if err := token.upgradeTokenHash(tx, hashKey); err != nil {
+			return 0, errors.Wrapf(err, "error hashing refresh token %d", token.ID)
+		}
+	}
+
+	return len(tokens), nil
+}
+
 // Deprecated. For backward compatibility, some access tokens may not have a sessionId. Use models.Logout instead.
 // LogoutAllRefreshTokens deletes all sessions for a user.
 func LogoutAllRefreshTokens(tx *storage.Connection, userId uuid.UUID) error {
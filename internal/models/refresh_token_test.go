@@ -7,13 +7,15 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/storage"
 	"github.com/supabase/gotrue/internal/storage/test"
 )
 
 type RefreshTokenTestSuite struct {
 	suite.Suite
-	db *storage.Connection
+	db      *storage.Connection
+	hashKey string
 }
 
 func (ts *RefreshTokenTestSuite) SetupTest() {
@@ -28,7 +30,8 @@ func TestRefreshToken(t *testing.T) {
 	require.NoError(t, err)
 
 	ts := &RefreshTokenTestSuite{
-		db: conn,
+		db:      conn,
+		hashKey: globalConfig.Security.RefreshTokenHashKey,
 	}
 	defer ts.db.Close()
 
@@ -37,7 +40,7 @@ func TestRefreshToken(t *testing.T) {
 
 func (ts *RefreshTokenTestSuite) TestGrantAuthenticatedUser() {
 	u := ts.createUser()
-	r, err := GrantAuthenticatedUser(ts.db, u, GrantParams{})
+	r, err := GrantAuthenticatedUser(ts.db, ts.hashKey, u, GrantParams{})
 	require.NoError(ts.T(), err)
 
 	require.NotEmpty(ts.T(), r.Token)
@@ -46,13 +49,13 @@ func (ts *RefreshTokenTestSuite) TestGrantAuthenticatedUser() {
 
 func (ts *RefreshTokenTestSuite) TestGrantRefreshTokenSwap() {
 	u := ts.createUser()
-	r, err := GrantAuthenticatedUser(ts.db, u, GrantParams{})
+	r, err := GrantAuthenticatedUser(ts.db, ts.hashKey, u, GrantParams{})
 	require.NoError(ts.T(), err)
 
-	s, err := GrantRefreshTokenSwap(&http.Request{}, ts.db, u, r)
+	s, err := GrantRefreshTokenSwap(&http.Request{}, ts.db, ts.hashKey, u, r)
 	require.NoError(ts.T(), err)
 
-	_, nr, _, err := FindUserWithRefreshToken(ts.db, r.Token, false)
+	_, nr, _, err := FindUserWithRefreshToken(ts.db, ts.hashKey, r.Token, false)
 	require.NoError(ts.T(), err)
 
 	require.Equal(ts.T(), r.ID, nr.ID)
@@ -62,13 +65,100 @@ func (ts *RefreshTokenTestSuite) TestGrantRefreshTokenSwap() {
 	require.Equal(ts.T(), u.ID, s.UserID)
 }
 
+func (ts *RefreshTokenTestSuite) TestRefreshTokenIsStoredHashed() {
+	u := ts.createUser()
+	r, err := GrantAuthenticatedUser(ts.db, ts.hashKey, u, GrantParams{})
+	require.NoError(ts.T(), err)
+
+	require.Equal(ts.T(), hashRefreshToken(ts.hashKey, r.Token), r.TokenHash)
+	require.NotEqual(ts.T(), r.Token, r.TokenHash)
+
+	// the stored hash cannot be used as the refresh token
+	_, _, _, err = FindUserWithRefreshToken(ts.db, ts.hashKey, r.TokenHash, false)
+	require.True(ts.T(), IsNotFoundError(err), "expected NotFoundError")
+}
+
+func (ts *RefreshTokenTestSuite) TestRefreshTokenWithoutHashKey() {
+	u := ts.createUser()
+	r, err := GrantAuthenticatedUser(ts.db, "", u, GrantParams{})
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), r.Token, r.TokenHash)
+
+	_, nr, _, err := FindUserWithRefreshToken(ts.db, "", r.Token, false)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), r.ID, nr.ID)
+
+	// once a key is configured, the token is hashed when it is next used
+	_, nr, _, err = FindUserWithRefreshToken(ts.db, ts.hashKey, r.Token, false)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), r.ID, nr.ID)
+	require.Equal(ts.T(), hashRefreshToken(ts.hashKey, r.Token), nr.TokenHash)
+}
+
+func (ts *RefreshTokenTestSuite) TestFindUserWithPlaintextRefreshToken() {
+	u := ts.createUser()
+	r, err := GrantAuthenticatedUser(ts.db, ts.hashKey, u, GrantParams{})
+	require.NoError(ts.T(), err)
+
+	s, err := GrantRefreshTokenSwap(&http.Request{}, ts.db, ts.hashKey, u, r)
+	require.NoError(ts.T(), err)
+
+	// store the tokens in plaintext, as they were before tokens were hashed
+	require.NoError(ts.T(), ts.db.RawQuery("update "+r.TableName()+" set token = ? where id = ?", r.Token, r.ID).Exec())
+	require.NoError(ts.T(), ts.db.RawQuery("update "+r.TableName()+" set parent = ? where id = ?", r.Token, s.ID).Exec())
+
+	_, nr, _, err := FindUserWithRefreshToken(ts.db, ts.hashKey, r.Token, true)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), r.ID, nr.ID)
+	require.Equal(ts.T(), r.TokenHash, nr.TokenHash)
+
+	ns := &RefreshToken{}
+	require.NoError(ts.T(), ts.db.Find(ns, s.ID))
+	require.Equal(ts.T(), r.TokenHash, ns.Parent.String())
+
+	// the token keeps working once it is stored hashed
+	_, nr, _, err = FindUserWithRefreshToken(ts.db, ts.hashKey, r.Token, false)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), r.ID, nr.ID)
+}
+
+func (ts *RefreshTokenTestSuite) TestHashPlaintextRefreshTokens() {
+	u := ts.createUser()
+	r, err := GrantAuthenticatedUser(ts.db, ts.hashKey, u, GrantParams{})
+	require.NoError(ts.T(), err)
+
+	s, err := GrantRefreshTokenSwap(&http.Request{}, ts.db, ts.hashKey, u, r)
+	require.NoError(ts.T(), err)
+
+	// store the tokens in plaintext, as they were before tokens were hashed
+	require.NoError(ts.T(), ts.db.RawQuery("update "+r.TableName()+" set token = ? where id = ?", r.Token, r.ID).Exec())
+	require.NoError(ts.T(), ts.db.RawQuery("update "+r.TableName()+" set parent = ? where id = ?", r.Token, s.ID).Exec())
+
+	count, err := HashPlaintextRefreshTokens(ts.db, ts.hashKey, 10)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 1, count)
+
+	nr := &RefreshToken{}
+	require.NoError(ts.T(), ts.db.Find(nr, r.ID))
+	require.Equal(ts.T(), r.TokenHash, nr.TokenHash)
+
+	ns := &RefreshToken{}
+	require.NoError(ts.T(), ts.db.Find(ns, s.ID))
+	require.Equal(ts.T(), r.TokenHash, ns.Parent.String())
+
+	// tokens that are already hashed are left alone
+	count, err = HashPlaintextRefreshTokens(ts.db, ts.hashKey, 10)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), 0, count)
+}
+
 func (ts *RefreshTokenTestSuite) TestLogout() {
 	u := ts.createUser()
-	r, err := GrantAuthenticatedUser(ts.db, u, GrantParams{})
+	r, err := GrantAuthenticatedUser(ts.db, ts.hashKey, u, GrantParams{})
 	require.NoError(ts.T(), err)
 
 	require.NoError(ts.T(), Logout(ts.db, u.ID))
-	u, r, _, err = FindUserWithRefreshToken(ts.db, r.Token, false)
+	u, r, _, err = FindUserWithRefreshToken(ts.db, ts.hashKey, r.Token, false)
 	require.Errorf(ts.T(), err, "expected error when there are no refresh tokens to authenticate. user: %v token: %v", u, r)
 
 	require.True(ts.T(), IsNotFoundError(err), "expected NotFoundError")
@@ -79,7 +169,7 @@ func (ts *RefreshTokenTestSuite) createUser() *User {
 }
 
 func (ts *RefreshTokenTestSuite) createUserWithEmail(email string) *User {
-	user, err := NewUser("", email, "secret", "test", nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "", email, "secret", "test", nil)
 	require.NoError(ts.T(), err)
 
 	err = ts.db.Create(user)
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// ServiceAccount is a non-human identity, such as a backend worker, that
+// obtains short-lived access tokens with the client_credentials grant.
+type ServiceAccount struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	Name             string     `json:"name" db:"name"`
+	ClientSecretHash string     `json:"-" db:"client_secret_hash"`
+	Role             string     `json:"role" db:"role"`
+	Scopes           StringList `json:"scopes" db:"scopes"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+func (ServiceAccount) TableName() string {
+	tableName := "service_accounts"
+	return tableName
+}
+
+// NewServiceAccount creates a new service account and its secret, which is
+// returned only once and stored hashed.
+func NewServiceAccount(name, role string, scopes []string, expiresAt *time.Time) (*ServiceAccount, string) {
+	account := &ServiceAccount{
+		ID:        uuid.Must(uuid.NewV4()),
+		Name:      name,
+		Role:      role,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+
+	secret := account.GenerateSecret()
+
+	return account, secret
+}
+
+// GenerateSecret sets a new random secret on the service account and returns
+// it.
+func (s *ServiceAccount) GenerateSecret() string {
+	secret := crypto.SecureToken(32)
+	s.ClientSecretHash = hashClientSecret(secret)
+	return secret
+}
+
+// VerifySecret checks the provided secret against the stored hash.
+func (s *ServiceAccount) VerifySecret(secret string) bool {
+	if secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(s.ClientSecretHash), []byte(hashClientSecret(secret))) == 1
+}
+
+// IsExpired returns true if the service account can no longer obtain tokens.
+func (s *ServiceAccount) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// AllowsScopes returns true if all of the provided scopes are allowed for the
+// service account.
+func (s *ServiceAccount) AllowsScopes(scopes []string) bool {
+	return s.Scopes.ContainsAll(scopes)
+}
+
+// FindServiceAccountByID finds a service account by its ID, which is also
+// its client_id.
+func FindServiceAccountByID(tx *storage.Connection, id uuid.UUID) (*ServiceAccount, error) {
+	account := &ServiceAccount{}
+	if err := tx.Q().Where("id = ?", id).First(account); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, ServiceAccountNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding service account")
+	}
+
+	return account, nil
+}
+
+// FindAllServiceAccounts returns all service accounts.
+func FindAllServiceAccounts(tx *storage.Connection) ([]ServiceAccount, error) {
+	var accounts []ServiceAccount
+
+	if err := tx.Q().Order("created_at asc").All(&accounts); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "error finding service accounts")
+	}
+
+	return accounts, nil
+}
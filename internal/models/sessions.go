@@ -3,8 +3,8 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"net"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/gobuffalo/pop/v6"
@@ -41,22 +41,6 @@ type AMREntry struct {
 	Provider  string `json:"provider,omitempty"`
 }
 
-type sortAMREntries struct {
-	Array []AMREntry
-}
-
-func (s sortAMREntries) Len() int {
-	return len(s.Array)
-}
-
-func (s sortAMREntries) Less(i, j int) bool {
-	return s.Array[i].Timestamp < s.Array[j].Timestamp
-}
-
-func (s sortAMREntries) Swap(i, j int) {
-	s.Array[j], s.Array[i] = s.Array[i], s.Array[j]
-}
-
 type Session struct {
 	ID        uuid.UUID  `json:"-" db:"id"`
 	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
@@ -66,6 +50,21 @@ type Session struct {
 	FactorID  *uuid.UUID `json:"factor_id" db:"factor_id"`
 	AMRClaims []AMRClaim `json:"amr,omitempty" has_many:"amr_claims"`
 	AAL       *string    `json:"aal" db:"aal"`
+
+	OAuthClientID *uuid.UUID         `json:"oauth_client_id,omitempty" db:"oauth_client_id"`
+	Scopes        storage.NullString `json:"scopes,omitempty" db:"scopes"`
+
+	UserAgent   *string    `json:"user_agent,omitempty" db:"user_agent"`
+	IP          *string    `json:"ip,omitempty" db:"ip"`
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty" db:"refreshed_at"`
+
+	// AuthTime is the time the user last actively authenticated in the
+	// session, by signing in or by reauthenticating.
+	AuthTime *time.Time `json:"auth_time,omitempty" db:"auth_time"`
+
+	// FailedReauthenticationAttempts counts the consecutive wrong
+	// passwords entered to reauthenticate in the session.
+	FailedReauthenticationAttempts int `json:"-" db:"failed_reauthentication_attempts"`
 }
 
 func (Session) TableName() string {
@@ -129,6 +128,16 @@ func FindSessionByUserID(tx *storage.Connection, userId uuid.UUID) (*Session, er
 	return session, nil
 }
 
+// FindAllSessionsByUserID returns all sessions of a user, most recently
+// created first.
+func FindAllSessionsByUserID(tx *storage.Connection, userID uuid.UUID) ([]*Session, error) {
+	sessions := []*Session{}
+	if err := tx.Q().Where("user_id = ?", userID).Order("created_at desc").All(&sessions); err != nil {
+		return nil, errors.Wrap(err, "error finding sessions")
+	}
+	return sessions, nil
+}
+
 func FindSessionsByFactorID(tx *storage.Connection, factorID uuid.UUID) ([]*Session, error) {
 	sessions := []*Session{}
 	if err := tx.Q().Where("factor_id = ?", factorID).All(&sessions); err != nil {
@@ -160,6 +169,79 @@ func LogoutAllExceptMe(tx *storage.Connection, sessionId uuid.UUID, userID uuid.
 	return tx.RawQuery("DELETE FROM "+(&pop.Model{Value: Session{}}).TableName()+" WHERE id != ? AND user_id = ?", sessionId, userID).Exec()
 }
 
+// UpdateOnRefresh records the time and the client of the latest refresh of
+// the session.
+func (s *Session) UpdateOnRefresh(tx *storage.Connection, userAgent, ip string) error {
+	now := time.Now()
+	s.RefreshedAt = &now
+	if userAgent != "" {
+		s.UserAgent = &userAgent
+	}
+	if ip := sessionIPAddress(ip); ip != "" {
+		s.IP = &ip
+	}
+	return tx.UpdateOnly(s, "refreshed_at", "user_agent", "ip", "updated_at")
+}
+
+// sessionIPAddress returns the IP address in the form stored in the inet
+// column of sessions, or an empty string when it isn't a valid IP address,
+// e.g. when it comes from a forged X-Forwarded-For header.
+func sessionIPAddress(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	return parsed.String()
+}
+
+// UpdateAuthTime records that the user actively authenticated in the
+// session.
+func (s *Session) UpdateAuthTime(tx *storage.Connection) error {
+	now := time.Now()
+	s.AuthTime = &now
+	return tx.UpdateOnly(s, "auth_time", "updated_at")
+}
+
+// RecordFailedReauthentication counts a wrong password entered to
+// reauthenticate in the session and returns the number of consecutive
+// failed attempts.
+func (s *Session) RecordFailedReauthentication(tx *storage.Connection) (int, error) {
+	// the attempts are counted by the database, as concurrent attempts
+	// would otherwise overwrite each other's count
+	if err := tx.RawQuery("UPDATE "+(&pop.Model{Value: Session{}}).TableName()+" SET failed_reauthentication_attempts = failed_reauthentication_attempts + 1, updated_at = ? WHERE id = ?", time.Now(), s.ID).Exec(); err != nil {
+		return 0, errors.Wrap(err, "error counting failed reauthentication attempts")
+	}
+
+	if err := tx.Reload(s); err != nil {
+		return 0, errors.Wrap(err, "error reloading session")
+	}
+
+	return s.FailedReauthenticationAttempts, nil
+}
+
+// ResetFailedReauthentications clears the failed reauthentication attempts
+// after the user reauthenticated.
+func (s *Session) ResetFailedReauthentications(tx *storage.Connection) error {
+	s.FailedReauthenticationAttempts = 0
+	return tx.UpdateOnly(s, "failed_reauthentication_attempts", "updated_at")
+}
+
+// GetAuthTime returns the time the user last actively authenticated in the
+// session. Sessions created before the auth time was tracked fall back to
+// their creation time.
+func (s *Session) GetAuthTime() time.Time {
+	if s.AuthTime == nil {
+		return s.CreatedAt
+	}
+	return *s.AuthTime
+}
+
+// IsRecentlyAuthenticated returns whether the user actively authenticated
+// in the session within maxAge of now.
+func (s *Session) IsRecentlyAuthenticated(now time.Time, maxAge time.Duration) bool {
+	return now.Before(s.GetAuthTime().Add(maxAge))
+}
+
 func (s *Session) UpdateAssociatedFactor(tx *storage.Connection, factorID *uuid.UUID) error {
 	s.FactorID = factorID
 	return tx.Update(s)
@@ -172,46 +254,35 @@ func (s *Session) UpdateAssociatedAAL(tx *storage.Connection, aal string) error
 
 func (s *Session) CalculateAALAndAMR(tx *storage.Connection) (aal string, amr []AMREntry, err error) {
 	amr, aal = []AMREntry{}, AAL1.String()
-	for _, claim := range s.AMRClaims {
-		if *claim.AuthenticationMethod == TOTPSignIn.String() {
-			aal = AAL2.String()
-		}
-		amr = append(amr, AMREntry{Method: claim.GetAuthenticationMethod(), Timestamp: claim.UpdatedAt.Unix()})
-	}
 
 	// makes sure that the AMR claims are always ordered most-recent first
-
-	// sort in ascending order
-	sort.Sort(sortAMREntries{
-		Array: amr,
+	claims := make([]AMRClaim, len(s.AMRClaims))
+	copy(claims, s.AMRClaims)
+	sort.SliceStable(claims, func(i, j int) bool {
+		return claims[i].UpdatedAt.After(claims[j].UpdatedAt)
 	})
 
-	// now reverse for descending order
-	_ = sort.Reverse(sortAMREntries{
-		Array: amr,
-	})
+	for _, claim := range claims {
+		switch *claim.AuthenticationMethod {
+		case TOTPSignIn.String(), WebAuthnSignIn.String(), PhoneSignIn.String(), RecoveryCodeSignIn.String(), TrustedDeviceSignIn.String():
+			aal = AAL2.String()
+		}
+		amr = append(amr, AMREntry{Method: claim.GetAuthenticationMethod(), Timestamp: claim.UpdatedAt.Unix()})
+	}
 
-	lastIndex := len(amr) - 1
+	for i := range amr {
+		if amr[i].Method != SSOSAML.String() {
+			continue
+		}
 
-	if lastIndex > -1 && amr[lastIndex].Method == SSOSAML.String() {
-		// initial AMR claim is from sso/saml, we need to add information
-		// about the provider that was used for the authentication
-		identities, err := FindIdentitiesByUserID(tx, s.UserID)
+		// the sso/saml claim needs information about the provider that
+		// was used for the authentication, also when a factor was
+		// verified after it
+		providerID, err := FindSSOProviderIDForUser(tx, s.UserID)
 		if err != nil {
 			return aal, amr, err
 		}
-
-		if len(identities) == 1 {
-			identity := identities[0]
-
-			if strings.HasPrefix(identity.Provider, "sso:") {
-				amr[lastIndex].Provider = strings.TrimPrefix(identity.Provider, "sso:")
-			}
-		}
-
-		// otherwise we can't identify that this user account has only
-		// one SSO identity, so we are not encoding the provider at
-		// this time
+		amr[i].Provider = providerID
 	}
 
 	return aal, amr, nil
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
 	"github.com/supabase/gotrue/internal/storage"
 	"github.com/supabase/gotrue/internal/storage/test"
 )
@@ -20,7 +21,7 @@ type SessionsTestSuite struct {
 func (ts *SessionsTestSuite) SetupTest() {
 	TruncateAll(ts.db)
 	email := "test@example.com"
-	user, err := NewUser("", email, "secret", ts.Config.JWT.Aud, nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "", email, "secret", ts.Config.JWT.Aud, nil)
 	require.NoError(ts.T(), err)
 
 	err = ts.db.Create(user)
@@ -97,3 +98,73 @@ func (ts *SessionsTestSuite) TestCalculateAALAndAMR() {
 	}
 	require.True(ts.T(), found)
 }
+
+func (ts *SessionsTestSuite) TestCalculateAALAndAMRWithSSO() {
+	u, err := FindUserByEmailAndAudience(ts.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+
+	identity, err := NewIdentity(u, "sso:00000000-0000-0000-0000-000000000001", map[string]interface{}{
+		"sub": "sso-subject",
+	})
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), ts.db.Create(identity))
+
+	session, err := NewSession()
+	require.NoError(ts.T(), err)
+	session.UserID = u.ID
+	require.NoError(ts.T(), ts.db.Create(session))
+
+	require.NoError(ts.T(), AddClaimToSession(ts.db, session.ID, SSOSAML))
+	require.NoError(ts.T(), AddClaimToSession(ts.db, session.ID, TOTPSignIn))
+
+	session, err = FindSessionByID(ts.db, session.ID, false)
+	require.NoError(ts.T(), err)
+
+	aal, amr, err := session.CalculateAALAndAMR(ts.db)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), AAL2.String(), aal)
+
+	// the most recent claim comes first, and the sso/saml claim keeps
+	// its provider after a factor was verified
+	require.Len(ts.T(), amr, 2)
+	require.Equal(ts.T(), TOTPSignIn.String(), amr[0].Method)
+	require.Equal(ts.T(), SSOSAML.String(), amr[1].Method)
+	require.Equal(ts.T(), "00000000-0000-0000-0000-000000000001", amr[1].Provider)
+}
+
+func (ts *SessionsTestSuite) TestUpdateAuthTime() {
+	u, err := FindUserByEmailAndAudience(ts.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+	session, err := NewSession()
+	require.NoError(ts.T(), err)
+	session.UserID = u.ID
+	require.NoError(ts.T(), ts.db.Create(session))
+
+	// sessions without an auth time fall back to their creation time
+	require.Equal(ts.T(), session.CreatedAt, session.GetAuthTime())
+	require.False(ts.T(), session.IsRecentlyAuthenticated(session.CreatedAt.Add(2*time.Hour), time.Hour))
+
+	require.NoError(ts.T(), session.UpdateAuthTime(ts.db))
+
+	found, err := FindSessionByID(ts.db, session.ID, false)
+	require.NoError(ts.T(), err)
+	require.NotNil(ts.T(), found.AuthTime)
+	require.True(ts.T(), found.IsRecentlyAuthenticated(time.Now(), time.Hour))
+}
+
+func (ts *SessionsTestSuite) TestUpdateOnRefreshIgnoresInvalidIP() {
+	u, err := FindUserByEmailAndAudience(ts.db, "test@example.com", ts.Config.JWT.Aud)
+	require.NoError(ts.T(), err)
+	session, err := NewSession()
+	require.NoError(ts.T(), err)
+	session.UserID = u.ID
+	require.NoError(ts.T(), ts.db.Create(session))
+
+	require.NoError(ts.T(), session.UpdateOnRefresh(ts.db, "test", "foo"))
+	require.Nil(ts.T(), session.IP)
+
+	require.NoError(ts.T(), session.UpdateOnRefresh(ts.db, "test", "127.0.0.1"))
+	found, err := FindSessionByID(ts.db, session.ID, false)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), "127.0.0.1", *found.IP)
+}
@@ -20,6 +20,10 @@ type SSOProvider struct {
 	SAMLProvider SAMLProvider `has_one:"saml_providers" fk_id:"sso_provider_id" json:"saml,omitempty"`
 	SSODomains   []SSODomain  `has_many:"sso_domains" fk_id:"sso_provider_id" json:"domains"`
 
+	// MFARequired requires the users of the provider to use MFA, for
+	// identity providers that don't enforce it themselves.
+	MFARequired bool `db:"mfa_required" json:"mfa_required"`
+
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -196,6 +200,22 @@ func FindSSOProviderByID(tx *storage.Connection, id uuid.UUID) (*SSOProvider, er
 	return &ssoProvider, nil
 }
 
+// FindSSOProviderIDForUser returns the ID of the SSO provider of a user
+// whose only identity is an SSO identity. If the user has other identities
+// the provider can't be identified and it returns an empty string.
+func FindSSOProviderIDForUser(tx *storage.Connection, userID uuid.UUID) (string, error) {
+	identities, err := FindIdentitiesByUserID(tx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(identities) != 1 || !strings.HasPrefix(identities[0].Provider, "sso:") {
+		return "", nil
+	}
+
+	return strings.TrimPrefix(identities[0].Provider, "sso:"), nil
+}
+
 func FindSSOProviderForEmailAddress(tx *storage.Connection, emailAddress string) (*SSOProvider, error) {
 	parts := strings.Split(emailAddress, "@")
 	emailDomain := strings.ToLower(parts[1])
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+)
+
+// TrustedDevice is a device a user trusted after verifying a factor on it.
+// Signing in with the device token of a trusted device skips the factor
+// challenge. Only the hash of the token is stored.
+type TrustedDevice struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"-" db:"user_id"`
+	FactorID   uuid.UUID  `json:"factor_id" db:"factor_id"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	UserAgent  *string    `json:"user_agent,omitempty" db:"user_agent"`
+	IP         *string    `json:"ip,omitempty" db:"ip"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+func (TrustedDevice) TableName() string {
+	tableName := "mfa_trusted_devices"
+	return tableName
+}
+
+// NewTrustedDevice trusts the device the factor was verified on until
+// expiresAt. It returns the device and its token, which is only returned
+// once.
+func NewTrustedDevice(user *User, factor *Factor, expiresAt time.Time, userAgent, ip string) (*TrustedDevice, string) {
+	token := crypto.SecureToken(32)
+
+	device := &TrustedDevice{
+		ID:        uuid.Must(uuid.NewV4()),
+		UserID:    user.ID,
+		FactorID:  factor.ID,
+		TokenHash: hashClientSecret(token),
+		ExpiresAt: expiresAt,
+	}
+	if userAgent != "" {
+		device.UserAgent = &userAgent
+	}
+	if ip != "" {
+		device.IP = &ip
+	}
+
+	return device, token
+}
+
+// IsExpired returns whether the device is no longer trusted.
+func (d *TrustedDevice) IsExpired(now time.Time) bool {
+	return !now.Before(d.ExpiresAt)
+}
+
+// UpdateLastUsedAt records that the device was used to sign in.
+func (d *TrustedDevice) UpdateLastUsedAt(tx *storage.Connection) error {
+	now := time.Now()
+	d.LastUsedAt = &now
+	return tx.UpdateOnly(d, "last_used_at")
+}
+
+// FindTrustedDeviceByToken finds the unexpired trusted device of a user by
+// its device token.
+func FindTrustedDeviceByToken(tx *storage.Connection, user *User, token string) (*TrustedDevice, error) {
+	device := &TrustedDevice{}
+	if err := tx.Q().Where("user_id = ? and token_hash = ? and expires_at > ?", user.ID, hashClientSecret(token), time.Now()).First(device); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, TrustedDeviceNotFoundError{}
+		}
+		return nil, errors.Wrap(err, "error finding trusted device")
+	}
+	return device, nil
+}
+
+// FindTrustedDevicesByUser returns the unexpired trusted devices of a user,
+// most recently trusted first.
+func FindTrustedDevicesByUser(tx *storage.Connection, user *User) ([]*TrustedDevice, error) {
+	devices := []*TrustedDevice{}
+	if err := tx.Q().Where("user_id = ? and expires_at > ?", user.ID, time.Now()).Order("created_at desc").All(&devices); err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return devices, nil
+		}
+		return nil, errors.Wrap(err, "error finding trusted devices")
+	}
+	return devices, nil
+}
+
+// DeleteTrustedDevice revokes a trusted device of a user.
+func DeleteTrustedDevice(tx *storage.Connection, user *User, id uuid.UUID) error {
+	count, err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: TrustedDevice{}}).TableName()+" WHERE user_id = ? AND id = ?", user.ID, id).ExecWithCount()
+	if err != nil {
+		return errors.Wrap(err, "error deleting trusted device")
+	}
+	if count == 0 {
+		return TrustedDeviceNotFoundError{}
+	}
+	return nil
+}
+
+// DeleteTrustedDevices revokes all trusted devices of a user.
+func DeleteTrustedDevices(tx *storage.Connection, user *User) error {
+	if err := tx.RawQuery("DELETE FROM "+(&pop.Model{Value: TrustedDevice{}}).TableName()+" WHERE user_id = ?", user.ID).Exec(); err != nil {
+		return errors.Wrap(err, "error deleting trusted devices")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/supabase/gotrue/internal/conf"
+	"github.com/supabase/gotrue/internal/crypto"
+	"github.com/supabase/gotrue/internal/storage"
+	"github.com/supabase/gotrue/internal/storage/test"
+)
+
+type TrustedDeviceTestSuite struct {
+	suite.Suite
+	db *storage.Connection
+}
+
+func TestTrustedDevice(t *testing.T) {
+	globalConfig, err := conf.LoadGlobal(modelsTestConfig)
+	require.NoError(t, err)
+	conn, err := test.SetupDBConnection(globalConfig)
+	require.NoError(t, err)
+	ts := &TrustedDeviceTestSuite{
+		db: conn,
+	}
+	defer ts.db.Close()
+	suite.Run(t, ts)
+}
+
+func (ts *TrustedDeviceTestSuite) SetupTest() {
+	TruncateAll(ts.db)
+}
+
+func (ts *TrustedDeviceTestSuite) TestFindTrustedDeviceByToken() {
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "secret", "test", nil)
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), ts.db.Create(user))
+
+	factor, err := NewFactor(nil, user, "", TOTP, FactorStateVerified, "secret")
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), ts.db.Create(factor))
+
+	device, token := NewTrustedDevice(user, factor, time.Now().Add(time.Hour), "Mozilla/5.0", "127.0.0.1")
+	require.NoError(ts.T(), ts.db.Create(device))
+	require.NotEqual(ts.T(), token, device.TokenHash)
+
+	found, err := FindTrustedDeviceByToken(ts.db, user, token)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), device.ID, found.ID)
+
+	_, err = FindTrustedDeviceByToken(ts.db, user, "other")
+	require.EqualError(ts.T(), err, TrustedDeviceNotFoundError{}.Error())
+
+	expired, expiredToken := NewTrustedDevice(user, factor, time.Now().Add(-time.Minute), "", "")
+	require.NoError(ts.T(), ts.db.Create(expired))
+
+	_, err = FindTrustedDeviceByToken(ts.db, user, expiredToken)
+	require.EqualError(ts.T(), err, TrustedDeviceNotFoundError{}.Error())
+
+	devices, err := FindTrustedDevicesByUser(ts.db, user)
+	require.NoError(ts.T(), err)
+	require.Len(ts.T(), devices, 1)
+
+	// revoking the factor revokes the devices trusted with it
+	require.NoError(ts.T(), ts.db.Destroy(factor))
+	_, err = FindTrustedDeviceByToken(ts.db, user, token)
+	require.EqualError(ts.T(), err, TrustedDeviceNotFoundError{}.Error())
+}
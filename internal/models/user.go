@@ -56,6 +56,15 @@ type User struct {
 
 	LastSignInAt *time.Time `json:"last_sign_in_at,omitempty" db:"last_sign_in_at"`
 
+	MFARequired      bool       `json:"mfa_required" db:"mfa_required"`
+	MFARequiredSince *time.Time `json:"-" db:"mfa_required_since"`
+
+	// FailedRecoveryCodeAttempts counts the consecutive invalid recovery
+	// codes the user entered. Recovery codes are locked until
+	// RecoveryCodesLockedUntil when there were too many.
+	FailedRecoveryCodeAttempts int        `json:"-" db:"failed_recovery_code_attempts"`
+	RecoveryCodesLockedUntil   *time.Time `json:"-" db:"recovery_codes_locked_until"`
+
 	AppMetaData  JSONMap `json:"app_metadata" db:"raw_app_meta_data"`
 	UserMetaData JSONMap `json:"user_metadata" db:"raw_user_meta_data"`
 
@@ -71,12 +80,27 @@ type User struct {
 }
 
 // NewUser initializes a new user from an email, password and user data.
-func NewUser(phone, email, password, aud string, userData map[string]interface{}) (*User, error) {
-	id := uuid.Must(uuid.NewV4())
-	pw, err := crypto.GenerateFromPassword(context.Background(), password)
+// The password is hashed with hashParams.
+func NewUser(hashParams crypto.PasswordHashParams, phone, email, password, aud string, userData map[string]interface{}) (*User, error) {
+	pw, err := crypto.GenerateFromPassword(context.Background(), hashParams, password)
 	if err != nil {
 		return nil, err
 	}
+	return newUser(phone, email, pw, aud, userData), nil
+}
+
+// NewUserWithPasswordHash initializes a new user from an email, a password
+// hash generated elsewhere and user data. The hash is replaced with a native
+// one the first time the user signs in with their password.
+func NewUserWithPasswordHash(phone, email, passwordHash, aud string, userData map[string]interface{}) (*User, error) {
+	if err := crypto.ValidatePasswordHash(passwordHash); err != nil {
+		return nil, err
+	}
+	return newUser(phone, email, passwordHash, aud, userData), nil
+}
+
+func newUser(phone, email, encryptedPassword, aud string, userData map[string]interface{}) *User {
+	id := uuid.Must(uuid.NewV4())
 	if userData == nil {
 		userData = make(map[string]interface{})
 	}
@@ -86,9 +110,9 @@ func NewUser(phone, email, password, aud string, userData map[string]interface{}
 		Email:             storage.NullString(strings.ToLower(email)),
 		Phone:             storage.NullString(phone),
 		UserMetaData:      userData,
-		EncryptedPassword: pw,
+		EncryptedPassword: encryptedPassword,
 	}
-	return user, nil
+	return user
 }
 
 // TableName overrides the table name used by pop
@@ -227,13 +251,26 @@ func (u *User) SetPhone(tx *storage.Connection, phone string) error {
 	return tx.UpdateOnly(u, "phone")
 }
 
-// UpdatePassword updates the user's password
-func (u *User) UpdatePassword(tx *storage.Connection, password string, sessionID *uuid.UUID) error {
-	pw, err := crypto.GenerateFromPassword(context.Background(), password)
+// UpdatePassword updates the user's password, hashed with hashParams
+func (u *User) UpdatePassword(tx *storage.Connection, hashParams crypto.PasswordHashParams, password string, sessionID *uuid.UUID) error {
+	pw, err := crypto.GenerateFromPassword(context.Background(), hashParams, password)
 	if err != nil {
 		return err
 	}
-	u.EncryptedPassword = pw
+	return u.updateEncryptedPassword(tx, pw, sessionID)
+}
+
+// UpdatePasswordHash replaces the user's password with a password hash
+// generated elsewhere, which is validated first.
+func (u *User) UpdatePasswordHash(tx *storage.Connection, passwordHash string, sessionID *uuid.UUID) error {
+	if err := crypto.ValidatePasswordHash(passwordHash); err != nil {
+		return err
+	}
+	return u.updateEncryptedPassword(tx, passwordHash, sessionID)
+}
+
+func (u *User) updateEncryptedPassword(tx *storage.Connection, encryptedPassword string, sessionID *uuid.UUID) error {
+	u.EncryptedPassword = encryptedPassword
 	if err := tx.UpdateOnly(u, "encrypted_password"); err != nil {
 		return err
 	}
@@ -247,6 +284,22 @@ func (u *User) UpdatePassword(tx *storage.Connection, password string, sessionID
 	}
 }
 
+// RehashPassword hashes the password again if the stored hash was generated
+// with another algorithm or other parameters than hashParams. The password
+// must have been authenticated already. Sessions are not affected.
+func (u *User) RehashPassword(tx *storage.Connection, hashParams crypto.PasswordHashParams, password string) error {
+	if !crypto.NeedsRehash(hashParams, u.EncryptedPassword) {
+		return nil
+	}
+
+	pw, err := crypto.GenerateFromPassword(context.Background(), hashParams, password)
+	if err != nil {
+		return err
+	}
+	u.EncryptedPassword = pw
+	return tx.UpdateOnly(u, "encrypted_password")
+}
+
 // UpdatePhone updates the user's phone
 func (u *User) UpdatePhone(tx *storage.Connection, phone string) error {
 	u.Phone = storage.NullString(phone)
@@ -445,15 +498,27 @@ func FindUserByEmailChangeToken(tx *storage.Connection, token string) (*User, er
 // the form SELECT ... FOR UPDATE SKIP LOCKED. This means that a FOR UPDATE
 // lock will only be acquired if there's no other lock. In case there is a
 // lock, a IsNotFound(err) error will be returned.
-func FindUserWithRefreshToken(tx *storage.Connection, token string, forUpdate bool) (*User, *RefreshToken, *Session, error) {
+//
+// Refresh tokens are looked up by their hash keyed with hashKey. Refresh
+// tokens issued before the key was configured are found in plaintext and
+// replaced with their hash.
+func FindUserWithRefreshToken(tx *storage.Connection, hashKey, token string, forUpdate bool) (*User, *RefreshToken, *Session, error) {
 	refreshToken := &RefreshToken{}
 
+	query, args := "token = ?", []interface{}{hashRefreshToken(hashKey, token)}
+	if hashKey != "" && len(token) != refreshTokenHashLength {
+		// a stored hash must not be usable as a refresh token, so
+		// only tokens that cannot be a hash are looked up in
+		// plaintext
+		query, args = "(token = ? or token = ?)", append(args, token)
+	}
+
 	if forUpdate {
 		// pop does not provide us with a way to execute FOR UPDATE
 		// queries which lock the rows affected by the query from
 		// being accessed by any other transaction that also uses FOR
 		// UPDATE
-		if err := tx.RawQuery(fmt.Sprintf("SELECT * FROM %q WHERE token = ? LIMIT 1 FOR UPDATE SKIP LOCKED;", refreshToken.TableName()), token).First(refreshToken); err != nil {
+		if err := tx.RawQuery(fmt.Sprintf("SELECT * FROM %q WHERE %s LIMIT 1 FOR UPDATE SKIP LOCKED;", refreshToken.TableName(), query), args...).First(refreshToken); err != nil {
 			if errors.Cause(err) == sql.ErrNoRows {
 				return nil, nil, nil, RefreshTokenNotFoundError{}
 			}
@@ -463,13 +528,19 @@ func FindUserWithRefreshToken(tx *storage.Connection, token string, forUpdate bo
 	}
 
 	// once the rows are locked (if forUpdate was true), we can query again using pop
-	if err := tx.Where("token = ?", token).First(refreshToken); err != nil {
+	if err := tx.Where(query, args...).First(refreshToken); err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, nil, nil, RefreshTokenNotFoundError{}
 		}
 		return nil, nil, nil, errors.Wrap(err, "error finding refresh token")
 	}
 
+	if hashKey != "" && refreshToken.TokenHash == token {
+		if err := refreshToken.upgradeTokenHash(tx, hashKey); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "error hashing refresh token")
+		}
+	}
+
 	user, err := FindUserByID(tx, refreshToken.UserID)
 	if err != nil {
 		return nil, nil, nil, err
@@ -627,6 +698,33 @@ func (u *User) Ban(tx *storage.Connection, duration time.Duration) error {
 	return tx.UpdateOnly(u, "banned_until")
 }
 
+// SetMFARequired sets whether an admin requires the user to use MFA. Clearing
+// it also clears when the requirement started, so that requiring MFA again
+// starts a new enrollment grace period.
+func (u *User) SetMFARequired(tx *storage.Connection, required bool) error {
+	u.MFARequired = required
+	if !required {
+		u.MFARequiredSince = nil
+	} else if u.MFARequiredSince == nil {
+		now := time.Now()
+		u.MFARequiredSince = &now
+	}
+	return tx.UpdateOnly(u, "mfa_required", "mfa_required_since")
+}
+
+// StartMFAEnforcement records when the user first became required to use
+// MFA, which starts the enrollment grace period. It does nothing if it was
+// already recorded.
+func (u *User) StartMFAEnforcement(tx *storage.Connection) error {
+	if u.MFARequiredSince != nil {
+		return nil
+	}
+
+	now := time.Now()
+	u.MFARequiredSince = &now
+	return tx.UpdateOnly(u, "mfa_required_since")
+}
+
 // IsBanned checks if a user is banned or not
 func (u *User) IsBanned() bool {
 	if u.BannedUntil == nil {
@@ -43,7 +43,7 @@ func TestUser(t *testing.T) {
 }
 
 func (ts *UserTestSuite) TestUpdateAppMetadata() {
-	u, err := NewUser("", "", "", "", nil)
+	u, err := NewUser(crypto.DefaultPasswordHashParams, "", "", "", "", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), u.UpdateAppMetaData(ts.db, make(map[string]interface{})))
 
@@ -62,7 +62,7 @@ func (ts *UserTestSuite) TestUpdateAppMetadata() {
 }
 
 func (ts *UserTestSuite) TestUpdateUserMetadata() {
-	u, err := NewUser("", "", "", "", nil)
+	u, err := NewUser(crypto.DefaultPasswordHashParams, "", "", "", "", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), u.UpdateUserMetaData(ts.db, make(map[string]interface{})))
 
@@ -148,10 +148,10 @@ func (ts *UserTestSuite) TestFindUserByRecoveryToken() {
 
 func (ts *UserTestSuite) TestFindUserWithRefreshToken() {
 	u := ts.createUser()
-	r, err := GrantAuthenticatedUser(ts.db, u, GrantParams{})
+	r, err := GrantAuthenticatedUser(ts.db, "refreshtokenhashkey", u, GrantParams{})
 	require.NoError(ts.T(), err)
 
-	n, nr, s, err := FindUserWithRefreshToken(ts.db, r.Token, true /* forUpdate */)
+	n, nr, s, err := FindUserWithRefreshToken(ts.db, "refreshtokenhashkey", r.Token, true /* forUpdate */)
 	require.NoError(ts.T(), err)
 	require.Equal(ts.T(), r.ID, nr.ID)
 	require.Equal(ts.T(), u.ID, n.ID)
@@ -184,7 +184,7 @@ func (ts *UserTestSuite) createUser() *User {
 }
 
 func (ts *UserTestSuite) createUserWithEmail(email string) *User {
-	user, err := NewUser("", email, "secret", "test", nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "", email, "secret", "test", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(user))
 
@@ -199,7 +199,7 @@ func (ts *UserTestSuite) createUserWithEmail(email string) *User {
 }
 
 func (ts *UserTestSuite) TestRemoveUnconfirmedIdentities() {
-	user, err := NewUser("+29382983298", "someone@example.com", "abcdefgh", "authenticated", nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "+29382983298", "someone@example.com", "abcdefgh", "authenticated", nil)
 	require.NoError(ts.T(), err)
 
 	user.AppMetaData = map[string]interface{}{
@@ -252,7 +252,7 @@ func (ts *UserTestSuite) TestRemoveUnconfirmedIdentities() {
 }
 
 func (ts *UserTestSuite) TestConfirmEmailChange() {
-	user, err := NewUser("", "test@example.com", "", "authenticated", nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "", "test@example.com", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(user))
 
@@ -280,7 +280,7 @@ func (ts *UserTestSuite) TestConfirmEmailChange() {
 }
 
 func (ts *UserTestSuite) TestConfirmPhoneChange() {
-	user, err := NewUser("123456789", "", "", "authenticated", nil)
+	user, err := NewUser(crypto.DefaultPasswordHashParams, "123456789", "", "", "authenticated", nil)
 	require.NoError(ts.T(), err)
 	require.NoError(ts.T(), ts.db.Create(user))
 
@@ -306,3 +306,29 @@ func (ts *UserTestSuite) TestConfirmPhoneChange() {
 	require.NotNil(ts.T(), identity.IdentityData)
 	require.Equal(ts.T(), identity.IdentityData["phone"], "987654321")
 }
+
+func (ts *UserTestSuite) TestSetMFARequired() {
+	u := ts.createUser()
+	require.Nil(ts.T(), u.MFARequiredSince)
+
+	require.NoError(ts.T(), u.SetMFARequired(ts.db, true))
+	since := u.MFARequiredSince
+	require.NotNil(ts.T(), since)
+
+	// requiring MFA again keeps the grace period
+	require.NoError(ts.T(), u.SetMFARequired(ts.db, true))
+	require.Equal(ts.T(), since, u.MFARequiredSince)
+
+	// clearing the requirement resets the grace period
+	require.NoError(ts.T(), u.SetMFARequired(ts.db, false))
+	require.Nil(ts.T(), u.MFARequiredSince)
+
+	u, err := FindUserByID(ts.db, u.ID)
+	require.NoError(ts.T(), err)
+	require.False(ts.T(), u.MFARequired)
+	require.Nil(ts.T(), u.MFARequiredSince)
+
+	require.NoError(ts.T(), u.SetMFARequired(ts.db, true))
+	require.NotNil(ts.T(), u.MFARequiredSince)
+	require.True(ts.T(), u.MFARequiredSince.After(*since))
+}
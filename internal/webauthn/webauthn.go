@@ -0,0 +1,471 @@
+// Package webauthn implements the relying party side of the WebAuthn
+// registration and authentication ceremonies, see
+// https://www.w3.org/TR/webauthn-2/#sctn-rp-operations.
+//
+// The attestation objects, authenticator data and COSE keys are parsed and
+// verified with github.com/go-webauthn/webauthn. The relying party requests
+// "none" attestation, attestation statements that authenticators send anyway
+// are verified but not checked against trust anchors. The options and
+// responses are encoded as JSON with
+// binary values in base64url, as expected by
+// PublicKeyCredential.parseCreationOptionsFromJSON() and produced by
+// PublicKeyCredential.toJSON() in browsers.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	// PublicKeyCredentialType is the only supported credential type.
+	PublicKeyCredentialType = "public-key"
+
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+
+	ResidentKeyRequired  = "required"
+	ResidentKeyPreferred = "preferred"
+
+	AttestationNone = "none"
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// COSE algorithm identifiers of the supported public keys, see
+// https://www.iana.org/assignments/cose/cose.xhtml#algorithms.
+const (
+	AlgES256 = int64(webauthncose.AlgES256)
+	AlgEdDSA = int64(webauthncose.AlgEdDSA)
+	AlgRS256 = int64(webauthncose.AlgRS256)
+)
+
+var (
+	ErrInvalidCredential = errors.New("webauthn: invalid credential")
+	ErrInvalidChallenge  = errors.New("webauthn: challenge mismatch")
+	ErrInvalidOrigin     = errors.New("webauthn: origin not allowed")
+	ErrInvalidRPID       = errors.New("webauthn: relying party ID mismatch")
+	ErrUserNotPresent    = errors.New("webauthn: user not present")
+	ErrUserNotVerified   = errors.New("webauthn: user not verified")
+
+	// ErrInvalidAttestation is returned when the attestation statement an
+	// authenticator sent with a new credential is invalid.
+	ErrInvalidAttestation = errors.New("webauthn: invalid attestation")
+
+	errUnsupportedPublicKey = errors.New("webauthn: unsupported public key")
+	errInvalidSignature     = errors.New("webauthn: invalid signature")
+
+	// ErrSignCountMismatch is returned when the signature counter of the
+	// authenticator did not increase, which indicates that the credential
+	// was cloned.
+	ErrSignCountMismatch = errors.New("webauthn: signature counter did not increase")
+)
+
+// RelyingParty verifies the credentials of a relying party.
+type RelyingParty struct {
+	// ID is the relying party ID, the domain credentials are scoped to.
+	ID string
+
+	// Name is shown to the user when a credential is created.
+	Name string
+
+	// Origins are the origins allowed to use the credentials.
+	Origins []string
+
+	// Timeout is the time the user has to complete a ceremony.
+	Timeout time.Duration
+}
+
+// Credential is a public key credential registered with the relying party.
+type Credential struct {
+	ID         []byte   `json:"id"`
+	PublicKey  []byte   `json:"public_key"`
+	SignCount  uint32   `json:"sign_count"`
+	AAGUID     []byte   `json:"aaguid,omitempty"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// RelyingPartyEntity describes the relying party to the authenticator.
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity describes the user account a credential is created for. The ID
+// is returned as the user handle on authentication.
+type UserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// CredentialParameters describes a supported public key algorithm.
+type CredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// CredentialDescriptor identifies a credential.
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// AuthenticatorSelection describes the authenticators allowed to create a
+// credential.
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// PublicKeyCredentialCreationOptions are the options passed to
+// navigator.credentials.create() to register a credential.
+type PublicKeyCredentialCreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// PublicKeyCredentialRequestOptions are the options passed to
+// navigator.credentials.get() to authenticate with a credential.
+type PublicKeyCredentialRequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout,omitempty"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// AuthenticatorAttestationResponse is the response of the authenticator to
+// navigator.credentials.create().
+type AuthenticatorAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON"`
+	AttestationObject string   `json:"attestationObject"`
+	Transports        []string `json:"transports,omitempty"`
+}
+
+// CredentialCreationResponse is the credential returned by
+// navigator.credentials.create().
+type CredentialCreationResponse struct {
+	ID       string                           `json:"id"`
+	RawID    string                           `json:"rawId"`
+	Type     string                           `json:"type"`
+	Response AuthenticatorAttestationResponse `json:"response"`
+}
+
+// AuthenticatorAssertionResponse is the response of the authenticator to
+// navigator.credentials.get().
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// CredentialAssertionResponse is the credential returned by
+// navigator.credentials.get().
+type CredentialAssertionResponse struct {
+	ID       string                         `json:"id"`
+	RawID    string                         `json:"rawId"`
+	Type     string                         `json:"type"`
+	Response AuthenticatorAssertionResponse `json:"response"`
+}
+
+// CredentialID returns the ID of the credential used for the assertion.
+func (r *CredentialAssertionResponse) CredentialID() ([]byte, error) {
+	return decodeBase64URL(r.RawID)
+}
+
+// UserHandle returns the user handle of the credential used for the
+// assertion. It is only returned by discoverable credentials.
+func (r *CredentialAssertionResponse) UserHandle() ([]byte, error) {
+	return decodeBase64URL(r.Response.UserHandle)
+}
+
+type collectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// CreationOptions returns the options to register a credential for user.
+// Credentials in exclude cannot be registered again.
+func (rp *RelyingParty) CreationOptions(challenge string, user UserEntity, exclude []*Credential, residentKey string) *PublicKeyCredentialCreationOptions {
+	return &PublicKeyCredentialCreationOptions{
+		Challenge: challenge,
+		RP: RelyingPartyEntity{
+			ID:   rp.ID,
+			Name: rp.Name,
+		},
+		User: user,
+		PubKeyCredParams: []CredentialParameters{
+			{Type: PublicKeyCredentialType, Alg: AlgES256},
+			{Type: PublicKeyCredentialType, Alg: AlgEdDSA},
+			{Type: PublicKeyCredentialType, Alg: AlgRS256},
+		},
+		Timeout:            rp.Timeout.Milliseconds(),
+		ExcludeCredentials: credentialDescriptors(exclude),
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        residentKey,
+			RequireResidentKey: residentKey == ResidentKeyRequired,
+			UserVerification:   UserVerificationPreferred,
+		},
+		Attestation: AttestationNone,
+	}
+}
+
+// RequestOptions returns the options to authenticate with one of the allowed
+// credentials. If no credentials are allowed, any discoverable credential of
+// the relying party can be used.
+func (rp *RelyingParty) RequestOptions(challenge string, allow []*Credential, userVerification string) *PublicKeyCredentialRequestOptions {
+	return &PublicKeyCredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          rp.Timeout.Milliseconds(),
+		RPID:             rp.ID,
+		AllowCredentials: credentialDescriptors(allow),
+		UserVerification: userVerification,
+	}
+}
+
+// VerifyRegistration verifies the response to the creation options with
+// challenge and returns the registered credential.
+func (rp *RelyingParty) VerifyRegistration(challenge string, response *CredentialCreationResponse, requireUserVerification bool) (*Credential, error) {
+	if response.Type != PublicKeyCredentialType {
+		return nil, ErrInvalidCredential
+	}
+
+	if err := rp.verifyClientData(response.Response.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	clientDataJSON, err := decodeBase64URL(response.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidCredential
+	}
+
+	attestationObject, err := decodeBase64URL(response.Response.AttestationObject)
+	if err != nil {
+		return nil, ErrInvalidCredential
+	}
+
+	attestationResponse := &protocol.AuthenticatorAttestationResponse{
+		AuthenticatorResponse: protocol.AuthenticatorResponse{
+			ClientDataJSON: clientDataJSON,
+		},
+		AttestationObject: attestationObject,
+	}
+	parsed, err := attestationResponse.Parse()
+	if err != nil {
+		return nil, ErrInvalidCredential
+	}
+
+	authData := &parsed.AttestationObject.AuthData
+	if err := rp.verifyAuthenticatorData(authData, requireUserVerification); err != nil {
+		return nil, err
+	}
+
+	rawID, err := decodeBase64URL(response.RawID)
+	if err != nil || !bytes.Equal(rawID, authData.AttData.CredentialID) {
+		return nil, ErrInvalidCredential
+	}
+
+	if _, err := parsePublicKey(authData.AttData.CredentialPublicKey); err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := parsed.AttestationObject.Verify(rp.ID, clientDataHash[:], requireUserVerification); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAttestation, err)
+	}
+
+	return &Credential{
+		ID:         authData.AttData.CredentialID,
+		PublicKey:  authData.AttData.CredentialPublicKey,
+		SignCount:  authData.Counter,
+		AAGUID:     authData.AttData.AAGUID,
+		Transports: response.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion verifies the response to the request options with
+// challenge, signed with credential. It returns the new signature counter
+// of the credential.
+func (rp *RelyingParty) VerifyAssertion(challenge string, credential *Credential, response *CredentialAssertionResponse, requireUserVerification bool) (uint32, error) {
+	if response.Type != PublicKeyCredentialType {
+		return 0, ErrInvalidCredential
+	}
+
+	rawID, err := response.CredentialID()
+	if err != nil || !bytes.Equal(rawID, credential.ID) {
+		return 0, ErrInvalidCredential
+	}
+
+	clientDataJSON, err := decodeBase64URL(response.Response.ClientDataJSON)
+	if err != nil {
+		return 0, ErrInvalidCredential
+	}
+
+	if err := rp.verifyClientData(response.Response.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return 0, err
+	}
+
+	rawAuthData, err := decodeBase64URL(response.Response.AuthenticatorData)
+	if err != nil {
+		return 0, ErrInvalidCredential
+	}
+
+	authData := &protocol.AuthenticatorData{}
+	if err := authData.Unmarshal(rawAuthData); err != nil {
+		return 0, ErrInvalidCredential
+	}
+
+	if err := rp.verifyAuthenticatorData(authData, requireUserVerification); err != nil {
+		return 0, err
+	}
+
+	signature, err := decodeBase64URL(response.Response.Signature)
+	if err != nil {
+		return 0, ErrInvalidCredential
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedData := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if err := verifySignature(credential.PublicKey, signedData, signature); err != nil {
+		return 0, err
+	}
+
+	// authenticators without a signature counter always return 0
+	if (authData.Counter != 0 || credential.SignCount != 0) && authData.Counter <= credential.SignCount {
+		return 0, ErrSignCountMismatch
+	}
+
+	return authData.Counter, nil
+}
+
+func (rp *RelyingParty) verifyClientData(encoded, ceremony, challenge string) error {
+	data, err := decodeBase64URL(encoded)
+	if err != nil {
+		return ErrInvalidCredential
+	}
+
+	clientData := &collectedClientData{}
+	if err := json.Unmarshal(data, clientData); err != nil {
+		return ErrInvalidCredential
+	}
+
+	if clientData.Type != ceremony {
+		return ErrInvalidCredential
+	}
+
+	if subtle.ConstantTimeCompare([]byte(strings.TrimRight(clientData.Challenge, "=")), []byte(strings.TrimRight(challenge, "="))) != 1 {
+		return ErrInvalidChallenge
+	}
+
+	if clientData.CrossOrigin {
+		return ErrInvalidOrigin
+	}
+
+	for _, origin := range rp.Origins {
+		if clientData.Origin == origin {
+			return nil
+		}
+	}
+
+	return ErrInvalidOrigin
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(authData *protocol.AuthenticatorData, requireUserVerification bool) error {
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(authData.RPIDHash, rpIDHash[:]) != 1 {
+		return ErrInvalidRPID
+	}
+
+	if !authData.Flags.UserPresent() {
+		return ErrUserNotPresent
+	}
+
+	if requireUserVerification && !authData.Flags.UserVerified() {
+		return ErrUserNotVerified
+	}
+
+	return nil
+}
+
+// parsePublicKey parses a COSE_Key encoded public key. Only ES256, EdDSA
+// (Ed25519) and RS256 keys are supported.
+func parsePublicKey(data []byte) (interface{}, error) {
+	key, err := webauthncose.ParsePublicKey(data)
+	if err != nil {
+		return nil, errUnsupportedPublicKey
+	}
+
+	switch key := key.(type) {
+	case webauthncose.EC2PublicKeyData:
+		if key.Algorithm == AlgES256 && key.Curve == int64(webauthncose.P256) && len(key.XCoord) == 32 && len(key.YCoord) == 32 {
+			return key, nil
+		}
+	case webauthncose.OKPPublicKeyData:
+		if key.Algorithm == AlgEdDSA && len(key.XCoord) == ed25519.PublicKeySize {
+			return key, nil
+		}
+	case webauthncose.RSAPublicKeyData:
+		// the signature verification expects a 3 byte exponent
+		if key.Algorithm == AlgRS256 && len(key.Modulus) >= 256 && len(key.Exponent) == 3 {
+			return key, nil
+		}
+	}
+
+	return nil, errUnsupportedPublicKey
+}
+
+// verifySignature verifies signature over data with a COSE_Key encoded
+// public key.
+func verifySignature(publicKey []byte, data, signature []byte) error {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	if valid, err := webauthncose.VerifySignature(key, data, signature); err != nil || !valid {
+		return errInvalidSignature
+	}
+
+	return nil
+}
+
+func credentialDescriptors(credentials []*Credential) []CredentialDescriptor {
+	descriptors := make([]CredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, CredentialDescriptor{
+			Type:       PublicKeyCredentialType,
+			ID:         base64.RawURLEncoding.EncodeToString(credential.ID),
+			Transports: credential.Transports,
+		})
+	}
+	return descriptors
+}
+
+// decodeBase64URL decodes base64url with or without padding.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
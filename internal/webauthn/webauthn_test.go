@@ -0,0 +1,151 @@
+package webauthn_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/supabase/gotrue/internal/webauthn"
+	"github.com/supabase/gotrue/internal/webauthn/webauthntest"
+)
+
+var relyingParty = &webauthn.RelyingParty{
+	ID:      "example.com",
+	Name:    "Example",
+	Origins: []string{"https://example.com"},
+	Timeout: 5 * time.Minute,
+}
+
+var user = webauthn.UserEntity{
+	ID:          base64.RawURLEncoding.EncodeToString([]byte("user-id")),
+	Name:        "test@example.com",
+	DisplayName: "test@example.com",
+}
+
+func register(t *testing.T, authenticator *webauthntest.Authenticator) *webauthn.Credential {
+	options := relyingParty.CreationOptions("registration-challenge", user, nil, webauthn.ResidentKeyPreferred)
+	credential, err := relyingParty.VerifyRegistration(options.Challenge, authenticator.Create(options), true)
+	require.NoError(t, err)
+	return credential
+}
+
+func TestRegistration(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator("https://example.com")
+	credential := register(t, authenticator)
+
+	require.Equal(t, authenticator.CredentialID, credential.ID)
+	require.Equal(t, []byte("user-id"), authenticator.UserHandle)
+	require.Equal(t, uint32(0), credential.SignCount)
+	require.Equal(t, []string{"internal"}, credential.Transports)
+}
+
+func TestRegistrationErrors(t *testing.T) {
+	cases := []struct {
+		desc          string
+		relyingParty  *webauthn.RelyingParty
+		origin        string
+		challenge     string
+		userVerified  bool
+		requireUV     bool
+		expectedError error
+	}{
+		{
+			desc:          "Challenge mismatch",
+			relyingParty:  relyingParty,
+			origin:        "https://example.com",
+			challenge:     "other-challenge",
+			userVerified:  true,
+			expectedError: webauthn.ErrInvalidChallenge,
+		},
+		{
+			desc:          "Origin not allowed",
+			relyingParty:  relyingParty,
+			origin:        "https://evil.example",
+			challenge:     "registration-challenge",
+			userVerified:  true,
+			expectedError: webauthn.ErrInvalidOrigin,
+		},
+		{
+			desc: "Relying party ID mismatch",
+			relyingParty: &webauthn.RelyingParty{
+				ID:      "other.example.com",
+				Origins: relyingParty.Origins,
+			},
+			origin:        "https://example.com",
+			challenge:     "registration-challenge",
+			userVerified:  true,
+			expectedError: webauthn.ErrInvalidRPID,
+		},
+		{
+			desc:          "User not verified",
+			relyingParty:  relyingParty,
+			origin:        "https://example.com",
+			challenge:     "registration-challenge",
+			userVerified:  false,
+			requireUV:     true,
+			expectedError: webauthn.ErrUserNotVerified,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			authenticator := webauthntest.NewAuthenticator(c.origin)
+			authenticator.UserVerified = c.userVerified
+
+			options := relyingParty.CreationOptions("registration-challenge", user, nil, webauthn.ResidentKeyPreferred)
+			_, err := c.relyingParty.VerifyRegistration(c.challenge, authenticator.Create(options), c.requireUV)
+			require.ErrorIs(t, err, c.expectedError)
+		})
+	}
+}
+
+func TestAssertion(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator("https://example.com")
+	credential := register(t, authenticator)
+
+	options := relyingParty.RequestOptions("assertion-challenge", []*webauthn.Credential{credential}, webauthn.UserVerificationRequired)
+	require.Len(t, options.AllowCredentials, 1)
+
+	response := authenticator.Get(options)
+
+	userHandle, err := response.UserHandle()
+	require.NoError(t, err)
+	require.Equal(t, []byte("user-id"), userHandle)
+
+	signCount, err := relyingParty.VerifyAssertion(options.Challenge, credential, response, true)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), signCount)
+
+	// replaying the assertion of a cloned authenticator fails
+	credential.SignCount = signCount
+	_, err = relyingParty.VerifyAssertion(options.Challenge, credential, response, true)
+	require.ErrorIs(t, err, webauthn.ErrSignCountMismatch)
+}
+
+func TestAssertionErrors(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator("https://example.com")
+	credential := register(t, authenticator)
+
+	options := relyingParty.RequestOptions("assertion-challenge", nil, webauthn.UserVerificationRequired)
+
+	_, err := relyingParty.VerifyAssertion("other-challenge", credential, authenticator.Get(options), true)
+	require.ErrorIs(t, err, webauthn.ErrInvalidChallenge)
+
+	other := register(t, webauthntest.NewAuthenticator("https://example.com"))
+	_, err = relyingParty.VerifyAssertion(options.Challenge, other, authenticator.Get(options), true)
+	require.ErrorIs(t, err, webauthn.ErrInvalidCredential)
+
+	// the signature is made with a different key
+	other.ID = credential.ID
+	_, err = relyingParty.VerifyAssertion(options.Challenge, other, authenticator.Get(options), true)
+	require.Error(t, err)
+
+	authenticator.UserVerified = false
+	_, err = relyingParty.VerifyAssertion(options.Challenge, credential, authenticator.Get(options), true)
+	require.ErrorIs(t, err, webauthn.ErrUserNotVerified)
+
+	// user presence is enough for a second factor
+	_, err = relyingParty.VerifyAssertion(options.Challenge, credential, authenticator.Get(options), false)
+	require.NoError(t, err)
+}
@@ -0,0 +1,167 @@
+// Package webauthntest provides a software authenticator for testing the
+// WebAuthn ceremonies.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/supabase/gotrue/internal/webauthn"
+)
+
+// Authenticator is a software authenticator holding a single ES256
+// credential, which is registered with Create.
+type Authenticator struct {
+	// Origin is the origin the browser reports in the client data.
+	Origin string
+
+	// UserVerified controls whether the authenticator verifies the
+	// user, e.g. with a PIN or biometrics.
+	UserVerified bool
+
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+type attestationObject struct {
+	Format       string                 `cbor:"fmt"`
+	AttStatement map[string]interface{} `cbor:"attStmt"`
+	AuthData     []byte                 `cbor:"authData"`
+}
+
+// NewAuthenticator returns an authenticator that verifies the user and is
+// used from origin.
+func NewAuthenticator(origin string) *Authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err.Error()) // rand should never fail
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		panic(err.Error())
+	}
+
+	return &Authenticator{
+		Origin:       origin,
+		UserVerified: true,
+		CredentialID: credentialID,
+		key:          key,
+	}
+}
+
+// Create answers navigator.credentials.create() with the options.
+func (a *Authenticator) Create(options *webauthn.PublicKeyCredentialCreationOptions) *webauthn.CredentialCreationResponse {
+	userHandle, err := base64.RawURLEncoding.DecodeString(options.User.ID)
+	if err != nil {
+		panic(err.Error())
+	}
+	a.UserHandle = userHandle
+
+	authData := a.authenticatorData(options.RP.ID, 0x40)
+	authData = binary.BigEndian.AppendUint16(append(authData, make([]byte, 16)...), uint16(len(a.CredentialID)))
+	authData = append(append(authData, a.CredentialID...), a.publicKey()...)
+
+	attestation := marshalCBOR(&attestationObject{
+		Format:       webauthn.AttestationNone,
+		AttStatement: map[string]interface{}{},
+		AuthData:     authData,
+	})
+
+	return &webauthn.CredentialCreationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		RawID: base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		Type:  webauthn.PublicKeyCredentialType,
+		Response: webauthn.AuthenticatorAttestationResponse{
+			ClientDataJSON:    a.clientData("webauthn.create", options.Challenge),
+			AttestationObject: base64.RawURLEncoding.EncodeToString(attestation),
+			Transports:        []string{"internal"},
+		},
+	}
+}
+
+// Get answers navigator.credentials.get() with the options.
+func (a *Authenticator) Get(options *webauthn.PublicKeyCredentialRequestOptions) *webauthn.CredentialAssertionResponse {
+	a.SignCount += 1
+
+	authData := a.authenticatorData(options.RPID, 0)
+	clientData := a.clientData("webauthn.get", options.Challenge)
+
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(clientData)
+	if err != nil {
+		panic(err.Error())
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return &webauthn.CredentialAssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		RawID: base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		Type:  webauthn.PublicKeyCredentialType,
+		Response: webauthn.AuthenticatorAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+			Signature:         base64.RawURLEncoding.EncodeToString(signature),
+			UserHandle:        base64.RawURLEncoding.EncodeToString(a.UserHandle),
+		},
+	}
+}
+
+func (a *Authenticator) authenticatorData(rpID string, flags byte) []byte {
+	flags |= 0x01
+	if a.UserVerified {
+		flags |= 0x04
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.SignCount)
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) string {
+	clientData, err := json.Marshal(map[string]interface{}{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(clientData)
+}
+
+// publicKey returns the COSE_Key encoded public key of the credential.
+func (a *Authenticator) publicKey() []byte {
+	return marshalCBOR(&webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: webauthn.AlgES256,
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+}
+
+func marshalCBOR(v interface{}) []byte {
+	data, err := webauthncbor.Marshal(v)
+	if err != nil {
+		panic(err.Error())
+	}
+	return data
+}
@@ -0,0 +1,41 @@
+-- adds tables for acting as an OAuth 2.0 authorization server for third-party clients
+
+create table if not exists {{ index .Options "Namespace" }}.oauth_clients (
+	id uuid not null,
+	name text not null,
+	client_secret_hash text null,
+	redirect_uris jsonb not null,
+	scopes jsonb not null,
+	created_at timestamptz null,
+	updated_at timestamptz null,
+	primary key (id)
+);
+
+comment on table {{ index .Options "Namespace" }}.oauth_clients is 'Auth: Manages third-party OAuth clients.';
+
+create table if not exists {{ index .Options "Namespace" }}.oauth_consents (
+	id uuid not null,
+	user_id uuid not null,
+	client_id uuid not null,
+	scopes text not null,
+	created_at timestamptz null,
+	updated_at timestamptz null,
+	primary key (id),
+	unique (user_id, client_id),
+	foreign key (user_id) references {{ index .Options "Namespace" }}.users (id) on delete cascade,
+	foreign key (client_id) references {{ index .Options "Namespace" }}.oauth_clients (id) on delete cascade
+);
+
+comment on table {{ index .Options "Namespace" }}.oauth_consents is 'Auth: Stores the scopes users have consented to share with third-party OAuth clients.';
+
+alter table {{ index .Options "Namespace" }}.flow_state
+	add column if not exists oauth_client_id uuid null references {{ index .Options "Namespace" }}.oauth_clients (id) on delete cascade,
+	add column if not exists redirect_uri text null,
+	add column if not exists scopes text null,
+	add column if not exists oauth_state text null;
+
+alter table {{ index .Options "Namespace" }}.sessions
+	add column if not exists oauth_client_id uuid null references {{ index .Options "Namespace" }}.oauth_clients (id) on delete cascade,
+	add column if not exists scopes text null;
+
+create index if not exists sessions_oauth_client_id_idx on {{ index .Options "Namespace" }}.sessions (oauth_client_id);
@@ -0,0 +1,15 @@
+-- adds service accounts for the client_credentials grant
+
+create table if not exists {{ index .Options "Namespace" }}.service_accounts (
+	id uuid not null,
+	name text not null,
+	client_secret_hash text not null,
+	role text not null,
+	scopes jsonb not null,
+	expires_at timestamptz null,
+	created_at timestamptz null,
+	updated_at timestamptz null,
+	primary key (id)
+);
+
+comment on table {{ index .Options "Namespace" }}.service_accounts is 'Auth: Manages service accounts that authenticate with the client_credentials grant.';
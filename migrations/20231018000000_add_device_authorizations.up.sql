@@ -0,0 +1,20 @@
+-- adds device authorizations for the OAuth 2.0 Device Authorization Grant (RFC 8628)
+
+create table if not exists {{ index .Options "Namespace" }}.device_authorizations (
+	id uuid not null,
+	device_code_hash text not null,
+	user_code text not null,
+	status text not null,
+	user_id uuid null,
+	last_polled_at timestamptz null,
+	created_at timestamptz null,
+	updated_at timestamptz null,
+	primary key (id),
+	unique (device_code_hash),
+	unique (user_code),
+	foreign key (user_id) references {{ index .Options "Namespace" }}.users (id) on delete cascade
+);
+
+create index if not exists device_authorizations_created_at_idx on {{ index .Options "Namespace" }}.device_authorizations (created_at desc);
+
+comment on table {{ index .Options "Namespace" }}.device_authorizations is 'Auth: Stores pending device authorization requests.';
@@ -0,0 +1,10 @@
+-- adds the client and the latest refresh of a session so that users can review their sessions
+
+alter table {{ index .Options "Namespace" }}.sessions
+  add column if not exists user_agent text null,
+  add column if not exists ip inet null,
+  add column if not exists refreshed_at timestamptz null;
+
+comment on column {{ index .Options "Namespace" }}.sessions.user_agent is 'Auth: User agent of the client the session was issued or last refreshed for.';
+comment on column {{ index .Options "Namespace" }}.sessions.ip is 'Auth: IP address of the client the session was issued or last refreshed for.';
+comment on column {{ index .Options "Namespace" }}.sessions.refreshed_at is 'Auth: Time of the latest refresh of the session.';
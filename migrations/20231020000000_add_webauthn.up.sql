@@ -0,0 +1,19 @@
+-- adds webauthn factors and passkey login
+
+alter table {{ index .Options "Namespace" }}.mfa_factors add column if not exists web_authn_credential jsonb null;
+alter table {{ index .Options "Namespace" }}.mfa_challenges add column if not exists web_authn_challenge text null;
+
+comment on column {{ index .Options "Namespace" }}.mfa_factors.web_authn_credential is 'Auth: Public key credential of a webauthn factor.';
+comment on column {{ index .Options "Namespace" }}.mfa_challenges.web_authn_challenge is 'Auth: Challenge signed by the authenticator of a webauthn factor.';
+
+create table if not exists {{ index .Options "Namespace" }}.passkey_challenges (
+	id uuid not null,
+	challenge text not null,
+	ip_address inet not null,
+	created_at timestamptz not null,
+	primary key (id)
+);
+
+create index if not exists passkey_challenges_created_at_idx on {{ index .Options "Namespace" }}.passkey_challenges (created_at desc);
+
+comment on table {{ index .Options "Namespace" }}.passkey_challenges is 'Auth: Stores challenges for signing in with a passkey.';
@@ -0,0 +1,9 @@
+-- adds phone factors, whose codes are sent by SMS
+
+alter type factor_type add value if not exists 'phone';
+
+alter table {{ index .Options "Namespace" }}.mfa_factors add column if not exists phone text null;
+alter table {{ index .Options "Namespace" }}.mfa_challenges add column if not exists otp_code text null;
+
+comment on column {{ index .Options "Namespace" }}.mfa_factors.phone is 'Auth: Phone number codes of a phone factor are sent to.';
+comment on column {{ index .Options "Namespace" }}.mfa_challenges.otp_code is 'Auth: Hash of the code sent for a phone factor challenge.';
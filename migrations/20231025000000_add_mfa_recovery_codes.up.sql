@@ -0,0 +1,15 @@
+-- adds one-time recovery codes for MFA
+
+create table if not exists {{ index .Options "Namespace" }}.mfa_recovery_codes (
+	id uuid not null,
+	user_id uuid not null,
+	code_hash text not null,
+	created_at timestamptz not null,
+	used_at timestamptz null,
+	primary key (id),
+	constraint mfa_recovery_codes_user_id_fkey foreign key (user_id) references {{ index .Options "Namespace" }}.users(id) on delete cascade
+);
+
+create index if not exists mfa_recovery_codes_user_id_code_hash_idx on {{ index .Options "Namespace" }}.mfa_recovery_codes (user_id, code_hash);
+
+comment on table {{ index .Options "Namespace" }}.mfa_recovery_codes is 'Auth: Stores hashed one-time recovery codes that can be redeemed in place of a factor challenge.';
@@ -0,0 +1,7 @@
+-- adds TOTP replay protection and failed verification lockout to MFA factors
+
+alter table {{ index .Options "Namespace" }}.mfa_factors add column if not exists last_totp_counter bigint null;
+alter table {{ index .Options "Namespace" }}.mfa_factors add column if not exists failed_verification_attempts integer not null default 0;
+alter table {{ index .Options "Namespace" }}.mfa_factors add column if not exists locked_until timestamptz null;
+
+comment on column {{ index .Options "Namespace" }}.mfa_factors.last_totp_counter is 'Auth: Time step of the last accepted TOTP code, codes from this or earlier time steps are rejected.';
@@ -0,0 +1,7 @@
+-- adds the per-user MFA requirement and the start of its enrollment grace period
+
+alter table {{ index .Options "Namespace" }}.users add column if not exists mfa_required boolean not null default false;
+alter table {{ index .Options "Namespace" }}.users add column if not exists mfa_required_since timestamptz null;
+
+comment on column {{ index .Options "Namespace" }}.users.mfa_required is 'Auth: Set by an admin to require the user to use MFA, regardless of the configured MFA enforcement policy.';
+comment on column {{ index .Options "Namespace" }}.users.mfa_required_since is 'Auth: When the user first became required to use MFA, the enrollment grace period starts from here.';
@@ -0,0 +1,20 @@
+-- adds trusted devices, which skip the MFA challenge when signing in
+
+create table if not exists {{ index .Options "Namespace" }}.mfa_trusted_devices (
+	id uuid not null,
+	user_id uuid not null,
+	factor_id uuid not null,
+	token_hash text not null,
+	user_agent text null,
+	ip text null,
+	created_at timestamptz not null,
+	expires_at timestamptz not null,
+	last_used_at timestamptz null,
+	primary key (id),
+	constraint mfa_trusted_devices_user_id_fkey foreign key (user_id) references {{ index .Options "Namespace" }}.users(id) on delete cascade,
+	constraint mfa_trusted_devices_factor_id_fkey foreign key (factor_id) references {{ index .Options "Namespace" }}.mfa_factors(id) on delete cascade
+);
+
+create index if not exists mfa_trusted_devices_user_id_token_hash_idx on {{ index .Options "Namespace" }}.mfa_trusted_devices (user_id, token_hash);
+
+comment on table {{ index .Options "Namespace" }}.mfa_trusted_devices is 'Auth: Stores devices trusted after verifying a factor, whose hashed device token upgrades sign-ins to aal2.';
@@ -0,0 +1,5 @@
+-- allows requiring MFA for the users of an SSO provider
+
+alter table {{ index .Options "Namespace" }}.sso_providers add column if not exists mfa_required boolean not null default false;
+
+comment on column {{ index .Options "Namespace" }}.sso_providers.mfa_required is 'Auth: Requires the users of the provider to use MFA, for identity providers that do not enforce it themselves.';
@@ -0,0 +1,5 @@
+-- tracks the time the user last actively authenticated in a session
+
+alter table {{ index .Options "Namespace" }}.sessions add column if not exists auth_time timestamptz null;
+
+comment on column {{ index .Options "Namespace" }}.sessions.auth_time is 'Auth: Time the user last actively authenticated in the session, by signing in or by reauthenticating. Sessions without it fall back to created_at.';
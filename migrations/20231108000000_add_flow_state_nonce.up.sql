@@ -0,0 +1,3 @@
+-- stores the nonce of authorization requests from third-party OAuth clients, which is echoed in the ID token
+
+alter table {{ index .Options "Namespace" }}.flow_state add column if not exists nonce text null;
@@ -0,0 +1,6 @@
+-- adds the failed recovery code redemption lockout to users
+
+alter table {{ index .Options "Namespace" }}.users add column if not exists failed_recovery_code_attempts integer not null default 0;
+alter table {{ index .Options "Namespace" }}.users add column if not exists recovery_codes_locked_until timestamptz null;
+
+comment on column {{ index .Options "Namespace" }}.users.recovery_codes_locked_until is 'Auth: Recovery codes cannot be redeemed until then, after too many consecutive invalid codes.';
@@ -0,0 +1,5 @@
+-- counts failed password reauthentication attempts in a session
+
+alter table {{ index .Options "Namespace" }}.sessions add column if not exists failed_reauthentication_attempts integer not null default 0;
+
+comment on column {{ index .Options "Namespace" }}.sessions.failed_reauthentication_attempts is 'Auth: Consecutive wrong passwords entered to reauthenticate in the session, the session is signed out after too many.';
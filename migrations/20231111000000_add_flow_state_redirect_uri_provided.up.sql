@@ -0,0 +1,3 @@
+-- records whether the authorization request from a third-party OAuth client included the redirect_uri, which then has to be repeated in the token request
+
+alter table {{ index .Options "Namespace" }}.flow_state add column if not exists redirect_uri_provided boolean not null default true;